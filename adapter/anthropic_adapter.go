@@ -1,9 +1,12 @@
 package adapter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/dvictor357/blaze"
 )
@@ -14,10 +17,15 @@ import (
 
 // Tool represents a callable function that can be registered with an adapter
 type Tool struct {
-	Name        string
-	Description string
-	InputSchema any
-	Handler     func(json.RawMessage) (any, error)
+	Name            string
+	Description     string
+	InputSchema     any
+	Handler         func(json.RawMessage) (any, error)
+	ProgressHandler func(json.RawMessage, ProgressFunc) (any, error)    // optional, see NewProgressTool
+	ContextHandler  func(context.Context, json.RawMessage) (any, error) // optional, see NewContextTool
+	SideEffect      bool                                                // mutates state or reaches the network; skipped in dry-run mode
+	ReadOnly        func(input json.RawMessage) bool                    // optional; true for calls of a SideEffect tool that only read, which dry-run mode still runs
+	Methods         []string                                            // HTTP methods ExecHandler accepts, e.g. GET for reads; empty = any, or POST for SideEffect tools
 }
 
 // NewTool creates a new Tool with the given parameters
@@ -44,15 +52,99 @@ type AnthropicContentBlock struct {
 	Text      string         `json:"text,omitempty"`
 	ToolUseID string         `json:"tool_use_id,omitempty"`
 	Content   string         `json:"content,omitempty"`
+	IsError   bool           `json:"is_error,omitempty"`
+
+	// Source holds the payload of an image block
+	Source *AnthropicImageSource `json:"source,omitempty"`
+
+	// ContentBlocks holds tool_result content sent as an array of blocks.
+	// Content then contains the joined text of those blocks.
+	ContentBlocks []AnthropicContentBlock `json:"-"`
+}
+
+// AnthropicImageSource is the source of an image content block
+type AnthropicImageSource struct {
+	Type      string `json:"type"` // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// UnmarshalJSON accepts content as either a string or an array of blocks
+func (b *AnthropicContentBlock) UnmarshalJSON(data []byte) error {
+	type plain AnthropicContentBlock
+	var raw struct {
+		plain
+		Content json.RawMessage `json:"content,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*b = AnthropicContentBlock(raw.plain)
+
+	content := strings.TrimSpace(string(raw.Content))
+	switch {
+	case content == "" || content == "null":
+		return nil
+	case content[0] == '[':
+		if err := json.Unmarshal(raw.Content, &b.ContentBlocks); err != nil {
+			return err
+		}
+		var texts []string
+		for _, block := range b.ContentBlocks {
+			if block.Type == "text" {
+				texts = append(texts, block.Text)
+			}
+		}
+		b.Content = strings.Join(texts, "\n")
+		return nil
+	default:
+		return json.Unmarshal(raw.Content, &b.Content)
+	}
+}
+
+// MarshalJSON writes content as an array when the block holds ContentBlocks
+func (b AnthropicContentBlock) MarshalJSON() ([]byte, error) {
+	type plain AnthropicContentBlock
+	if b.ContentBlocks == nil {
+		return json.Marshal(plain(b))
+	}
+	return json.Marshal(struct {
+		plain
+		Content []AnthropicContentBlock `json:"content"`
+	}{plain(b), b.ContentBlocks})
 }
 
 // AnthropicChatRequest represents an Anthropic chat completion request
 type AnthropicChatRequest struct {
-	Model     string             `json:"model"`
-	Messages  []AnthropicMessage `json:"messages"`
-	MaxTokens int                `json:"max_tokens,omitempty"`
-	Tools     []map[string]any   `json:"tools,omitempty"`
-	Stream    bool               `json:"stream,omitempty"`
+	Model         string             `json:"model"`
+	System        any                `json:"system,omitempty"` // Can be string or []ContentBlock
+	Messages      []AnthropicMessage `json:"messages"`
+	MaxTokens     int                `json:"max_tokens,omitempty"`
+	Tools         []map[string]any   `json:"tools,omitempty"`
+	Stream        bool               `json:"stream,omitempty"`
+	SessionID     string             `json:"session_id,omitempty"`
+	ToolCallDepth int                `json:"tool_call_depth,omitempty"`
+	Timezone      string             `json:"timezone,omitempty"`
+	DryRun        bool               `json:"dry_run,omitempty"`
+}
+
+// SystemPrompt returns the top-level system prompt as plain text, joining
+// text blocks when it was sent as an array
+func (r AnthropicChatRequest) SystemPrompt() string {
+	if r.System == nil {
+		return ""
+	}
+	if str, ok := r.System.(string); ok {
+		return str
+	}
+	var parts []string
+	for _, block := range parseContentBlocks(r.System) {
+		if block.Type == "text" {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
 }
 
 // AnthropicChatResponse represents an Anthropic chat completion response
@@ -90,71 +182,85 @@ type AnthropicStreamEvent struct {
 // AnthropicAdapter creates a Blaze handler that processes Anthropic/Claude-format
 // requests and executes registered tools
 func AnthropicAdapter(tools ...Tool) blaze.HandlerFunc {
-	toolMap := make(map[string]Tool)
-	for _, tool := range tools {
-		toolMap[tool.Name] = tool
-	}
+	return AnthropicAdapterWithOptions(tools)
+}
 
-	return func(ctx *blaze.Context) error {
-		var req AnthropicChatRequest
-		if err := ctx.BindJSON(&req); err != nil {
-			return ctx.JSON(400, map[string]any{
-				"type": "error",
-				"error": map[string]any{
-					"type":    "invalid_request_error",
-					"message": fmt.Sprintf("Invalid request: %v", err),
-				},
-			})
-		}
+// AnthropicAdapterWithOptions is like AnthropicAdapter but accepts Options
+// such as WithToolLimits
+func AnthropicAdapterWithOptions(tools []Tool, opts ...Option) blaze.HandlerFunc {
+	return NewAdapter(anthropicSpec, tools, opts...)
+}
 
+// anthropicSpec describes the Anthropic Messages API for NewAdapter. Tool
+// calls are the tool_use blocks of the last message, which must be from the
+// user; their results are returned as tool_result blocks.
+var anthropicSpec = ProviderSpec[AnthropicChatRequest, AnthropicMessage, AnthropicContentBlock, AnthropicContentBlock]{
+	Validate: func(req AnthropicChatRequest) string {
 		if len(req.Messages) == 0 {
-			return ctx.JSON(400, map[string]any{
-				"type": "error",
-				"error": map[string]any{
-					"type":    "invalid_request_error",
-					"message": "Messages array is required",
-				},
-			})
+			return "Messages array is required"
 		}
-
-		// Get the last message
-		lastMessage := req.Messages[len(req.Messages)-1]
-		if lastMessage.Role != "user" {
-			return ctx.JSON(400, map[string]any{
-				"type": "error",
-				"error": map[string]any{
-					"type":    "invalid_request_error",
-					"message": "Last message must be from user",
-				},
-			})
+		if req.Messages[len(req.Messages)-1].Role != "user" {
+			return "Last message must be from user"
 		}
-
-		// Parse content blocks from the message
-		contentBlocks := parseContentBlocks(lastMessage.Content)
-
-		// Find and execute tool_use blocks
-		var toolResults []AnthropicContentBlock
-		hasToolUse := false
-
-		for _, block := range contentBlocks {
+		return ""
+	},
+	Strict: func(req AnthropicChatRequest) string {
+		if req.MaxTokens <= 0 {
+			return "max_tokens: Field required"
+		}
+		return ""
+	},
+	BadRequest: func(ctx *blaze.Context, message string) error {
+		return ctx.JSON(400, AnthropicErrorEnvelope(blaze.NewHTTPError(400, message)))
+	},
+	Info: func(req AnthropicChatRequest) RequestInfo {
+		return RequestInfo{
+			Model:         req.Model,
+			Stream:        req.Stream,
+			SessionID:     req.SessionID,
+			ToolCallDepth: req.ToolCallDepth,
+			Timezone:      req.Timezone,
+			DryRun:        req.DryRun,
+		}
+	},
+	Messages: func(req *AnthropicChatRequest) *[]AnthropicMessage {
+		return &req.Messages
+	},
+	ToolCalls: func(req AnthropicChatRequest) []AnthropicContentBlock {
+		var calls []AnthropicContentBlock
+		for _, block := range parseContentBlocks(req.Messages[len(req.Messages)-1].Content) {
 			if block.Type == "tool_use" {
-				hasToolUse = true
-				result := executeToolBlock(block, toolMap)
-				toolResults = append(toolResults, result)
+				calls = append(calls, block)
 			}
 		}
-
-		// If no tool_use blocks, return info about available tools
-		if !hasToolUse {
-			return handleNoToolUse(ctx, req, tools)
-		}
-
-		// Return response based on streaming preference
-		if req.Stream {
-			return streamAnthropicResponse(ctx, req.Model, toolResults)
+		return calls
+	},
+	Call: func(block AnthropicContentBlock) (string, json.RawMessage) {
+		inputBytes, _ := json.Marshal(block.Input)
+		return block.Name, inputBytes
+	},
+	Result: func(block AnthropicContentBlock, outcome ToolOutcome) AnthropicContentBlock {
+		return AnthropicContentBlock{
+			Type:      "tool_result",
+			ToolUseID: block.ID,
+			Content:   outcome.Content,
+			IsError:   outcome.IsError,
 		}
-		return sendAnthropicResponse(ctx, req.Model, toolResults)
-	}
+	},
+	Text: func(text string) AnthropicContentBlock {
+		return AnthropicContentBlock{Type: "text", Text: text}
+	},
+	Conversation: func(req AnthropicChatRequest, results []AnthropicContentBlock) []AnthropicMessage {
+		// Anthropic expects tool_result blocks in a user turn
+		return append(req.Messages, AnthropicMessage{Role: "user", Content: results})
+	},
+	NoToolCalls: handleNoToolUse,
+	Send: func(ctx *blaze.Context, req AnthropicChatRequest, results []AnthropicContentBlock) error {
+		return sendAnthropicResponse(ctx, req.Model, results)
+	},
+	Stream: func(ctx *blaze.Context, req AnthropicChatRequest, run func(ProgressFunc) []AnthropicContentBlock, heartbeat time.Duration) error {
+		return streamAnthropicResponse(ctx, req.Model, run, heartbeat)
+	},
 }
 
 // parseContentBlocks parses the content field which can be string or []ContentBlock
@@ -178,36 +284,6 @@ func parseContentBlocks(content any) []AnthropicContentBlock {
 	return blocks
 }
 
-// executeToolBlock executes a single tool_use block and returns the result
-func executeToolBlock(block AnthropicContentBlock, toolMap map[string]Tool) AnthropicContentBlock {
-	tool, exists := toolMap[block.Name]
-	if !exists {
-		return AnthropicContentBlock{
-			Type:      "tool_result",
-			ToolUseID: block.ID,
-			Content:   fmt.Sprintf(`{"error": "Tool '%s' not found"}`, block.Name),
-		}
-	}
-
-	// Execute the tool handler
-	inputBytes, _ := json.Marshal(block.Input)
-	result, err := tool.Handler(inputBytes)
-	if err != nil {
-		return AnthropicContentBlock{
-			Type:      "tool_result",
-			ToolUseID: block.ID,
-			Content:   fmt.Sprintf(`{"error": "%v"}`, err),
-		}
-	}
-
-	resultBytes, _ := json.Marshal(result)
-	return AnthropicContentBlock{
-		Type:      "tool_result",
-		ToolUseID: block.ID,
-		Content:   string(resultBytes),
-	}
-}
-
 // handleNoToolUse returns a response when no tool_use blocks are present
 func handleNoToolUse(ctx *blaze.Context, req AnthropicChatRequest, tools []Tool) error {
 	// Get text from last user message
@@ -217,6 +293,12 @@ func handleNoToolUse(ctx *blaze.Context, req AnthropicChatRequest, tools []Tool)
 		userText = str
 	}
 
+	text := fmt.Sprintf("I have access to %d tools. To use them, include tool_use blocks in your request.%s Your message: %s", len(tools), systemNote(req.SystemPrompt()), userText)
+	stopReason := "end_turn"
+	if capped, ok := capTokens(text, req.MaxTokens); ok {
+		text, stopReason = capped, "max_tokens"
+	}
+
 	response := AnthropicChatResponse{
 		ID:    generateAnthropicID("msg"),
 		Type:  "message",
@@ -225,20 +307,42 @@ func handleNoToolUse(ctx *blaze.Context, req AnthropicChatRequest, tools []Tool)
 		Content: []AnthropicContentBlock{
 			{
 				Type: "text",
-				Text: fmt.Sprintf("I have access to %d tools. To use them, include tool_use blocks in your request. Your message: %s", len(tools), userText),
+				Text: text,
 			},
 		},
-		StopReason:   "end_turn",
+		StopReason:   stopReason,
 		StopSequence: nil,
 		Usage: AnthropicUsage{
 			InputTokens:  10,
-			OutputTokens: 20,
+			OutputTokens: approxTokens(text),
 		},
 	}
 
 	return ctx.JSON(200, response)
 }
 
+// charsPerToken approximates the length of a token in characters
+const charsPerToken = 4
+
+// approxTokens estimates the number of tokens in text
+func approxTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// capTokens cuts text to about maxTokens tokens, reporting whether it was
+// cut. A maxTokens of 0 or less means no limit.
+func capTokens(text string, maxTokens int) (string, bool) {
+	limit := maxTokens * charsPerToken
+	if maxTokens <= 0 || len(text) <= limit {
+		return text, false
+	}
+	// Don't split a UTF-8 sequence
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit], true
+}
+
 // sendAnthropicResponse sends a non-streaming response
 func sendAnthropicResponse(ctx *blaze.Context, model string, toolResults []AnthropicContentBlock) error {
 	response := AnthropicChatResponse{
@@ -258,8 +362,10 @@ func sendAnthropicResponse(ctx *blaze.Context, model string, toolResults []Anthr
 	return ctx.JSON(200, response)
 }
 
-// streamAnthropicResponse sends a streaming SSE response
-func streamAnthropicResponse(ctx *blaze.Context, model string, toolResults []AnthropicContentBlock) error {
+// streamAnthropicResponse sends a streaming SSE response. Tools run inside
+// the stream so their progress messages are sent as text deltas before the
+// results.
+func streamAnthropicResponse(ctx *blaze.Context, model string, run func(ProgressFunc) []AnthropicContentBlock, heartbeat time.Duration) error {
 	ch := make(chan any)
 
 	go func() {
@@ -289,14 +395,32 @@ func streamAnthropicResponse(ctx *blaze.Context, model string, toolResults []Ant
 			},
 		}
 
+		// Relay progress on the processing block while the tools run
+		progress, stop := guardProgress(func(msg string) {
+			ch <- AnthropicStreamEvent{
+				Type:  "content_block_delta",
+				Index: 0,
+				Delta: map[string]any{
+					"type": "text",
+					"text": msg,
+				},
+			}
+		})
+		toolResults := run(progress)
+		stop()
+
 		// Send each tool result as a delta
 		for i, result := range toolResults {
+			text := result.Content
+			if result.Type == "text" {
+				text = result.Text
+			}
 			ch <- AnthropicStreamEvent{
 				Type:  "content_block_delta",
 				Index: i,
 				Delta: map[string]any{
 					"type": result.Type,
-					"text": result.Content,
+					"text": text,
 				},
 			}
 		}
@@ -308,7 +432,7 @@ func streamAnthropicResponse(ctx *blaze.Context, model string, toolResults []Ant
 		}
 	}()
 
-	return ctx.StreamJSON(ch)
+	return streamJSON(ctx, ch, heartbeat)
 }
 
 // ============================================================================
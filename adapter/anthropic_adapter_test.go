@@ -232,6 +232,91 @@ func TestAnthropicAdapter_LastMessageNotUser(t *testing.T) {
 	}
 }
 
+// TestAnthropicAdapter_StrictMaxTokens tests that strict validation rejects
+// requests without max_tokens, and that lenient mode accepts them
+func TestAnthropicAdapter_StrictMaxTokens(t *testing.T) {
+	body := `{"model":"claude-3-5-sonnet","messages":[{"role":"user","content":"Hello"}]}`
+	post := func(h blaze.HandlerFunc) *httptest.ResponseRecorder {
+		e := blaze.New()
+		e.POST("/chat", h)
+		req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post(AnthropicAdapterWithOptions(nil, WithStrictValidation()))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("strict: expected status 400, got %d", rec.Code)
+	}
+	var errResp struct {
+		Type  string `json:"type"`
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if errResp.Type != "error" || errResp.Error.Type != "invalid_request_error" {
+		t.Errorf("Unexpected error shape: %s", rec.Body.String())
+	}
+	if !strings.Contains(errResp.Error.Message, "max_tokens") {
+		t.Errorf("Expected message to name max_tokens, got %q", errResp.Error.Message)
+	}
+
+	if rec := post(AnthropicAdapter()); rec.Code != http.StatusOK {
+		t.Errorf("lenient: expected status 200, got %d", rec.Code)
+	}
+}
+
+// TestAnthropicAdapter_StrictJSON tests that an engine with StrictJSON
+// rejects misspelled request fields
+func TestAnthropicAdapter_StrictJSON(t *testing.T) {
+	e := blaze.New()
+	e.StrictJSON()
+	e.POST("/chat", AnthropicAdapter())
+
+	body := `{"model":"claude-3-5-sonnet","maxTokens":100,"messages":[{"role":"user","content":"Hello"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "maxTokens") {
+		t.Errorf("Expected 400 naming maxTokens, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestAnthropicAdapter_MaxTokensCap tests that generated text is cut to
+// about max_tokens tokens
+func TestAnthropicAdapter_MaxTokensCap(t *testing.T) {
+	e := blaze.New()
+	e.POST("/chat", AnthropicAdapterWithOptions(nil, WithStrictValidation()))
+
+	body := `{"model":"claude-3-5-sonnet","max_tokens":5,"messages":[{"role":"user","content":"Hello"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp AnthropicChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.StopReason != "max_tokens" {
+		t.Errorf("Expected stop_reason max_tokens, got %q", resp.StopReason)
+	}
+	if n := len(resp.Content[0].Text); n > 5*charsPerToken {
+		t.Errorf("Expected at most %d characters, got %d", 5*charsPerToken, n)
+	}
+}
+
 // TestAnthropicAdapter_MultipleToolCalls tests executing multiple tools in one request
 func TestAnthropicAdapter_MultipleToolCalls(t *testing.T) {
 	addTool := NewTool("add", "Add numbers", nil,
@@ -351,3 +436,113 @@ func TestParseContentBlocks(t *testing.T) {
 		t.Errorf("Expected 2 blocks, got %d", len(blocks))
 	}
 }
+
+// TestAnthropicAdapter_SystemPrompt tests that the system prompt is parsed and forwarded
+func TestAnthropicAdapter_SystemPrompt(t *testing.T) {
+	var forwarded AnthropicChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(AnthropicChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+
+	reqBody := map[string]any{
+		"model":  "claude-3-5-sonnet",
+		"system": []map[string]any{{"type": "text", "text": "You are terse."}},
+		"messages": []map[string]any{
+			{"role": "user", "content": "Hello"},
+		},
+	}
+
+	rec := postJSON(t, AnthropicAdapterWithOptions(nil, WithFallback(backend)), reqBody)
+	if !strings.Contains(rec.Body.String(), "forwarded") {
+		t.Fatalf("Expected request to reach the fallback, got: %s", rec.Body.String())
+	}
+	if forwarded.SystemPrompt() != "You are terse." {
+		t.Errorf("Expected system prompt to be forwarded, got %q", forwarded.SystemPrompt())
+	}
+
+	// Without a fallback the info response acknowledges the system prompt
+	reqBody["system"] = "You are terse."
+	var resp AnthropicChatResponse
+	json.Unmarshal(postJSON(t, AnthropicAdapter(), reqBody).Body.Bytes(), &resp)
+	if !strings.Contains(resp.Content[0].Text, "System prompt received") {
+		t.Errorf("Expected system prompt acknowledgement, got: %s", resp.Content[0].Text)
+	}
+}
+
+// TestAnthropicContentBlock_ContentRoundTrip tests string and array tool_result content
+func TestAnthropicContentBlock_ContentRoundTrip(t *testing.T) {
+	// String content
+	var block AnthropicContentBlock
+	if err := json.Unmarshal([]byte(`{"type":"tool_result","tool_use_id":"toolu_1","content":"42"}`), &block); err != nil {
+		t.Fatalf("Failed to parse string content: %v", err)
+	}
+	if block.Content != "42" || block.ContentBlocks != nil {
+		t.Errorf("Expected string content, got %q / %v", block.Content, block.ContentBlocks)
+	}
+	out, _ := json.Marshal(block)
+	if string(out) != `{"type":"tool_result","tool_use_id":"toolu_1","content":"42"}` {
+		t.Errorf("Unexpected string round trip: %s", out)
+	}
+
+	// Array content
+	arrayJSON := `{"type":"tool_result","tool_use_id":"toolu_2","is_error":true,"content":[{"type":"text","text":"first"},{"type":"text","text":"second"}]}`
+	block = AnthropicContentBlock{}
+	if err := json.Unmarshal([]byte(arrayJSON), &block); err != nil {
+		t.Fatalf("Failed to parse array content: %v", err)
+	}
+	if len(block.ContentBlocks) != 2 || block.ContentBlocks[1].Text != "second" {
+		t.Fatalf("Expected 2 structured blocks, got %+v", block.ContentBlocks)
+	}
+	if block.Content != "first\nsecond" || !block.IsError {
+		t.Errorf("Expected joined text and is_error, got %q / %v", block.Content, block.IsError)
+	}
+	out, _ = json.Marshal(block)
+	if string(out) != arrayJSON {
+		t.Errorf("Unexpected array round trip:\n got %s\nwant %s", out, arrayJSON)
+	}
+
+	// Array content inside a message survives parseContentBlocks
+	var msg AnthropicMessage
+	json.Unmarshal([]byte(`{"role":"user","content":[`+arrayJSON+`]}`), &msg)
+	blocks := parseContentBlocks(msg.Content)
+	if len(blocks) != 1 || len(blocks[0].ContentBlocks) != 2 {
+		t.Errorf("Expected tool_result array content to survive parsing, got %+v", blocks)
+	}
+}
+
+// TestAnthropicAdapter_ImageBlock tests that image blocks survive parsing and forwarding
+func TestAnthropicAdapter_ImageBlock(t *testing.T) {
+	var forwarded AnthropicChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(AnthropicChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+
+	reqBody := map[string]any{
+		"model": "claude-3-5-sonnet",
+		"messages": []map[string]any{
+			{"role": "user", "content": []map[string]any{
+				{"type": "text", "text": "What is in this image?"},
+				{"type": "image", "source": map[string]any{
+					"type": "base64", "media_type": "image/png", "data": "iVBORw0KGgo=",
+				}},
+			}},
+		},
+	}
+
+	rec := postJSON(t, AnthropicAdapterWithOptions(nil, WithFallback(backend)), reqBody)
+	if !strings.Contains(rec.Body.String(), "forwarded") {
+		t.Fatalf("Expected request to reach the fallback, got: %s", rec.Body.String())
+	}
+
+	last := forwarded.Messages[len(forwarded.Messages)-1]
+	blocks := parseContentBlocks(last.Content)
+	if len(blocks) != 2 || blocks[1].Type != "image" || blocks[1].Source == nil {
+		t.Fatalf("Expected image block to be preserved, got %+v", blocks)
+	}
+	src := blocks[1].Source
+	if src.Type != "base64" || src.MediaType != "image/png" || src.Data != "iVBORw0KGgo=" {
+		t.Errorf("Image source corrupted: %+v", src)
+	}
+}
@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Batch Tool Execution
+// ============================================================================
+
+// batchConcurrency bounds how many calls of one batch run at the same time
+const batchConcurrency = 8
+
+// BatchRequest is the body accepted by BatchExecHandler
+type BatchRequest struct {
+	Calls []BatchCall `json:"calls"`
+}
+
+// BatchCall is a single tool call in a batch
+type BatchCall struct {
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// BatchResponse is the body returned by BatchExecHandler
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult is the outcome of one call; exactly one of Result and Error is
+// set. Kind classifies the error.
+type BatchResult struct {
+	Name   string          `json:"name"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Kind   ErrorKind       `json:"kind,omitempty"`
+}
+
+// BatchExecHandler creates a handler that runs several tools in one request
+// without the chat wrapper. The body is {"calls": [{"name", "input"}]}; calls
+// run concurrently and results are returned in input order. A failing or
+// unknown tool produces an error for that call only.
+func BatchExecHandler(tools ...Tool) blaze.HandlerFunc {
+	return BatchExecHandlerWithOptions(tools)
+}
+
+// BatchExecHandlerWithOptions is like BatchExecHandler but accepts Options.
+// With WithDedupe, duplicate calls in a batch run the tool once, even while
+// the first is still in flight.
+func BatchExecHandlerWithOptions(tools []Tool, opts ...Option) blaze.HandlerFunc {
+	cfg := newConfig(opts)
+	toolMap := mustBuildToolMap(tools)
+
+	return func(ctx *blaze.Context) error {
+		var req BatchRequest
+		if err := ctx.BindJSON(&req); err != nil {
+			return ctx.Errorf(400, "invalid request: %v", err)
+		}
+
+		exec := newExecutor(ctx, toolMap, cfg)
+		results := make([]BatchResult, len(req.Calls))
+		sem := make(chan struct{}, batchConcurrency)
+		var wg sync.WaitGroup
+
+		for i, call := range req.Calls {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = runBatchCall(exec, call)
+			}()
+		}
+		wg.Wait()
+		exec.logRequest()
+
+		return ctx.JSON(200, BatchResponse{Results: results})
+	}
+}
+
+// runBatchCall executes one call and converts its outcome to a BatchResult
+func runBatchCall(exec *executor, call BatchCall) BatchResult {
+	input := call.Input
+	if len(input) == 0 || string(input) == "null" {
+		input = json.RawMessage("{}")
+	}
+
+	outcome := exec.execute(call.Name, input)
+	if !outcome.IsError {
+		return BatchResult{Name: call.Name, Result: json.RawMessage(outcome.Content)}
+	}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.Unmarshal([]byte(outcome.Content), &body)
+	return BatchResult{Name: call.Name, Error: body.Error, Kind: outcome.Kind}
+}
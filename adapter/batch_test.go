@@ -0,0 +1,136 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestBatchExecHandler_Mixed tests a batch with successful, failing and unknown tools
+func TestBatchExecHandler_Mixed(t *testing.T) {
+	echo := NewTool("echo", "Echo", objectSchema, func(input json.RawMessage) (any, error) {
+		var v map[string]any
+		json.Unmarshal(input, &v)
+		return v, nil
+	})
+	fail := NewTool("fail", "Fail", objectSchema, func(input json.RawMessage) (any, error) {
+		return nil, fmt.Errorf("boom")
+	})
+
+	rec := postJSON(t, BatchExecHandler(echo, fail), map[string]any{
+		"calls": []map[string]any{
+			{"name": "echo", "input": map[string]any{"x": 1}},
+			{"name": "missing", "input": map[string]any{}},
+			{"name": "fail"},
+		},
+	})
+	if rec.Code != 200 {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(resp.Results))
+	}
+	if string(resp.Results[0].Result) != `{"x":1}` || resp.Results[0].Error != "" {
+		t.Errorf("Unexpected echo result: %+v", resp.Results[0])
+	}
+	if resp.Results[1].Name != "missing" || resp.Results[1].Error != "Tool 'missing' not found" {
+		t.Errorf("Expected not-found error for unknown tool, got %+v", resp.Results[1])
+	}
+	if resp.Results[2].Error != "boom" || resp.Results[2].Result != nil {
+		t.Errorf("Expected handler error, got %+v", resp.Results[2])
+	}
+	if resp.Results[0].Kind != "" || resp.Results[1].Kind != KindNotFound || resp.Results[2].Kind != KindInternal {
+		t.Errorf("Unexpected error kinds: %q, %q, %q", resp.Results[0].Kind, resp.Results[1].Kind, resp.Results[2].Kind)
+	}
+
+	// Malformed body is a whole-request failure
+	if rec := postJSON(t, BatchExecHandler(echo), "not an object"); rec.Code != 400 {
+		t.Errorf("Expected 400 for malformed body, got %d", rec.Code)
+	}
+}
+
+// TestBatchExecHandler_Order tests that results keep input order regardless of completion order
+func TestBatchExecHandler_Order(t *testing.T) {
+	sleep := NewTool("sleep", "Sleep", objectSchema, func(input json.RawMessage) (any, error) {
+		var in struct {
+			Ms int `json:"ms"`
+		}
+		json.Unmarshal(input, &in)
+		time.Sleep(time.Duration(in.Ms) * time.Millisecond)
+		return in.Ms, nil
+	})
+
+	delays := []int{40, 5, 25, 0, 15, 30, 10, 20, 35, 1}
+	var calls []map[string]any
+	for _, ms := range delays {
+		calls = append(calls, map[string]any{"name": "sleep", "input": map[string]any{"ms": ms}})
+	}
+
+	var resp BatchResponse
+	json.Unmarshal(postJSON(t, BatchExecHandler(sleep), map[string]any{"calls": calls}).Body.Bytes(), &resp)
+	if len(resp.Results) != len(delays) {
+		t.Fatalf("Expected %d results, got %d", len(delays), len(resp.Results))
+	}
+	for i, ms := range delays {
+		if string(resp.Results[i].Result) != fmt.Sprint(ms) {
+			t.Errorf("Result %d: expected %d, got %s", i, ms, resp.Results[i].Result)
+		}
+	}
+}
+
+// TestBatchExecHandler_InvalidRequest tests that a malformed body gets a 400
+// in the error envelope
+func TestBatchExecHandler_InvalidRequest(t *testing.T) {
+	rec := postJSON(t, BatchExecHandler(), map[string]any{"calls": "not a list"})
+	var body struct {
+		Error blaze.HTTPError `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != 400 || body.Error.Code != 400 || !strings.HasPrefix(body.Error.Message, "invalid request:") {
+		t.Errorf("Expected a 400 error envelope, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestBatchExecHandler_Dedupe tests that duplicate calls running concurrently
+// in one batch execute once with WithDedupe
+func TestBatchExecHandler_Dedupe(t *testing.T) {
+	var calls atomic.Int32
+	fetch := NewTool("fetch", "Fetch", objectSchema, func(input json.RawMessage) (any, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return "page", nil
+	})
+
+	batch := []map[string]any{
+		{"name": "fetch", "input": map[string]any{"url": "https://example.com"}},
+		{"name": "fetch", "input": map[string]any{"url": "https://example.com"}},
+		{"name": "fetch", "input": map[string]any{"url": "https://example.com"}},
+		{"name": "fetch", "input": map[string]any{"url": "https://example.org"}},
+	}
+	var resp BatchResponse
+	rec := postJSON(t, BatchExecHandlerWithOptions([]Tool{fetch}, WithDedupe()), map[string]any{"calls": batch})
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected 2 executions, got %d", got)
+	}
+	if len(resp.Results) != len(batch) {
+		t.Fatalf("Expected %d results, got %d", len(batch), len(resp.Results))
+	}
+	for i, r := range resp.Results {
+		if string(r.Result) != `"page"` {
+			t.Errorf("Result %d: unexpected %+v", i, r)
+		}
+	}
+}
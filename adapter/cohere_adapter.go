@@ -0,0 +1,341 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Cohere Types
+// ============================================================================
+
+// CohereToolDef represents a Cohere tool definition
+type CohereToolDef struct {
+	Name                 string                         `json:"name"`
+	Description          string                         `json:"description"`
+	ParameterDefinitions map[string]CohereParameterSpec `json:"parameter_definitions,omitempty"`
+}
+
+// CohereParameterSpec describes one tool parameter. Type uses Python-style
+// names such as "str", "int" or "List[str]".
+type CohereParameterSpec struct {
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// CohereToolCall represents a tool call made by the model
+type CohereToolCall struct {
+	Name       string         `json:"name"`
+	Parameters map[string]any `json:"parameters"`
+}
+
+// CohereToolResult pairs a tool call with its outputs
+type CohereToolResult struct {
+	Call    CohereToolCall   `json:"call"`
+	Outputs []map[string]any `json:"outputs"`
+}
+
+// CohereMessage represents an entry of chat_history
+type CohereMessage struct {
+	Role        string             `json:"role"` // USER, CHATBOT, SYSTEM or TOOL
+	Message     string             `json:"message,omitempty"`
+	ToolCalls   []CohereToolCall   `json:"tool_calls,omitempty"`
+	ToolResults []CohereToolResult `json:"tool_results,omitempty"`
+}
+
+// CohereChatRequest represents a Cohere chat request
+type CohereChatRequest struct {
+	Model       string             `json:"model,omitempty"`
+	Message     string             `json:"message"`
+	ChatHistory []CohereMessage    `json:"chat_history,omitempty"`
+	Preamble    string             `json:"preamble,omitempty"`
+	Tools       []CohereToolDef    `json:"tools,omitempty"`
+	ToolResults []CohereToolResult `json:"tool_results,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+// SystemPrompt returns the preamble followed by any SYSTEM messages of the
+// chat history
+func (r CohereChatRequest) SystemPrompt() string {
+	var parts []string
+	if r.Preamble != "" {
+		parts = append(parts, r.Preamble)
+	}
+	for _, msg := range r.ChatHistory {
+		if msg.Role == "SYSTEM" {
+			parts = append(parts, msg.Message)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// CohereChatResponse represents a Cohere chat response
+type CohereChatResponse struct {
+	ResponseID   string             `json:"response_id"`
+	GenerationID string             `json:"generation_id"`
+	Text         string             `json:"text"`
+	ToolResults  []CohereToolResult `json:"tool_results,omitempty"`
+	FinishReason string             `json:"finish_reason"`
+}
+
+// CohereStreamEvent represents an event of a streaming response
+type CohereStreamEvent struct {
+	EventType    string              `json:"event_type"` // stream-start, text-generation or stream-end
+	GenerationID string              `json:"generation_id,omitempty"`
+	Text         string              `json:"text,omitempty"`
+	FinishReason string              `json:"finish_reason,omitempty"`
+	Response     *CohereChatResponse `json:"response,omitempty"`
+}
+
+// ============================================================================
+// Format Conversion
+// ============================================================================
+
+// ToCohere converts a Tool to Cohere tool definition format. The top-level
+// properties of the input schema become parameter definitions; nested
+// schemas are reduced to their type.
+func (t Tool) ToCohere() CohereToolDef {
+	def := CohereToolDef{Name: t.Name, Description: t.Description}
+
+	b, err := json.Marshal(t.InputSchema)
+	if err != nil {
+		return def
+	}
+	var schema struct {
+		Properties map[string]cohereSchemaProperty `json:"properties"`
+		Required   []string                        `json:"required"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil || len(schema.Properties) == 0 {
+		return def
+	}
+
+	def.ParameterDefinitions = make(map[string]CohereParameterSpec, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		def.ParameterDefinitions[name] = CohereParameterSpec{
+			Description: prop.Description,
+			Type:        prop.cohereType(),
+			Required:    slices.Contains(schema.Required, name),
+		}
+	}
+	return def
+}
+
+// cohereSchemaProperty is the part of a JSON Schema property ToCohere reads
+type cohereSchemaProperty struct {
+	Type        any                   `json:"type"` // a name, or a list of names
+	Description string                `json:"description"`
+	Items       *cohereSchemaProperty `json:"items"`
+}
+
+// cohereType maps the property's JSON Schema type to Cohere's Python-style
+// type name. Of a list of types, the first that is not "null" is used.
+func (p cohereSchemaProperty) cohereType() string {
+	typ, _ := p.Type.(string)
+	if types, ok := p.Type.([]any); ok {
+		for _, t := range types {
+			if s, _ := t.(string); s != "null" {
+				typ = s
+				break
+			}
+		}
+	}
+
+	switch typ {
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		if p.Items != nil && p.Items.Type != nil {
+			return "List[" + p.Items.cohereType() + "]"
+		}
+		return "List"
+	case "object":
+		return "Dict"
+	}
+	return "str"
+}
+
+// ============================================================================
+// Cohere Adapter
+// ============================================================================
+
+// CohereAdapter creates a Blaze handler that processes Cohere-format chat
+// requests and executes registered tools. Tool calls are read from the last
+// CHATBOT entry of chat_history, and answered with tool_results.
+func CohereAdapter(tools ...Tool) blaze.HandlerFunc {
+	return CohereAdapterWithOptions(tools)
+}
+
+// CohereAdapterWithOptions is like CohereAdapter but accepts Options such
+// as WithToolLimits
+func CohereAdapterWithOptions(tools []Tool, opts ...Option) blaze.HandlerFunc {
+	return NewAdapter(cohereSpec, tools, opts...)
+}
+
+// cohereSpec describes Cohere's chat API for NewAdapter. Cohere requests
+// have no session or depth fields; the X-Session-ID and X-Tool-Call-Depth
+// headers apply instead.
+var cohereSpec = ProviderSpec[CohereChatRequest, CohereMessage, CohereToolCall, CohereToolResult]{
+	Validate: func(req CohereChatRequest) string {
+		if req.Message == "" && len(req.ChatHistory) == 0 {
+			return "message or chat_history is required"
+		}
+		return ""
+	},
+	BadRequest: func(ctx *blaze.Context, message string) error {
+		return ctx.JSON(400, map[string]any{"message": message})
+	},
+	Info: func(req CohereChatRequest) RequestInfo {
+		return RequestInfo{Model: req.Model, Stream: req.Stream}
+	},
+	Messages: func(req *CohereChatRequest) *[]CohereMessage {
+		return &req.ChatHistory
+	},
+	ToolCalls: func(req CohereChatRequest) []CohereToolCall {
+		return lastCohereToolCalls(req.ChatHistory)
+	},
+	Call: func(call CohereToolCall) (string, json.RawMessage) {
+		return call.Name, cohereInput(call.Parameters)
+	},
+	Result: func(call CohereToolCall, outcome ToolOutcome) CohereToolResult {
+		return CohereToolResult{Call: call, Outputs: cohereOutputs(outcome)}
+	},
+	Text: func(text string) CohereToolResult {
+		return CohereToolResult{Outputs: []map[string]any{{"text": text}}}
+	},
+	Conversation: func(req CohereChatRequest, results []CohereToolResult) []CohereMessage {
+		return append(cohereHistory(req), CohereMessage{Role: "TOOL", ToolResults: results})
+	},
+	History:     cohereHistory,
+	NoToolCalls: handleCohereNoToolCalls,
+	Send: func(ctx *blaze.Context, req CohereChatRequest, results []CohereToolResult) error {
+		return ctx.JSON(200, cohereResponse(results))
+	},
+	Stream: func(ctx *blaze.Context, req CohereChatRequest, run func(ProgressFunc) []CohereToolResult, heartbeat time.Duration) error {
+		return streamCohereResponse(ctx, run, heartbeat)
+	},
+}
+
+// cohereHistory returns the chat history of req followed by its message, the
+// current user turn, which Cohere sends outside chat_history
+func cohereHistory(req CohereChatRequest) []CohereMessage {
+	history := slices.Clip(req.ChatHistory)
+	if req.Message != "" {
+		history = append(history, CohereMessage{Role: "USER", Message: req.Message})
+	}
+	return history
+}
+
+// lastCohereToolCalls returns the tool calls of the last CHATBOT message
+// that has any, unless a TOOL message after it already answered them
+func lastCohereToolCalls(history []CohereMessage) []CohereToolCall {
+	for i := len(history) - 1; i >= 0; i-- {
+		switch {
+		case history[i].Role == "TOOL":
+			return nil
+		case history[i].Role == "CHATBOT" && len(history[i].ToolCalls) > 0:
+			return history[i].ToolCalls
+		}
+	}
+	return nil
+}
+
+// cohereInput encodes a call's parameters as tool input, using an empty
+// object when there are none
+func cohereInput(params map[string]any) json.RawMessage {
+	if params == nil {
+		return json.RawMessage("{}")
+	}
+	input, _ := json.Marshal(params)
+	return input
+}
+
+// cohereOutputs converts an outcome to Cohere's list of output objects.
+// Object results are used as is; other results are wrapped as {"result": v}.
+func cohereOutputs(outcome ToolOutcome) []map[string]any {
+	var v any
+	json.Unmarshal([]byte(outcome.Content), &v)
+	if obj, ok := v.(map[string]any); ok {
+		return []map[string]any{obj}
+	}
+	return []map[string]any{{"result": v}}
+}
+
+// cohereResponse builds the response for executed tool calls. The text
+// lists each result's JSON on its own line. Results without a call, made
+// by the spec's Text, add their text only.
+func cohereResponse(results []CohereToolResult) CohereChatResponse {
+	var text strings.Builder
+	var toolResults []CohereToolResult
+	for _, r := range results {
+		if r.Call.Name == "" {
+			for _, out := range r.Outputs {
+				fmt.Fprintln(&text, out["text"])
+			}
+			continue
+		}
+		toolResults = append(toolResults, r)
+		for _, out := range r.Outputs {
+			b, _ := json.Marshal(out)
+			text.Write(b)
+			text.WriteByte('\n')
+		}
+	}
+	return CohereChatResponse{
+		ResponseID:   generateID("resp"),
+		GenerationID: generateID("gen"),
+		Text:         text.String(),
+		ToolResults:  toolResults,
+		FinishReason: "COMPLETE",
+	}
+}
+
+// handleCohereNoToolCalls returns a response when no tool calls are present
+func handleCohereNoToolCalls(ctx *blaze.Context, req CohereChatRequest, tools []Tool) error {
+	return ctx.JSON(200, CohereChatResponse{
+		ResponseID:   generateID("resp"),
+		GenerationID: generateID("gen"),
+		Text:         fmt.Sprintf("I have access to %d tools. To use them, include tool_calls in a CHATBOT message of chat_history.%s Your message: %s", len(tools), systemNote(req.SystemPrompt()), req.Message),
+		FinishReason: "COMPLETE",
+	})
+}
+
+// streamCohereResponse streams a stream-start event, a text-generation
+// event per progress message and tool result, and a stream-end event
+// carrying the full response
+func streamCohereResponse(ctx *blaze.Context, run func(ProgressFunc) []CohereToolResult, heartbeat time.Duration) error {
+	ch := make(chan any)
+
+	go func() {
+		defer close(ch)
+
+		genID := generateID("gen")
+		ch <- CohereStreamEvent{EventType: "stream-start", GenerationID: genID}
+
+		progress, stop := guardProgress(func(msg string) {
+			ch <- CohereStreamEvent{EventType: "text-generation", Text: msg + "\n"}
+		})
+		results := run(progress)
+		stop()
+
+		response := cohereResponse(results)
+		response.GenerationID = genID
+		for _, line := range strings.SplitAfter(response.Text, "\n") {
+			if line != "" {
+				ch <- CohereStreamEvent{EventType: "text-generation", Text: line}
+			}
+		}
+		ch <- CohereStreamEvent{EventType: "stream-end", FinishReason: "COMPLETE", Response: &response}
+	}()
+
+	return streamJSON(ctx, ch, heartbeat)
+}
@@ -0,0 +1,154 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCohereAdapter_ToolExecution tests that tool_calls are answered with tool_results
+func TestCohereAdapter_ToolExecution(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", nil, func(input json.RawMessage) (any, error) {
+		var v map[string]any
+		json.Unmarshal(input, &v)
+		return v, nil
+	})
+	countTool := NewTool("count", "Count", nil, func(input json.RawMessage) (any, error) {
+		return 3, nil
+	})
+
+	rec := postJSON(t, CohereAdapter(echoTool, countTool), CohereChatRequest{
+		Message: "Echo hello",
+		ChatHistory: []CohereMessage{
+			{Role: "USER", Message: "Echo hello"},
+			{Role: "CHATBOT", ToolCalls: []CohereToolCall{
+				{Name: "echo", Parameters: map[string]any{"message": "hello"}},
+				{Name: "count"},
+			}},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CohereChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.ToolResults) != 2 {
+		t.Fatalf("Expected 2 tool results, got %d", len(resp.ToolResults))
+	}
+	first := resp.ToolResults[0]
+	if first.Call.Name != "echo" || !reflect.DeepEqual(first.Outputs, []map[string]any{{"message": "hello"}}) {
+		t.Errorf("Unexpected echo result: %+v", first)
+	}
+	if second := resp.ToolResults[1]; !reflect.DeepEqual(second.Outputs, []map[string]any{{"result": float64(3)}}) {
+		t.Errorf("Expected non-object result to be wrapped, got %+v", second.Outputs)
+	}
+	if resp.FinishReason != "COMPLETE" || !strings.Contains(resp.Text, `{"message":"hello"}`) {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+// TestCohereAdapter_ToolNotFound tests error outputs for unknown tools
+func TestCohereAdapter_ToolNotFound(t *testing.T) {
+	rec := postJSON(t, CohereAdapter(), CohereChatRequest{
+		ChatHistory: []CohereMessage{{Role: "CHATBOT", ToolCalls: []CohereToolCall{{Name: "unknown_tool"}}}},
+	})
+
+	var resp CohereChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.ToolResults) != 1 || resp.ToolResults[0].Outputs[0]["kind"] != string(KindNotFound) {
+		t.Errorf("Expected a not_found output, got %+v", resp.ToolResults)
+	}
+}
+
+// TestCohereAdapter_NoToolCalls tests the info response
+func TestCohereAdapter_NoToolCalls(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", nil, nil)
+
+	rec := postJSON(t, CohereAdapter(echoTool), CohereChatRequest{Message: "Hello", Preamble: "Be brief."})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp CohereChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !strings.Contains(resp.Text, "1 tools") || !strings.Contains(resp.Text, "System prompt received (9 chars)") || !strings.Contains(resp.Text, "Your message: Hello") {
+		t.Errorf("Unexpected info response: %s", resp.Text)
+	}
+}
+
+// TestCohereAdapter_InvalidRequest tests error handling for invalid requests
+func TestCohereAdapter_InvalidRequest(t *testing.T) {
+	if rec := postJSON(t, CohereAdapter(), "not an object"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for malformed body, got %d", rec.Code)
+	}
+	if rec := postJSON(t, CohereAdapter(), CohereChatRequest{}); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty request, got %d", rec.Code)
+	}
+}
+
+// TestCohereAdapter_Stream tests the streaming event sequence
+func TestCohereAdapter_Stream(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", nil, func(input json.RawMessage) (any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+
+	rec := postJSON(t, CohereAdapter(echoTool), CohereChatRequest{
+		Stream:      true,
+		ChatHistory: []CohereMessage{{Role: "CHATBOT", ToolCalls: []CohereToolCall{{Name: "echo"}}}},
+	})
+
+	var types []string
+	var last CohereStreamEvent
+	for _, line := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n") {
+		if err := json.Unmarshal([]byte(line), &last); err != nil {
+			t.Fatalf("Failed to parse event %q: %v", line, err)
+		}
+		types = append(types, last.EventType)
+	}
+	if want := []string{"stream-start", "text-generation", "stream-end"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("Expected events %v, got %v", want, types)
+	}
+	if last.Response == nil || len(last.Response.ToolResults) != 1 {
+		t.Errorf("Expected stream-end to carry the tool results, got %+v", last.Response)
+	}
+}
+
+// TestToolToCohere tests the JSON Schema to parameter definitions conversion
+func TestToolToCohere(t *testing.T) {
+	tool := NewTool("search", "Search the web", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string", "description": "Search query"},
+			"limit": map[string]any{"type": "integer"},
+			"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"score": map[string]any{"type": []string{"number", "null"}},
+			"opts":  map[string]any{"type": "object"},
+		},
+		"required": []string{"query"},
+	}, nil)
+
+	def := tool.ToCohere()
+	want := map[string]CohereParameterSpec{
+		"query": {Description: "Search query", Type: "str", Required: true},
+		"limit": {Type: "int"},
+		"tags":  {Type: "List[str]"},
+		"score": {Type: "float"},
+		"opts":  {Type: "Dict"},
+	}
+	if def.Name != "search" || def.Description != "Search the web" || !reflect.DeepEqual(def.ParameterDefinitions, want) {
+		t.Errorf("Unexpected definition: %+v", def)
+	}
+
+	if def := NewTool("ping", "Ping", nil, nil).ToCohere(); def.ParameterDefinitions != nil {
+		t.Errorf("Expected no parameter definitions without a schema, got %+v", def.ParameterDefinitions)
+	}
+}
@@ -0,0 +1,46 @@
+package adapter
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Tool Call Depth
+// ============================================================================
+
+// ToolDepthHeader carries the tool call depth of a chain when the request
+// body has no tool_call_depth field. Responses echo the depth the next
+// request in the chain should send.
+const ToolDepthHeader = "X-Tool-Call-Depth"
+
+// WithMaxToolDepth refuses to execute tool calls once a chain has already
+// gone through max tool rounds, returning a terminal assistant message
+// instead. Orchestrators pass the depth back via the tool_call_depth field
+// or the X-Tool-Call-Depth header.
+func WithMaxToolDepth(max int) Option {
+	return func(c *config) {
+		c.maxDepth = max
+	}
+}
+
+// toolCallDepth returns the depth from the request body, else from the header
+func toolCallDepth(ctx *blaze.Context, fromBody int) int {
+	if fromBody > 0 {
+		return fromBody
+	}
+	depth, _ := strconv.Atoi(ctx.Request.Header.Get(ToolDepthHeader))
+	return max(depth, 0)
+}
+
+// depthExceeded reports whether a request at depth may not execute tools
+func (c *config) depthExceeded(depth int) bool {
+	return c.maxDepth > 0 && depth >= c.maxDepth
+}
+
+// depthLimitMessage is the terminal message returned instead of tool results
+func depthLimitMessage(depth, max int) string {
+	return fmt.Sprintf("Tool call depth limit reached (depth %d, max %d). No tools were executed; answer with the information already available.", depth, max)
+}
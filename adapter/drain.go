@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Stream Draining
+// ============================================================================
+
+// drainContext returns a context derived from ctx that is also cancelled,
+// with blaze.ErrShutdown as its cause, when the server shuts down. Tools of
+// a streaming request run with it, so Engine.Shutdown stops them and the
+// stream ends with its usual terminal event instead of being cut off.
+func drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	drained, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(blaze.ShutdownContext(ctx), func() { cancel(blaze.ErrShutdown) })
+	return drained, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestShutdown_DrainsStream tests that shutdown stops the tools of a stream,
+// which then ends with its terminal event
+func TestShutdown_DrainsStream(t *testing.T) {
+	started := make(chan struct{})
+	wait := NewContextTool("wait", "Waits until cancelled", nil, func(ctx context.Context, input json.RawMessage) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, context.Cause(ctx)
+	})
+
+	e := blaze.New()
+	e.POST("/chat", AnthropicAdapter(wait))
+	body, _ := json.Marshal(AnthropicChatRequest{
+		Model:  "claude-3-5-sonnet",
+		Stream: true,
+		Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "wait", Input: map[string]any{}},
+		}}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("tool never started")
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end after shutdown")
+	}
+	out := rec.Body.String()
+	if !strings.Contains(out, blaze.ErrShutdown.Error()) {
+		t.Errorf("Expected the tool to report the shutdown, got %s", out)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out), `{"type":"message_stop","stop_reason":"end_turn"}`) {
+		t.Errorf("Expected the stream to end with message_stop, got %s", out)
+	}
+}
+
+// TestShutdown_NonStreamingUnaffected tests that tools of non-streaming
+// requests keep the request context
+func TestShutdown_NonStreamingUnaffected(t *testing.T) {
+	var cause error
+	check := NewContextTool("check", "Reports its context", nil, func(ctx context.Context, input json.RawMessage) (any, error) {
+		cause = context.Cause(ctx)
+		return map[string]any{"ok": true}, nil
+	})
+
+	e := blaze.New()
+	e.POST("/chat", AnthropicAdapter(check))
+	e.Shutdown(context.Background())
+
+	body, _ := json.Marshal(AnthropicChatRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "check", Input: map[string]any{}},
+		}}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	if cause != nil {
+		t.Errorf("Expected a live context, got %v", cause)
+	}
+}
@@ -0,0 +1,40 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Dry Run
+// ============================================================================
+
+// DryRunHeader enables dry-run mode when the request body has no dry_run
+// field. In dry-run mode tools marked SideEffect are not invoked, unless
+// their ReadOnly reports that the call only reads; the call returns a
+// preview of what would have executed instead.
+const DryRunHeader = "X-Dry-Run"
+
+// isDryRun reports whether the request asked for a dry run
+func isDryRun(ctx *blaze.Context) bool {
+	on, _ := strconv.ParseBool(ctx.Request.Header.Get(DryRunHeader))
+	return on
+}
+
+// dryRunOutcome is the synthesized result returned in place of a
+// side-effecting tool's handler
+func dryRunOutcome(tool Tool, input json.RawMessage) ToolOutcome {
+	var parsed any
+	if err := json.Unmarshal(input, &parsed); err != nil {
+		parsed = string(input)
+	}
+	return ToolOutcome{Content: toJSON(map[string]any{
+		"dry_run": true,
+		"tool":    tool.Name,
+		"input":   parsed,
+		"message": fmt.Sprintf("Tool '%s' would execute with this input; no action was taken", tool.Name),
+	})}
+}
@@ -0,0 +1,51 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestDryRun_Header tests that only side-effecting tools are skipped in dry-run mode
+func TestDryRun_Header(t *testing.T) {
+	var writes, reads int
+	write := NewTool("write", "Write", objectSchema, func(input json.RawMessage) (any, error) {
+		writes++
+		return "written", nil
+	})
+	write.SideEffect = true
+	read := NewTool("read", "Read", objectSchema, func(input json.RawMessage) (any, error) {
+		reads++
+		return "value", nil
+	})
+
+	e := blaze.New()
+	e.POST("/chat", OpenAIAdapter(write, read))
+	body, _ := json.Marshal(OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{
+			Role: "assistant",
+			ToolCalls: []OpenAIToolCall{
+				{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "write", Arguments: `{"path":"a.txt"}`}},
+				{ID: "call_2", Type: "function", Function: OpenAIFunctionCall{Name: "read", Arguments: `{}`}},
+			},
+		}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DryRunHeader, "true")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if writes != 0 || reads != 1 {
+		t.Errorf("Expected only the read tool to run, got writes=%d reads=%d", writes, reads)
+	}
+	if !strings.Contains(rec.Body.String(), `\"dry_run\":true`) || !strings.Contains(rec.Body.String(), `a.txt`) {
+		t.Errorf("Expected dry-run preview with the input, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,116 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Tool Errors
+// ============================================================================
+
+// ErrorKind classifies why a tool call failed
+type ErrorKind string
+
+const (
+	KindInvalidInput ErrorKind = "invalid_input" // the caller's input is wrong; retrying it unchanged fails again
+	KindNotFound     ErrorKind = "not_found"     // the tool or the thing it looked up does not exist
+	KindUpstream     ErrorKind = "upstream"      // a remote service failed; a retry may succeed
+	KindRateLimited  ErrorKind = "rate_limited"  // a call or rate limit was hit; retry later
+	KindInternal     ErrorKind = "internal"      // anything else, including unclassified errors
+)
+
+// Retryable reports whether the same call may succeed if retried later
+func (k ErrorKind) Retryable() bool {
+	return k == KindUpstream || k == KindRateLimited
+}
+
+// ToolError is an error a tool handler returns to tell the adapters what
+// kind of failure occurred. The adapters include the kind in the error
+// result, so clients can tell their own mistakes from retryable failures.
+type ToolError struct {
+	Kind    ErrorKind
+	Message string
+	Err     error // wrapped cause, may be nil
+}
+
+// NewToolError creates a ToolError with a fmt.Errorf-style message. A %w
+// verb makes the argument the wrapped cause.
+func NewToolError(kind ErrorKind, format string, args ...any) *ToolError {
+	err := fmt.Errorf(format, args...)
+	return &ToolError{Kind: kind, Message: err.Error(), Err: errors.Unwrap(err)}
+}
+
+// Error implements the error interface
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the wrapped cause
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKindOf returns the kind of the first ToolError in err's chain, or
+// KindInternal when there is none
+func ErrorKindOf(err error) ErrorKind {
+	var te *ToolError
+	if errors.As(err, &te) && te.Kind != "" {
+		return te.Kind
+	}
+	return KindInternal
+}
+
+// ============================================================================
+// Error Envelopes
+// ============================================================================
+
+// AnthropicErrorEnvelope shapes e as Anthropic's API reports errors,
+// {"type": "error", "error": {"type": ..., "message": ...}}. Assign it to
+// blaze.ErrorEnvelope to use that shape for all error responses.
+func AnthropicErrorEnvelope(e *blaze.HTTPError) any {
+	return map[string]any{
+		"type": "error",
+		"error": map[string]any{
+			"type":    providerErrorType(e.Code),
+			"message": e.Message,
+		},
+	}
+}
+
+// OpenAIErrorEnvelope shapes e as OpenAI's API reports errors,
+// {"error": {"message": ..., "type": ...}}. Assign it to
+// blaze.ErrorEnvelope to use that shape for all error responses.
+func OpenAIErrorEnvelope(e *blaze.HTTPError) any {
+	return map[string]any{
+		"error": map[string]any{
+			"message": e.Message,
+			"type":    providerErrorType(e.Code),
+		},
+	}
+}
+
+// providerErrorType maps an HTTP status to an error type as Anthropic names
+// them. Both envelopes use these names; for 400 they match OpenAI's too.
+func providerErrorType(code int) string {
+	switch code {
+	case 401:
+		return "authentication_error"
+	case 403:
+		return "permission_error"
+	case 404:
+		return "not_found_error"
+	case 413:
+		return "request_too_large"
+	case 429:
+		return "rate_limit_error"
+	case 529:
+		return "overloaded_error"
+	}
+	if code >= 500 {
+		return "api_error"
+	}
+	return "invalid_request_error"
+}
@@ -0,0 +1,108 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestErrorKindOf tests kind lookup through wrapped errors
+func TestErrorKindOf(t *testing.T) {
+	cause := errors.New("connection reset")
+	te := NewToolError(KindUpstream, "fetch failed: %w", cause)
+	if te.Error() != "fetch failed: connection reset" || !errors.Is(te, cause) {
+		t.Errorf("Expected message and cause to be kept, got %q", te.Error())
+	}
+
+	tests := []struct {
+		err  error
+		want ErrorKind
+	}{
+		{te, KindUpstream},
+		{fmt.Errorf("outer: %w", te), KindUpstream},
+		{errors.New("plain"), KindInternal},
+		{&ToolError{Message: "no kind"}, KindInternal},
+	}
+	for _, tt := range tests {
+		if got := ErrorKindOf(tt.err); got != tt.want {
+			t.Errorf("ErrorKindOf(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestToolErrorKinds tests that error results carry the kind and retryable flag
+func TestToolErrorKinds(t *testing.T) {
+	tools := []Tool{
+		NewTool("strict", "Strict", nil, func(input json.RawMessage) (any, error) {
+			return nil, NewToolError(KindInvalidInput, "name is required")
+		}),
+		NewTool("flaky", "Flaky", nil, func(input json.RawMessage) (any, error) {
+			return nil, NewToolError(KindUpstream, "service unavailable")
+		}),
+		NewTool("plain", "Plain", nil, func(input json.RawMessage) (any, error) {
+			return nil, errors.New("boom")
+		}),
+	}
+	handler := AnthropicAdapterWithOptions(tools)
+
+	var blocks []AnthropicContentBlock
+	for i, name := range []string{"strict", "flaky", "plain", "missing"} {
+		blocks = append(blocks, AnthropicContentBlock{Type: "tool_use", ID: fmt.Sprintf("toolu_%d", i), Name: name, Input: map[string]any{}})
+	}
+	rec := postJSON(t, handler, AnthropicChatRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: blocks}},
+	})
+
+	var resp AnthropicChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Content) != 4 {
+		t.Fatalf("Expected 4 tool results, got %d", len(resp.Content))
+	}
+
+	want := []struct {
+		kind      ErrorKind
+		retryable bool
+	}{
+		{KindInvalidInput, false},
+		{KindUpstream, true},
+		{KindInternal, false},
+		{KindNotFound, false},
+	}
+	for i, w := range want {
+		block := resp.Content[i]
+		var result struct {
+			Error     string    `json:"error"`
+			Kind      ErrorKind `json:"kind"`
+			Retryable bool      `json:"retryable"`
+		}
+		if err := json.Unmarshal([]byte(block.Content), &result); err != nil {
+			t.Fatalf("Failed to parse result %d: %v", i, err)
+		}
+		if !block.IsError || result.Error == "" {
+			t.Errorf("Result %d: expected an error, got %s", i, block.Content)
+		}
+		if result.Kind != w.kind || result.Retryable != w.retryable {
+			t.Errorf("Result %d: expected kind=%s retryable=%v, got %s", i, w.kind, w.retryable, block.Content)
+		}
+	}
+}
+
+// TestErrorEnvelopes tests the provider error shapes
+func TestErrorEnvelopes(t *testing.T) {
+	e := blaze.NewHTTPError(429, "slow down")
+
+	got, _ := json.Marshal(AnthropicErrorEnvelope(e))
+	if want := `{"error":{"message":"slow down","type":"rate_limit_error"},"type":"error"}`; string(got) != want {
+		t.Errorf("Anthropic: expected %s, got %s", want, got)
+	}
+	got, _ = json.Marshal(OpenAIErrorEnvelope(blaze.NewHTTPError(400, "bad")))
+	if want := `{"error":{"message":"bad","type":"invalid_request_error"}}`; string(got) != want {
+		t.Errorf("OpenAI: expected %s, got %s", want, got)
+	}
+}
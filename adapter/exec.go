@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Direct Tool Execution
+// ============================================================================
+
+// ExecHandler creates a handler that runs a single tool without the chat
+// wrapper. Mount it on a route with a :name param, e.g. POST /tools/:name;
+// the request body is the tool input. The response is {"result": ...} on
+// success or {"error": "...", "kind": "...", "retryable": bool} on failure,
+// with status 200 unless WithUnknownToolStatus applies. Malformed requests
+// get a 400 in the blaze.ErrorEnvelope format.
+//
+// GET and HEAD requests take the input from the query string instead, one
+// string field per parameter (an array when repeated). A tool that lists
+// Methods is only run for those; other methods get a 405. SideEffect tools
+// without Methods only run for POST, so that a prefetcher or crawler
+// following a link can't trigger them; list GET in Methods to opt in.
+func ExecHandler(tools ...Tool) blaze.HandlerFunc {
+	return ExecHandlerWithOptions(tools)
+}
+
+// ExecHandlerWithOptions is like ExecHandler but accepts Options
+func ExecHandlerWithOptions(tools []Tool, opts ...Option) blaze.HandlerFunc {
+	cfg := newConfig(opts)
+	toolMap := mustBuildToolMap(tools)
+
+	return func(ctx *blaze.Context) error {
+		defer ctx.Request.Body.Close()
+		name := ctx.Param("name")
+		exec := newExecutor(ctx, toolMap, cfg)
+
+		method := ctx.Request.Method
+		if tool, _, ok := exec.lookup(name); ok && !acceptsMethod(tool, method) {
+			ctx.SetHeader("Allow", strings.Join(allowedMethods(tool), ", "))
+			return ctx.Errorf(405, "tool %s does not accept %s", name, method)
+		}
+
+		var input []byte
+		if method == http.MethodGet || method == http.MethodHead {
+			input = queryInput(ctx.Request.URL.Query())
+		} else {
+			var err error
+			if input, err = io.ReadAll(ctx.Request.Body); err != nil {
+				return ctx.Errorf(400, "failed to read body: %v", err)
+			}
+		}
+		if len(input) == 0 {
+			input = []byte("{}")
+		}
+		if !json.Valid(input) {
+			return ctx.Error(400, "request body must be valid JSON")
+		}
+
+		outcome := exec.execute(name, input)
+		exec.logRequest()
+		if outcome.IsError {
+			status := 200
+			if cfg.unknownStatus != 0 {
+				if _, _, ok := exec.lookup(name); !ok {
+					status = cfg.unknownStatus
+				}
+			}
+			return ctx.JSON(status, json.RawMessage(outcome.Content))
+		}
+		return ctx.JSON(200, map[string]any{"result": json.RawMessage(outcome.Content)})
+	}
+}
+
+// queryInput encodes query parameters as a tool input object: a string per
+// parameter, or an array of strings for a repeated one
+func queryInput(query url.Values) []byte {
+	fields := make(map[string]any, len(query))
+	for key, values := range query {
+		if len(values) == 1 {
+			fields[key] = values[0]
+		} else {
+			fields[key] = values
+		}
+	}
+	input, _ := json.Marshal(fields)
+	return input
+}
+
+// allowedMethods returns the methods ExecHandler runs tool for: its
+// Methods, POST for a SideEffect tool without any, or nil for any method
+func allowedMethods(tool Tool) []string {
+	if len(tool.Methods) == 0 && tool.SideEffect {
+		return []string{http.MethodPost}
+	}
+	return tool.Methods
+}
+
+// acceptsMethod reports whether ExecHandler may run tool for method
+func acceptsMethod(tool Tool, method string) bool {
+	methods := allowedMethods(tool)
+	return len(methods) == 0 || slices.ContainsFunc(methods, func(m string) bool {
+		return strings.EqualFold(m, method)
+	})
+}
@@ -0,0 +1,248 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Tool Execution
+// ============================================================================
+
+// ToolOutcome is the provider-neutral result of one tool call
+type ToolOutcome struct {
+	Content string // JSON-encoded result or error object
+	IsError bool
+	Kind    ErrorKind // why the call failed, set when IsError
+}
+
+// executor runs the tool calls of a single request, applying the adapter's
+// configured policies. A new executor is created per request so per-request
+// state (like call counts) starts fresh.
+type executor struct {
+	ctx     *blaze.Context
+	toolMap map[string]Tool
+	cfg     *config
+
+	mu    sync.Mutex // guards calls, seen and records for concurrent execute calls
+	calls map[string]int
+	seen  map[string]*dedupedCall // calls by call key, when dedupe is enabled
+
+	started time.Time
+	records []callRecord // finished calls, when request logging is enabled
+
+	progress ProgressFunc // receives progress from progress-aware tools, may be nil
+
+	toolCtx context.Context // passed to context-aware tools instead of the request's, may be nil
+}
+
+func newExecutor(ctx *blaze.Context, toolMap map[string]Tool, cfg *config) *executor {
+	return &executor{
+		ctx:     ctx,
+		toolMap: toolMap,
+		cfg:     cfg,
+		calls:   make(map[string]int),
+		seen:    make(map[string]*dedupedCall),
+		started: time.Now(),
+	}
+}
+
+// dedupedCall is a call shared by identical calls of a request. done is
+// closed once outcome is set.
+type dedupedCall struct {
+	done    chan struct{}
+	outcome ToolOutcome
+}
+
+// execute runs the named tool with the given raw JSON input. With dedupe,
+// identical calls, including concurrent ones, run the tool once and share
+// its outcome.
+func (x *executor) execute(name string, input json.RawMessage) (outcome ToolOutcome) {
+	if x.cfg.requestLog != nil {
+		defer func(started time.Time) { x.record(name, input, started, outcome) }(time.Now())
+	}
+	if !x.cfg.dedupe {
+		return x.run(name, input)
+	}
+
+	key := callKey(name, input)
+	x.mu.Lock()
+	if call, ok := x.seen[key]; ok {
+		x.mu.Unlock()
+		<-call.done
+		return call.outcome
+	}
+	call := &dedupedCall{done: make(chan struct{})}
+	x.seen[key] = call
+	x.mu.Unlock()
+
+	defer close(call.done)
+	call.outcome = x.run(name, input)
+	return call.outcome
+}
+
+// lookup returns the tool called name. For a tool that is unknown or
+// disabled it returns the error outcome to report instead, and false.
+func (x *executor) lookup(name string) (Tool, ToolOutcome, bool) {
+	tool, exists := x.toolMap[name]
+	if reg := x.cfg.registry; reg != nil {
+		if registered, ok := reg.Get(name); ok {
+			if !reg.Enabled(name) {
+				return Tool{}, errorOutcome(KindNotFound, fmt.Sprintf("Tool '%s' is disabled", name)), false
+			}
+			if !exists {
+				tool, exists = registered, true
+			}
+		}
+	}
+	if !exists {
+		return Tool{}, errorOutcome(KindNotFound, fmt.Sprintf("Tool '%s' not found", name)), false
+	}
+	return tool, ToolOutcome{}, true
+}
+
+// run applies the per-call policies and invokes the tool handler
+func (x *executor) run(name string, input json.RawMessage) ToolOutcome {
+	tool, missing, ok := x.lookup(name)
+	if !ok {
+		return missing
+	}
+
+	x.mu.Lock()
+	if limit, ok := x.cfg.callLimits[name]; ok && x.calls[name] >= limit {
+		x.mu.Unlock()
+		return errorOutcome(KindRateLimited, fmt.Sprintf("call limit exceeded for %s (max %d per request)", name, limit))
+	}
+	x.calls[name]++
+	x.mu.Unlock()
+
+	input = x.applyInputHooks(tool, input)
+	if tool.SideEffect && isDryRun(x.ctx) && (tool.ReadOnly == nil || !tool.ReadOnly(input)) {
+		return dryRunOutcome(tool, input)
+	}
+
+	if bucket, ok := x.cfg.rateLimits[name]; ok && !bucket.take() {
+		return errorOutcome(KindRateLimited, fmt.Sprintf("rate limit exceeded for %s", name))
+	}
+
+	result, err := x.invokeRecovered(name, tool, input)
+	if err != nil {
+		return errorOutcome(ErrorKindOf(err), err.Error())
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	if x.cfg.maxResult > 0 && len(resultBytes) > x.cfg.maxResult {
+		resultBytes, _ = json.Marshal(TruncateResult(result, x.cfg.maxResult))
+	}
+	return ToolOutcome{Content: string(resultBytes)}
+}
+
+// invokeRecovered runs the tool's handler, turning a panic into an internal
+// error so that one faulty tool fails only its own call, not the request.
+// Each invocation is counted in ToolMetrics and, with the otel build tag,
+// traced as a child span of the request.
+func (x *executor) invokeRecovered(name string, tool Tool, input json.RawMessage) (result any, err error) {
+	ctx := x.toolCtx
+	if ctx == nil {
+		ctx = x.requestContext()
+	}
+	ctx, endSpan := startToolSpan(ctx, name)
+	defer func(started time.Time) {
+		observeTool(name, time.Since(started), err != nil)
+		endSpan(err)
+	}(time.Now())
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[PANIC] tool %s: %v\n%s", name, r, debug.Stack())
+			result, err = nil, NewToolError(KindInternal, "tool '%s' panicked: %v", name, r)
+		}
+	}()
+	return tool.Invoke(ctx, input, x.progress)
+}
+
+// requestContext returns the context of the request being served, or
+// context.Background() without one
+func (x *executor) requestContext() context.Context {
+	if x.ctx == nil {
+		return context.Background()
+	}
+	return x.ctx.Request.Context()
+}
+
+// callKey identifies a call by tool name and normalized input, so inputs
+// that differ only in key order or whitespace are treated as identical.
+// Numbers are kept as written, so large integers that round to the same
+// float64 stay distinct.
+func callKey(name string, input json.RawMessage) string {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		// Maps marshal with sorted keys, giving a canonical encoding
+		input, _ = json.Marshal(v)
+	}
+	sum := sha256.Sum256(append([]byte(name+"\x00"), input...))
+	return hex.EncodeToString(sum[:])
+}
+
+// errorOutcome wraps a message in the {"error", "kind", "retryable"} shape
+// used for failed calls
+func errorOutcome(kind ErrorKind, msg string) ToolOutcome {
+	return ToolOutcome{
+		Content: toJSON(map[string]any{"error": msg, "kind": kind, "retryable": kind.Retryable()}),
+		IsError: true,
+		Kind:    kind,
+	}
+}
+
+// ============================================================================
+// Rate Limiting
+// ============================================================================
+
+// tokenBucket is a thread-safe token bucket refilled continuously at
+// perMinute tokens per minute, holding at most perMinute tokens
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(perMinute int) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		last:     time.Now(),
+	}
+}
+
+// take consumes a token, reporting false when the bucket is empty
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,348 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// postJSON posts body as JSON to h mounted on a fresh engine and returns the recorder
+func postJSON(t *testing.T, h blaze.HandlerFunc, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := blaze.New()
+	e.POST("/chat", h)
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestWithToolLimits_PerRequestCap tests that calls beyond a tool's cap are refused
+func TestWithToolLimits_PerRequestCap(t *testing.T) {
+	calls := 0
+	searchTool := NewTool("web_search", "Search", nil,
+		func(input json.RawMessage) (any, error) {
+			calls++
+			return map[string]any{"ok": true}, nil
+		},
+	)
+
+	handler := AnthropicAdapterWithOptions([]Tool{searchTool}, WithToolLimits(map[string]int{"web_search": 2}))
+
+	var blocks []AnthropicContentBlock
+	for _, id := range []string{"toolu_1", "toolu_2", "toolu_3"} {
+		blocks = append(blocks, AnthropicContentBlock{Type: "tool_use", ID: id, Name: "web_search", Input: map[string]any{}})
+	}
+	rec := postJSON(t, handler, AnthropicChatRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: blocks}},
+	})
+
+	var resp AnthropicChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected handler to run 2 times, ran %d", calls)
+	}
+	if len(resp.Content) != 3 {
+		t.Fatalf("Expected 3 tool results, got %d", len(resp.Content))
+	}
+	if resp.Content[1].IsError {
+		t.Errorf("Expected second call to succeed, got: %s", resp.Content[1].Content)
+	}
+	last := resp.Content[2]
+	if !last.IsError || !strings.Contains(last.Content, "limit exceeded for web_search") {
+		t.Errorf("Expected limit error for third call, got is_error=%v content=%s", last.IsError, last.Content)
+	}
+
+	// Counts reset on the next request
+	calls = 0
+	postJSON(t, handler, AnthropicChatRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: blocks[:1]}},
+	})
+	if calls != 1 {
+		t.Errorf("Expected call count to reset per request, handler ran %d times", calls)
+	}
+}
+
+// TestWithToolRateLimits tests the per-minute token bucket across requests
+func TestWithToolRateLimits(t *testing.T) {
+	fetchTool := NewTool("web_fetch", "Fetch", nil,
+		func(input json.RawMessage) (any, error) {
+			return map[string]any{"ok": true}, nil
+		},
+	)
+
+	handler := OpenAIAdapterWithOptions([]Tool{fetchTool}, WithToolRateLimits(map[string]int{"web_fetch": 1}))
+
+	req := OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{
+			Role: "assistant",
+			ToolCalls: []OpenAIToolCall{{
+				ID: "call_1", Type: "function",
+				Function: OpenAIFunctionCall{Name: "web_fetch", Arguments: `{}`},
+			}},
+		}},
+	}
+
+	var contents []string
+	for range 2 {
+		var resp OpenAIChatResponse
+		if err := json.Unmarshal(postJSON(t, handler, req).Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		contents = append(contents, resp.Choices[0].Message.Content)
+	}
+
+	if strings.Contains(contents[0], "rate limit") {
+		t.Errorf("Expected first call to pass, got: %s", contents[0])
+	}
+	if !strings.Contains(contents[1], "rate limit exceeded for web_fetch") {
+		t.Errorf("Expected second call to be rate limited, got: %s", contents[1])
+	}
+}
+
+// TestWithDedupe tests that identical tool_use blocks execute once but each gets a result
+func TestWithDedupe(t *testing.T) {
+	calls := 0
+	fetchTool := NewTool("web_fetch", "Fetch", nil,
+		func(input json.RawMessage) (any, error) {
+			calls++
+			return map[string]any{"body": "page"}, nil
+		},
+	)
+
+	blocks := []AnthropicContentBlock{
+		{Type: "tool_use", ID: "toolu_1", Name: "web_fetch", Input: map[string]any{"url": "https://example.com", "raw": true}},
+		{Type: "tool_use", ID: "toolu_2", Name: "web_fetch", Input: map[string]any{"raw": true, "url": "https://example.com"}},
+		{Type: "tool_use", ID: "toolu_3", Name: "web_fetch", Input: map[string]any{"url": "https://example.org"}},
+	}
+	req := AnthropicChatRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: blocks}},
+	}
+
+	var resp AnthropicChatResponse
+	rec := postJSON(t, AnthropicAdapterWithOptions([]Tool{fetchTool}, WithDedupe()), req)
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected handler to run 2 times, ran %d", calls)
+	}
+	if len(resp.Content) != 3 {
+		t.Fatalf("Expected 3 tool results, got %d", len(resp.Content))
+	}
+	for i, id := range []string{"toolu_1", "toolu_2", "toolu_3"} {
+		if resp.Content[i].ToolUseID != id {
+			t.Errorf("Expected result %d for %s, got %s", i, id, resp.Content[i].ToolUseID)
+		}
+	}
+	if resp.Content[0].Content != resp.Content[1].Content {
+		t.Errorf("Expected duplicate to reuse the first result")
+	}
+
+	// Without the option both duplicates execute
+	calls = 0
+	postJSON(t, AnthropicAdapter(fetchTool), req)
+	if calls != 3 {
+		t.Errorf("Expected dedupe to be opt-in, handler ran %d times", calls)
+	}
+}
+
+// TestWithDedupe_Concurrent tests that concurrent identical calls run the tool once
+func TestWithDedupe_Concurrent(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	slow := NewTool("slow", "Slow", nil, func(input json.RawMessage) (any, error) {
+		calls.Add(1)
+		<-release
+		return "done", nil
+	})
+	x := newExecutor(nil, map[string]Tool{"slow": slow}, newConfig([]Option{WithDedupe()}))
+
+	var wg sync.WaitGroup
+	outcomes := make([]ToolOutcome, 8)
+	for i := range outcomes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcomes[i] = x.execute("slow", json.RawMessage(`{"a":1}`))
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", got)
+	}
+	for i, o := range outcomes {
+		if o.IsError || o.Content != `"done"` {
+			t.Errorf("Call %d: unexpected outcome %+v", i, o)
+		}
+	}
+}
+
+// TestCallKey_LargeNumbers tests that integers beyond float64 precision give distinct keys
+func TestCallKey_LargeNumbers(t *testing.T) {
+	a := callKey("get", json.RawMessage(`{"id": 9007199254740993}`))
+	b := callKey("get", json.RawMessage(`{"id":9007199254740992}`))
+	if a == b {
+		t.Error("Expected distinct keys for distinct large integers")
+	}
+	if c := callKey("get", json.RawMessage(`{ "id":9007199254740993 }`)); c != a {
+		t.Error("Expected whitespace to be ignored")
+	}
+}
+
+// TestMaxToolDepth tests that tool calls are refused once the depth cap is reached
+func TestMaxToolDepth(t *testing.T) {
+	var calls int
+	tools := []Tool{NewTool("count", "Count", objectSchema, func(input json.RawMessage) (any, error) {
+		calls++
+		return calls, nil
+	})}
+	h := AnthropicAdapterWithOptions(tools, WithMaxToolDepth(3))
+
+	request := func(depth int) map[string]any {
+		return map[string]any{
+			"model":           "claude-3-5-sonnet",
+			"tool_call_depth": depth,
+			"messages": []map[string]any{
+				{"role": "user", "content": []map[string]any{
+					{"type": "tool_use", "id": "toolu_1", "name": "count", "input": map[string]any{}},
+				}},
+			},
+		}
+	}
+
+	// Below the cap the tool runs and the next depth is echoed
+	rec := postJSON(t, h, request(2))
+	if calls != 1 {
+		t.Fatalf("Expected tool to run below the cap, got %d calls", calls)
+	}
+	if got := rec.Header().Get(ToolDepthHeader); got != "3" {
+		t.Errorf("Expected echoed depth 3, got %q", got)
+	}
+
+	// Above the cap the tool is refused with a terminal message
+	rec = postJSON(t, h, request(5))
+	if calls != 1 {
+		t.Errorf("Expected tool not to run above the cap, got %d calls", calls)
+	}
+	var resp AnthropicChatResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Content) != 1 || resp.Content[0].Type != "text" || !strings.Contains(resp.Content[0].Text, "depth limit reached") {
+		t.Errorf("Expected depth limit message, got %s", rec.Body.String())
+	}
+	if got := rec.Header().Get(ToolDepthHeader); got != "5" {
+		t.Errorf("Expected echoed depth 5, got %q", got)
+	}
+}
+
+// TestMaxToolDepth_Header tests the depth passed via header on the OpenAI adapter
+func TestMaxToolDepth_Header(t *testing.T) {
+	e := blaze.New()
+	e.POST("/chat", OpenAIAdapterWithOptions([]Tool{NewTool("noop", "Noop", objectSchema, noopHandler)}, WithMaxToolDepth(1)))
+
+	body, _ := json.Marshal(OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{
+			Role:      "assistant",
+			ToolCalls: []OpenAIToolCall{{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "noop", Arguments: "{}"}}},
+		}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ToolDepthHeader, "2")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var resp OpenAIChatResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if !strings.Contains(resp.Choices[0].Message.Content, "depth limit reached") {
+		t.Errorf("Expected depth limit message, got %s", rec.Body.String())
+	}
+}
+
+// TestToolPanicIsolation tests that a panicking handler fails only its own call
+func TestToolPanicIsolation(t *testing.T) {
+	good := NewTool("good", "Good", nil, func(input json.RawMessage) (any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+	bad := NewTool("bad", "Bad", nil, func(input json.RawMessage) (any, error) {
+		var m map[string]int
+		m["boom"]++ // nil map write
+		return nil, nil
+	})
+
+	t.Run("anthropic", func(t *testing.T) {
+		rec := postJSON(t, AnthropicAdapter(good, bad), AnthropicChatRequest{
+			Model: "claude-3-5-sonnet",
+			Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+				{Type: "tool_use", ID: "toolu_1", Name: "bad", Input: map[string]any{}},
+				{Type: "tool_use", ID: "toolu_2", Name: "good", Input: map[string]any{}},
+			}}},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp AnthropicChatResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(resp.Content) != 2 {
+			t.Fatalf("Expected 2 tool results, got %d", len(resp.Content))
+		}
+		if first := resp.Content[0]; !first.IsError || !strings.Contains(first.Content, "panicked") || !strings.Contains(first.Content, `"kind":"internal"`) {
+			t.Errorf("Expected internal error for panicking tool, got is_error=%v content=%s", first.IsError, first.Content)
+		}
+		if second := resp.Content[1]; second.IsError || second.Content != `{"ok":true}` {
+			t.Errorf("Expected good tool to succeed, got is_error=%v content=%s", second.IsError, second.Content)
+		}
+	})
+
+	t.Run("openai", func(t *testing.T) {
+		rec := postJSON(t, OpenAIAdapter(good, bad), OpenAIChatRequest{
+			Model: "gpt-4",
+			Messages: []OpenAIMessage{{Role: "assistant", ToolCalls: []OpenAIToolCall{
+				{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "bad", Arguments: `{}`}},
+				{ID: "call_2", Type: "function", Function: OpenAIFunctionCall{Name: "good", Arguments: `{}`}},
+			}}},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp OpenAIChatResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		content := resp.Choices[0].Message.Content
+		if !strings.Contains(content, "panicked") || !strings.Contains(content, `{"ok":true}`) {
+			t.Errorf("Expected panic error and good result, got: %s", content)
+		}
+	})
+}
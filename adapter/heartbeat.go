@@ -0,0 +1,63 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Stream Heartbeats
+// ============================================================================
+
+// heartbeatEvent is a JSON line of its own, like every streamed event:
+// proxies see traffic on the connection, and clients decoding the stream
+// line by line can skip it by its type
+const heartbeatEvent = `{"type":"ping"}` + "\n"
+
+// WithStreamHeartbeat makes streaming responses write a {"type":"ping"}
+// event whenever no event has been sent for interval, so proxies don't drop
+// the connection while slow tools run. Off by default.
+func WithStreamHeartbeat(interval time.Duration) Option {
+	return func(c *config) {
+		c.heartbeat = interval
+	}
+}
+
+// streamJSON streams events like ctx.StreamJSON, interleaving heartbeats
+// when the channel stays idle for heartbeat. A zero heartbeat disables them.
+func streamJSON(ctx *blaze.Context, events <-chan any, heartbeat time.Duration) error {
+	if heartbeat <= 0 {
+		return ctx.StreamJSON(events)
+	}
+
+	ctx.SetHeader("Content-Type", "application/json")
+	ctx.SetHeader("Transfer-Encoding", "chunked")
+
+	flusher, _ := ctx.ResponseWriter.(http.Flusher)
+	encoder := json.NewEncoder(ctx.ResponseWriter)
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := encoder.Encode(data); err != nil {
+				return err
+			}
+			ticker.Reset(heartbeat)
+		case <-ticker.C:
+			if _, err := ctx.ResponseWriter.Write([]byte(heartbeatEvent)); err != nil {
+				return err
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestStreamJSON_Heartbeat tests that heartbeats are sent while a producer is idle
+func TestStreamJSON_Heartbeat(t *testing.T) {
+	e := blaze.New()
+	e.GET("/stream", func(c *blaze.Context) error {
+		ch := make(chan any)
+		go func() {
+			defer close(ch)
+			time.Sleep(60 * time.Millisecond)
+			ch <- map[string]any{"n": 1}
+		}()
+		return streamJSON(c, ch, 10*time.Millisecond)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	body := rec.Body.String()
+	first := strings.Index(body, `{"n":1}`)
+	if first < 0 {
+		t.Fatalf("Expected the data event, got %q", body)
+	}
+	if !strings.HasPrefix(body, heartbeatEvent) {
+		t.Errorf("Expected a heartbeat before the first event, got %q", body)
+	}
+	if strings.Contains(body[first:], "ping") {
+		t.Errorf("Expected no heartbeat after the channel closed, got %q", body)
+	}
+}
+
+// TestWithStreamHeartbeat tests heartbeats during slow tool execution
+func TestWithStreamHeartbeat(t *testing.T) {
+	slow := NewTool("slow", "Slow", nil, func(input json.RawMessage) (any, error) {
+		time.Sleep(60 * time.Millisecond)
+		return map[string]any{"done": true}, nil
+	})
+	req := OpenAIChatRequest{
+		Model:  "gpt-4",
+		Stream: true,
+		Messages: []OpenAIMessage{{Role: "assistant", ToolCalls: []OpenAIToolCall{
+			{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "slow", Arguments: `{}`}},
+		}}},
+	}
+
+	body := postJSON(t, OpenAIAdapterWithOptions([]Tool{slow}, WithStreamHeartbeat(10*time.Millisecond)), req).Body.String()
+	beat, result := strings.Index(body, heartbeatEvent), strings.Index(body, `\"done\":true`)
+	if beat < 0 || result < 0 || beat > result {
+		t.Errorf("Expected a heartbeat before the tool result, got %q", body)
+	}
+
+	// Off by default
+	body = postJSON(t, OpenAIAdapter(slow), req).Body.String()
+	if strings.Contains(body, `"ping"`) {
+		t.Errorf("Expected no heartbeats by default, got %q", body)
+	}
+}
+
+// TestWithStreamHeartbeat_DecodesAsJSONLines tests that every line of a
+// stream with heartbeats is a JSON value, for each streaming adapter
+func TestWithStreamHeartbeat_DecodesAsJSONLines(t *testing.T) {
+	slow := NewTool("slow", "Slow", nil, func(input json.RawMessage) (any, error) {
+		time.Sleep(60 * time.Millisecond)
+		return map[string]any{"done": true}, nil
+	})
+	opts := []Option{WithStreamHeartbeat(10 * time.Millisecond)}
+
+	tests := []struct {
+		name    string
+		handler blaze.HandlerFunc
+		req     any
+	}{
+		{"openai", OpenAIAdapterWithOptions([]Tool{slow}, opts...), OpenAIChatRequest{
+			Model:  "gpt-4",
+			Stream: true,
+			Messages: []OpenAIMessage{{Role: "assistant", ToolCalls: []OpenAIToolCall{
+				{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "slow", Arguments: `{}`}},
+			}}},
+		}},
+		{"anthropic", AnthropicAdapterWithOptions([]Tool{slow}, opts...), AnthropicChatRequest{
+			Model:  "claude-3-5-sonnet",
+			Stream: true,
+			Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+				{Type: "tool_use", ID: "toolu_1", Name: "slow", Input: map[string]any{}},
+			}}},
+		}},
+		{"cohere", CohereAdapterWithOptions([]Tool{slow}, opts...), CohereChatRequest{
+			Stream:      true,
+			ChatHistory: []CohereMessage{{Role: "CHATBOT", ToolCalls: []CohereToolCall{{Name: "slow"}}}},
+		}},
+	}
+	for _, tt := range tests {
+		body := postJSON(t, tt.handler, tt.req).Body.String()
+		pings := 0
+		for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+			var event map[string]any
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				t.Fatalf("%s: line %q is not JSON: %v", tt.name, line, err)
+			}
+			if event["type"] == "ping" {
+				pings++
+			}
+		}
+		if pings == 0 {
+			t.Errorf("%s: expected heartbeats in %q", tt.name, body)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Input Hooks
+// ============================================================================
+
+// InputHook can modify a tool's input before its handler runs. input is the
+// decoded JSON object; hooks are skipped for inputs that are not objects.
+type InputHook func(ctx *blaze.Context, tool Tool, input map[string]any)
+
+// WithInputHook registers a hook that runs on every tool input, in the order
+// hooks were added
+func WithInputHook(hook InputHook) Option {
+	return func(c *config) {
+		c.inputHooks = append(c.inputHooks, hook)
+	}
+}
+
+// TimezoneHeader carries the client's IANA timezone when the request body
+// has no timezone field
+const TimezoneHeader = "X-Timezone"
+
+// WithDefaultTimezone fills a blank "timezone" input with the client's
+// timezone for tools whose schema declares a timezone property, such as the
+// datetime tool. The zone comes from the request's timezone field or the
+// X-Timezone header.
+func WithDefaultTimezone() Option {
+	return WithInputHook(defaultTimezone)
+}
+
+func defaultTimezone(ctx *blaze.Context, tool Tool, input map[string]any) {
+	if tz, _ := input["timezone"].(string); tz != "" {
+		return
+	}
+	tz := ctx.Request.Header.Get(TimezoneHeader)
+	if tz == "" || !schemaHasProperty(tool.InputSchema, "timezone") {
+		return
+	}
+	input["timezone"] = tz
+}
+
+// applyInputHooks runs the configured hooks on input and returns the result
+func (x *executor) applyInputHooks(tool Tool, input json.RawMessage) json.RawMessage {
+	if len(x.cfg.inputHooks) == 0 {
+		return input
+	}
+
+	var obj map[string]any
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber() // keep large integers intact when re-encoding
+	if err := dec.Decode(&obj); err != nil || obj == nil {
+		return input
+	}
+
+	for _, hook := range x.cfg.inputHooks {
+		hook(x.ctx, tool, obj)
+	}
+
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return input
+	}
+	return out
+}
+
+// schemaHasProperty reports whether a JSON schema declares the named property
+func schemaHasProperty(schema any, name string) bool {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return false
+	}
+	var s struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return false
+	}
+	_, ok := s.Properties[name]
+	return ok
+}
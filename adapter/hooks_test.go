@@ -0,0 +1,56 @@
+package adapter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestWithInputHook tests that hooks see and modify tool inputs before the handler
+func TestWithInputHook(t *testing.T) {
+	var received string
+	echo := NewTool("echo", "Echo", objectSchema, func(input json.RawMessage) (any, error) {
+		received = string(input)
+		return nil, nil
+	})
+	hook := func(ctx *blaze.Context, tool Tool, input map[string]any) {
+		input["tool"] = tool.Name
+	}
+
+	// OpenAI passes arguments through as raw JSON, so large integers must survive the hook
+	postJSON(t, OpenAIAdapterWithOptions([]Tool{echo}, WithInputHook(hook)), OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{
+			Role:      "assistant",
+			ToolCalls: []OpenAIToolCall{{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "echo", Arguments: `{"id":12345678901234567}`}}},
+		}},
+	})
+
+	if !strings.Contains(received, `"tool":"echo"`) || !strings.Contains(received, `"id":12345678901234567`) {
+		t.Errorf("Expected hooked input with intact numbers, got %s", received)
+	}
+}
+
+// TestWithDefaultTimezone_SchemaCheck tests that only tools declaring a timezone are augmented
+func TestWithDefaultTimezone_SchemaCheck(t *testing.T) {
+	var received string
+	plain := NewTool("plain", "No timezone", objectSchema, func(input json.RawMessage) (any, error) {
+		received = string(input)
+		return nil, nil
+	})
+
+	postJSON(t, OpenAIAdapterWithOptions([]Tool{plain}, WithDefaultTimezone()), OpenAIChatRequest{
+		Model:    "gpt-4",
+		Timezone: "Asia/Tokyo",
+		Messages: []OpenAIMessage{{
+			Role:      "assistant",
+			ToolCalls: []OpenAIToolCall{{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "plain", Arguments: "{}"}}},
+		}},
+	})
+
+	if received != "{}" {
+		t.Errorf("Expected input without timezone property to be untouched, got %s", received)
+	}
+}
@@ -0,0 +1,113 @@
+package adapter
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Tool Metrics
+// ============================================================================
+
+// latencyWindow is the number of recent calls per tool that latency
+// percentiles are computed over
+const latencyWindow = 1024
+
+// ToolStat summarizes the calls of one tool. Percentiles cover the most
+// recent 1024 calls.
+type ToolStat struct {
+	Calls  int64         `json:"calls"`
+	Errors int64         `json:"errors"`
+	P50    time.Duration `json:"p50_ns"`
+	P95    time.Duration `json:"p95_ns"`
+	P99    time.Duration `json:"p99_ns"`
+	Max    time.Duration `json:"max_ns"`
+}
+
+// toolMetrics collects call statistics for every tool run by any adapter
+var toolMetrics = struct {
+	mu    sync.Mutex
+	tools map[string]*toolSamples
+}{tools: make(map[string]*toolSamples)}
+
+// toolSamples holds the counts and a ring of recent latencies of a tool
+type toolSamples struct {
+	calls, errors int64
+	max           time.Duration
+	latencies     []time.Duration
+	next          int // ring position once latencies is full
+}
+
+// observeTool records one call of the named tool
+func observeTool(name string, latency time.Duration, failed bool) {
+	toolMetrics.mu.Lock()
+	defer toolMetrics.mu.Unlock()
+
+	s := toolMetrics.tools[name]
+	if s == nil {
+		s = &toolSamples{}
+		toolMetrics.tools[name] = s
+	}
+	s.calls++
+	if failed {
+		s.errors++
+	}
+	s.max = max(s.max, latency)
+	if len(s.latencies) < latencyWindow {
+		s.latencies = append(s.latencies, latency)
+		return
+	}
+	s.latencies[s.next] = latency
+	s.next = (s.next + 1) % latencyWindow
+}
+
+// ToolMetrics returns call statistics for every tool invoked so far, by
+// tool name, across all adapters and the exec endpoint. Calls refused
+// before reaching the tool, such as unknown tools or limits, aren't
+// counted; errors include panics.
+func ToolMetrics() map[string]ToolStat {
+	toolMetrics.mu.Lock()
+	defer toolMetrics.mu.Unlock()
+
+	stats := make(map[string]ToolStat, len(toolMetrics.tools))
+	for name, s := range toolMetrics.tools {
+		sorted := slices.Clone(s.latencies)
+		slices.Sort(sorted)
+		stats[name] = ToolStat{
+			Calls:  s.calls,
+			Errors: s.errors,
+			P50:    percentile(sorted, 0.50),
+			P95:    percentile(sorted, 0.95),
+			P99:    percentile(sorted, 0.99),
+			Max:    s.max,
+		}
+	}
+	return stats
+}
+
+// ResetToolMetrics discards the statistics collected so far
+func ResetToolMetrics() {
+	toolMetrics.mu.Lock()
+	defer toolMetrics.mu.Unlock()
+	toolMetrics.tools = make(map[string]*toolSamples)
+}
+
+// ToolMetricsHandler serves ToolMetrics as JSON, with latencies in
+// nanoseconds
+func ToolMetricsHandler() blaze.HandlerFunc {
+	return func(ctx *blaze.Context) error {
+		return ctx.JSON(200, ToolMetrics())
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
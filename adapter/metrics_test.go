@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestToolMetrics tests that calls are counted per tool and errors tallied
+// separately
+func TestToolMetrics(t *testing.T) {
+	ResetToolMetrics()
+	defer ResetToolMetrics()
+
+	ok := NewTool("metrics_ok", "Succeeds", nil, func(input json.RawMessage) (any, error) {
+		time.Sleep(time.Millisecond)
+		return map[string]any{"ok": true}, nil
+	})
+	fail := NewTool("metrics_fail", "Fails", nil, func(input json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+	panics := NewTool("metrics_panic", "Panics", nil, func(input json.RawMessage) (any, error) {
+		panic("boom")
+	})
+
+	call := func(name string) OpenAIToolCall {
+		return OpenAIToolCall{ID: "call_" + name, Type: "function", Function: OpenAIFunctionCall{Name: name, Arguments: `{}`}}
+	}
+	req := OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{Role: "assistant", ToolCalls: []OpenAIToolCall{
+			call("metrics_ok"), call("metrics_ok"), call("metrics_fail"), call("metrics_panic"), call("metrics_missing"),
+		}}},
+	}
+	postJSON(t, OpenAIAdapter(ok, fail, panics), req)
+	postJSON(t, AnthropicAdapter(ok), AnthropicChatRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "metrics_ok", Input: map[string]any{}},
+		}}},
+	})
+
+	stats := ToolMetrics()
+	if s := stats["metrics_ok"]; s.Calls != 3 || s.Errors != 0 {
+		t.Errorf("Expected 3 calls and no errors across adapters, got %+v", s)
+	}
+	if s := stats["metrics_ok"]; s.P50 < time.Millisecond || s.P99 < s.P50 || s.Max < s.P99 {
+		t.Errorf("Expected ordered latencies of at least 1ms, got %+v", s)
+	}
+	if s := stats["metrics_fail"]; s.Calls != 1 || s.Errors != 1 {
+		t.Errorf("Expected the failure to be tallied, got %+v", s)
+	}
+	if s := stats["metrics_panic"]; s.Calls != 1 || s.Errors != 1 {
+		t.Errorf("Expected the panic to be tallied as an error, got %+v", s)
+	}
+	if _, found := stats["metrics_missing"]; found {
+		t.Error("Expected unknown tools not to be counted")
+	}
+}
+
+// TestToolMetricsHandler tests the JSON rendering of the metrics
+func TestToolMetricsHandler(t *testing.T) {
+	ResetToolMetrics()
+	defer ResetToolMetrics()
+	observeTool("metrics_json", 2*time.Millisecond, true)
+
+	e := blaze.New()
+	e.GET("/metrics/tools", ToolMetricsHandler())
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/tools", nil))
+
+	var stats map[string]ToolStat
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", rec.Body.String(), err)
+	}
+	want := ToolStat{Calls: 1, Errors: 1, P50: 2 * time.Millisecond, P95: 2 * time.Millisecond, P99: 2 * time.Millisecond, Max: 2 * time.Millisecond}
+	if stats["metrics_json"] != want {
+		t.Errorf("Expected %+v, got %+v", want, stats["metrics_json"])
+	}
+}
+
+// TestPercentile tests nearest-rank percentiles
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	for p, want := range map[float64]time.Duration{0.50: 50, 0.95: 95, 0.99: 99} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(%v) = %d, want %d", p, got, want)
+		}
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("Expected 0 without samples, got %d", got)
+	}
+}
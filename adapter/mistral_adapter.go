@@ -0,0 +1,124 @@
+package adapter
+
+import (
+	"crypto/sha256"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Mistral Adapter
+// ============================================================================
+
+// Mistral's chat API is OpenAI-compatible: requests and responses use the
+// OpenAI structs, and tools are described as by Tool.ToOpenAI. The
+// differences are handled while decoding and by the adapter:
+//   - tool call arguments may be a JSON object instead of a string
+//   - tool call IDs must be exactly 9 letters or digits
+//   - a final assistant message with "prefix": true starts the reply
+
+// mistralToolCallIDLen is the length Mistral requires of tool call IDs
+const mistralToolCallIDLen = 9
+
+// mistralIDAlphabet holds the characters allowed in Mistral tool call IDs
+const mistralIDAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// MistralAdapter creates a Blaze handler that processes Mistral-format
+// requests and executes registered tools
+func MistralAdapter(tools ...Tool) blaze.HandlerFunc {
+	return MistralAdapterWithOptions(tools)
+}
+
+// MistralAdapterWithOptions is like MistralAdapter but accepts Options such
+// as WithToolLimits
+func MistralAdapterWithOptions(tools []Tool, opts ...Option) blaze.HandlerFunc {
+	// Only the request handed to the fallback gets Mistral IDs; results and
+	// sessions keep the IDs the client sent
+	opts = append(slices.Clip(opts), func(c *config) {
+		next := c.fallback
+		if next == nil {
+			return
+		}
+		c.fallback = func(ctx *blaze.Context, req any) error {
+			if r, ok := req.(OpenAIChatRequest); ok {
+				r.Messages = mistralToolCallIDs(r.Messages)
+				req = r
+			}
+			return next(ctx, req)
+		}
+	})
+	return NewAdapter(openAISpec, tools, opts...)
+}
+
+// mistralToolCallIDs returns a copy of messages in which tool call IDs that
+// Mistral would reject are replaced by 9-character ones, along with the tool
+// messages answering them. messages itself is left untouched. A client ID
+// always maps to the same Mistral ID, so a session replayed on later
+// requests stays consistent.
+func mistralToolCallIDs(messages []OpenAIMessage) []OpenAIMessage {
+	out := slices.Clone(messages)
+	renamed := map[string]string{} // client ID -> Mistral ID
+	for i := range out {
+		if len(out[i].ToolCalls) > 0 {
+			out[i].ToolCalls = slices.Clone(out[i].ToolCalls)
+		}
+		for j := range out[i].ToolCalls {
+			tc := &out[i].ToolCalls[j]
+			if isMistralToolCallID(tc.ID) {
+				continue
+			}
+			if tc.ID == "" {
+				tc.ID = newMistralToolCallID()
+				continue
+			}
+			id := mistralToolCallIDFor(tc.ID)
+			renamed[tc.ID] = id
+			tc.ID = id
+		}
+		if out[i].Role != "tool" || isMistralToolCallID(out[i].ToolCallID) {
+			continue
+		}
+		if id, ok := renamed[out[i].ToolCallID]; ok {
+			out[i].ToolCallID = id
+		} else if out[i].ToolCallID != "" {
+			out[i].ToolCallID = mistralToolCallIDFor(out[i].ToolCallID)
+		}
+	}
+	return out
+}
+
+// isMistralToolCallID reports whether id is 9 ASCII letters or digits
+func isMistralToolCallID(id string) bool {
+	if len(id) != mistralToolCallIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// mistralToolCallIDFor derives the 9-character alphanumeric ID that stands
+// in for the client ID id
+func mistralToolCallIDFor(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	out := make([]byte, mistralToolCallIDLen)
+	for i := range out {
+		out[i] = mistralIDAlphabet[int(sum[i])%len(mistralIDAlphabet)]
+	}
+	return string(out)
+}
+
+// newMistralToolCallID returns a random 9-character alphanumeric ID
+func newMistralToolCallID() string {
+	id := make([]byte, mistralToolCallIDLen)
+	for i := range id {
+		id[i] = mistralIDAlphabet[rand.IntN(len(mistralIDAlphabet))]
+	}
+	return string(id)
+}
@@ -0,0 +1,153 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestMistralAdapter_ToolExecution tests a tool call with object arguments
+func TestMistralAdapter_ToolExecution(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", nil, func(input json.RawMessage) (any, error) {
+		var v map[string]any
+		if err := json.Unmarshal(input, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+
+	rec := postJSON(t, MistralAdapter(echoTool), map[string]any{
+		"model": "mistral-large-latest",
+		"messages": []map[string]any{
+			{"role": "user", "content": "Echo hello"},
+			{"role": "assistant", "content": "", "tool_calls": []map[string]any{{
+				"id":       "D681PevKs",
+				"function": map[string]any{"name": "echo", "arguments": map[string]any{"message": "hello"}},
+			}}},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp OpenAIChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Choices) != 1 || !strings.Contains(resp.Choices[0].Message.Content, `{"message":"hello"}`) {
+		t.Errorf("Expected echoed arguments, got: %+v", resp.Choices)
+	}
+}
+
+// TestMistralAdapter_NoToolCalls tests the info response, including a prefix message
+func TestMistralAdapter_NoToolCalls(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", nil, nil)
+
+	rec := postJSON(t, MistralAdapter(echoTool), map[string]any{
+		"model": "mistral-large-latest",
+		"messages": []map[string]any{
+			{"role": "user", "content": "Hello"},
+			{"role": "assistant", "content": "Bonjour! ", "prefix": true},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp OpenAIChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	content := resp.Choices[0].Message.Content
+	if !strings.HasPrefix(content, "Bonjour! ") || !strings.Contains(content, "1 tools") || !strings.Contains(content, "Your message: Hello") {
+		t.Errorf("Expected prefixed info response, got: %s", content)
+	}
+}
+
+// TestMistralToolCallIDs tests that invalid IDs are replaced consistently in a copy
+func TestMistralToolCallIDs(t *testing.T) {
+	messages := []OpenAIMessage{
+		{Role: "assistant", ToolCalls: []OpenAIToolCall{
+			{ID: "call_abc123", Function: OpenAIFunctionCall{Name: "echo"}},
+			{ID: "Ab3dE6gH9", Function: OpenAIFunctionCall{Name: "echo"}},
+			{Function: OpenAIFunctionCall{Name: "echo"}},
+		}},
+		{Role: "tool", ToolCallID: "call_abc123", Content: "{}"},
+	}
+	out := mistralToolCallIDs(messages)
+
+	calls := out[0].ToolCalls
+	for _, tc := range calls {
+		if !isMistralToolCallID(tc.ID) {
+			t.Errorf("Expected a 9-character alphanumeric ID, got %q", tc.ID)
+		}
+	}
+	if calls[1].ID != "Ab3dE6gH9" {
+		t.Errorf("Expected a valid ID to be kept, got %q", calls[1].ID)
+	}
+	if out[1].ToolCallID != calls[0].ID {
+		t.Errorf("Expected tool message to follow the renamed call, got %q want %q", out[1].ToolCallID, calls[0].ID)
+	}
+	if messages[0].ToolCalls[0].ID != "call_abc123" || messages[0].ToolCalls[2].ID != "" || messages[1].ToolCallID != "call_abc123" {
+		t.Errorf("Expected the client's messages to be left untouched, got %+v", messages)
+	}
+	if again := mistralToolCallIDs(messages); again[0].ToolCalls[0].ID != calls[0].ID {
+		t.Errorf("Expected the same Mistral ID for a client ID, got %q and %q", again[0].ToolCalls[0].ID, calls[0].ID)
+	}
+}
+
+// TestMistralAdapter_SessionKeepsClientIDs tests that results and sessions
+// keep the client's tool call IDs while the fallback gets Mistral ones
+func TestMistralAdapter_SessionKeepsClientIDs(t *testing.T) {
+	store := newMapStore()
+	var forwarded OpenAIChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(OpenAIChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+	h := MistralAdapterWithOptions([]Tool{
+		NewTool("echo", "Echo", objectSchema, func(input json.RawMessage) (any, error) {
+			return "echoed", nil
+		}),
+	}, WithSessions(store, time.Minute), WithFallback(backend))
+
+	rec := postJSON(t, h, map[string]any{
+		"model":      "mistral-large-latest",
+		"session_id": "s1",
+		"messages": []map[string]any{
+			{"role": "assistant", "content": "", "tool_calls": []map[string]any{{
+				"id":       "call_abc123",
+				"function": map[string]any{"name": "echo", "arguments": "{}"},
+			}}},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	postJSON(t, h, map[string]any{
+		"model":      "mistral-large-latest",
+		"session_id": "s1",
+		"messages":   []map[string]any{{"role": "user", "content": "Thanks"}},
+	})
+
+	var history []OpenAIMessage
+	if err := json.Unmarshal([]byte(store.values[sessionKey("s1")].(string)), &history); err != nil {
+		t.Fatalf("Failed to parse stored session: %v", err)
+	}
+	if len(history) != 3 || history[0].ToolCalls[0].ID != "call_abc123" || history[1].ToolCallID != "call_abc123" {
+		t.Errorf("Expected the stored history to keep the client's IDs, got %+v", history)
+	}
+
+	if len(forwarded.Messages) != 3 {
+		t.Fatalf("Expected 3 forwarded messages, got %d: %+v", len(forwarded.Messages), forwarded.Messages)
+	}
+	id := forwarded.Messages[0].ToolCalls[0].ID
+	if !isMistralToolCallID(id) || forwarded.Messages[1].ToolCallID != id {
+		t.Errorf("Expected Mistral IDs in the forwarded request, got %+v", forwarded.Messages)
+	}
+}
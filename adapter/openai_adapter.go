@@ -1,11 +1,16 @@
 package adapter
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dvictor357/blaze"
+	"github.com/dvictor357/blaze/internal/docfmt"
 )
 
 // ============================================================================
@@ -31,6 +36,77 @@ type OpenAIMessage struct {
 	Content    string           `json:"content,omitempty"`
 	ToolCalls  []OpenAIToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string           `json:"tool_call_id,omitempty"`
+
+	// FunctionCall is the legacy single call of an assistant message, and
+	// Name the function a legacy "function" role result belongs to
+	FunctionCall *OpenAIFunctionCall `json:"function_call,omitempty"`
+	Name         string              `json:"name,omitempty"`
+
+	// Prefix marks a final assistant message as the start of the reply
+	// (Mistral)
+	Prefix bool `json:"prefix,omitempty"`
+
+	// Parts holds content sent as an array of parts (text, image_url).
+	// Content then contains the joined text of those parts.
+	Parts []OpenAIContentPart `json:"-"`
+}
+
+// OpenAIContentPart represents one part of a multimodal message
+type OpenAIContentPart struct {
+	Type     string          `json:"type"` // "text" or "image_url"
+	Text     string          `json:"text,omitempty"`
+	ImageURL *OpenAIImageURL `json:"image_url,omitempty"`
+}
+
+// OpenAIImageURL is the payload of an image_url part
+type OpenAIImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// UnmarshalJSON accepts content as either a string or an array of parts
+func (m *OpenAIMessage) UnmarshalJSON(data []byte) error {
+	type plain OpenAIMessage
+	var raw struct {
+		plain
+		Content json.RawMessage `json:"content,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = OpenAIMessage(raw.plain)
+
+	content := strings.TrimSpace(string(raw.Content))
+	switch {
+	case content == "" || content == "null":
+		return nil
+	case content[0] == '[':
+		if err := json.Unmarshal(raw.Content, &m.Parts); err != nil {
+			return err
+		}
+		var texts []string
+		for _, part := range m.Parts {
+			if part.Type == "text" {
+				texts = append(texts, part.Text)
+			}
+		}
+		m.Content = strings.Join(texts, "\n")
+		return nil
+	default:
+		return json.Unmarshal(raw.Content, &m.Content)
+	}
+}
+
+// MarshalJSON writes content as an array when the message holds Parts
+func (m OpenAIMessage) MarshalJSON() ([]byte, error) {
+	type plain OpenAIMessage
+	if m.Parts == nil {
+		return json.Marshal(plain(m))
+	}
+	return json.Marshal(struct {
+		plain
+		Content []OpenAIContentPart `json:"content"`
+	}{plain(m), m.Parts})
 }
 
 // OpenAIToolCall represents a tool call from the assistant
@@ -46,12 +122,58 @@ type OpenAIFunctionCall struct {
 	Arguments string `json:"arguments"` // JSON string
 }
 
+// UnmarshalJSON accepts arguments as a JSON string or, as Mistral may send
+// them, as an object, which is kept in its encoded form
+func (f *OpenAIFunctionCall) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	f.Name = raw.Name
+	f.Arguments = ""
+
+	args := strings.TrimSpace(string(raw.Arguments))
+	switch {
+	case args == "" || args == "null":
+		return nil
+	case args[0] == '"':
+		return json.Unmarshal(raw.Arguments, &f.Arguments)
+	default:
+		f.Arguments = args
+		return nil
+	}
+}
+
 // OpenAIChatRequest represents an OpenAI chat completion request
 type OpenAIChatRequest struct {
-	Model    string          `json:"model"`
-	Messages []OpenAIMessage `json:"messages"`
-	Tools    []OpenAIToolDef `json:"tools,omitempty"`
-	Stream   bool            `json:"stream,omitempty"`
+	Model         string          `json:"model"`
+	Messages      []OpenAIMessage `json:"messages"`
+	Tools         []OpenAIToolDef `json:"tools,omitempty"`
+	ToolChoice    json.RawMessage `json:"tool_choice,omitempty"`
+	Stream        bool            `json:"stream,omitempty"`
+	SessionID     string          `json:"session_id,omitempty"`
+	ToolCallDepth int             `json:"tool_call_depth,omitempty"`
+	Timezone      string          `json:"timezone,omitempty"`
+	DryRun        bool            `json:"dry_run,omitempty"`
+
+	// Legacy equivalents of Tools and ToolChoice
+	Functions    []OpenAIFunctionDef `json:"functions,omitempty"`
+	FunctionCall json.RawMessage     `json:"function_call,omitempty"`
+}
+
+// SystemPrompt returns the content of all system and developer messages,
+// joined in order
+func (r OpenAIChatRequest) SystemPrompt() string {
+	var parts []string
+	for _, msg := range r.Messages {
+		if msg.Role == "system" || msg.Role == "developer" {
+			parts = append(parts, msg.Content)
+		}
+	}
+	return strings.Join(parts, "\n")
 }
 
 // OpenAIChatResponse represents an OpenAI chat completion response
@@ -133,95 +255,158 @@ func (t Tool) ToAnthropic() map[string]any {
 // OpenAIAdapter creates a Blaze handler that processes OpenAI-format requests
 // and executes registered tools
 func OpenAIAdapter(tools ...Tool) blaze.HandlerFunc {
-	toolMap := make(map[string]Tool)
-	for _, tool := range tools {
-		toolMap[tool.Name] = tool
-	}
+	return OpenAIAdapterWithOptions(tools)
+}
 
-	return func(ctx *blaze.Context) error {
-		var req OpenAIChatRequest
-		if err := ctx.BindJSON(&req); err != nil {
-			return ctx.JSON(400, map[string]any{
-				"error": map[string]any{
-					"message": fmt.Sprintf("Invalid request: %v", err),
-					"type":    "invalid_request_error",
-				},
-			})
-		}
+// OpenAIAdapterWithOptions is like OpenAIAdapter but accepts Options such as
+// WithToolLimits
+func OpenAIAdapterWithOptions(tools []Tool, opts ...Option) blaze.HandlerFunc {
+	return NewAdapter(openAISpec, tools, opts...)
+}
 
+// openAICall is a tool call with whether it came from a legacy
+// function_call, whose result uses the "function" role
+type openAICall struct {
+	OpenAIToolCall
+	legacy bool
+}
+
+// openAISpec describes the OpenAI Chat Completions API for NewAdapter. Tool
+// calls come from the last assistant message that has any; each result is a
+// tool message.
+var openAISpec = ProviderSpec[OpenAIChatRequest, OpenAIMessage, openAICall, OpenAIMessage]{
+	Validate: func(req OpenAIChatRequest) string {
 		if len(req.Messages) == 0 {
-			return ctx.JSON(400, map[string]any{
-				"error": map[string]any{
-					"message": "Messages array is required",
-					"type":    "invalid_request_error",
-				},
-			})
+			return "Messages array is required"
 		}
-
-		// Find tool calls in the last assistant message
-		var toolCalls []OpenAIToolCall
-		for i := len(req.Messages) - 1; i >= 0; i-- {
-			msg := req.Messages[i]
-			if msg.Role == "assistant" && len(msg.ToolCalls) > 0 {
-				toolCalls = msg.ToolCalls
-				break
-			}
+		return ""
+	},
+	BadRequest: func(ctx *blaze.Context, message string) error {
+		return ctx.JSON(400, OpenAIErrorEnvelope(blaze.NewHTTPError(400, message)))
+	},
+	Info: func(req OpenAIChatRequest) RequestInfo {
+		return RequestInfo{
+			Model:         req.Model,
+			Stream:        req.Stream,
+			SessionID:     req.SessionID,
+			ToolCallDepth: req.ToolCallDepth,
+			Timezone:      req.Timezone,
+			DryRun:        req.DryRun,
 		}
-
-		// If no tool calls found, return available tools info
-		if len(toolCalls) == 0 {
-			return handleNoToolCalls(ctx, req, tools)
+	},
+	Messages: func(req *OpenAIChatRequest) *[]OpenAIMessage {
+		return &req.Messages
+	},
+	ToolCalls: func(req OpenAIChatRequest) []openAICall {
+		toolCalls, legacy := lastToolCalls(req.Messages)
+		calls := make([]openAICall, len(toolCalls))
+		for i, tc := range toolCalls {
+			calls[i] = openAICall{tc, legacy}
 		}
+		return calls
+	},
+	Call: func(call openAICall) (string, json.RawMessage) {
+		return call.Function.Name, json.RawMessage(call.Function.Arguments)
+	},
+	Result: func(call openAICall, outcome ToolOutcome) OpenAIMessage {
+		if call.legacy {
+			return OpenAIMessage{Role: "function", Name: call.Function.Name, Content: outcome.Content}
+		}
+		return OpenAIMessage{Role: "tool", ToolCallID: call.ID, Content: outcome.Content}
+	},
+	Text: func(text string) OpenAIMessage {
+		return OpenAIMessage{Role: "assistant", Content: text}
+	},
+	Conversation: func(req OpenAIChatRequest, results []OpenAIMessage) []OpenAIMessage {
+		return append(req.Messages, results...)
+	},
+	NoToolCalls: handleNoToolCalls,
+	Send: func(ctx *blaze.Context, req OpenAIChatRequest, results []OpenAIMessage) error {
+		return sendOpenAIResponse(ctx, req.Model, results)
+	},
+	Stream: func(ctx *blaze.Context, req OpenAIChatRequest, run func(ProgressFunc) []OpenAIMessage, heartbeat time.Duration) error {
+		return streamOpenAIResponse(ctx, req.Model, run, heartbeat)
+	},
+}
 
-		// Execute each tool call
-		toolResults := make([]OpenAIMessage, 0, len(toolCalls))
-		for _, tc := range toolCalls {
-			tool, exists := toolMap[tc.Function.Name]
-			if !exists {
-				toolResults = append(toolResults, OpenAIMessage{
-					Role:       "tool",
-					ToolCallID: tc.ID,
-					Content:    fmt.Sprintf(`{"error": "Tool '%s' not found"}`, tc.Function.Name),
-				})
-				continue
-			}
+// lastToolCalls returns the tool calls of the last assistant message that
+// has any, unless a tool or function message after it already answered
+// them. legacy reports that they come from a function_call field, whose
+// results use the "function" role.
+func lastToolCalls(messages []OpenAIMessage) (calls []OpenAIToolCall, legacy bool) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role == "tool" || msg.Role == "function" {
+			return nil, false
+		}
+		if msg.Role != "assistant" {
+			continue
+		}
+		if len(msg.ToolCalls) > 0 {
+			return msg.ToolCalls, false
+		}
+		if msg.FunctionCall != nil && msg.FunctionCall.Name != "" {
+			return []OpenAIToolCall{{Type: "function", Function: *msg.FunctionCall}}, true
+		}
+	}
+	return nil, false
+}
 
-			// Execute the tool handler
-			result, err := tool.Handler(json.RawMessage(tc.Function.Arguments))
-			if err != nil {
-				toolResults = append(toolResults, OpenAIMessage{
-					Role:       "tool",
-					ToolCallID: tc.ID,
-					Content:    fmt.Sprintf(`{"error": "%v"}`, err),
-				})
-				continue
-			}
+// chosenTools narrows tools to those tool_choice (or the legacy
+// function_call) allows: none for "none", the named one for a specific
+// function, and all of them otherwise. note describes the narrowing.
+func chosenTools(req OpenAIChatRequest, tools []Tool) (allowed []Tool, note string) {
+	choice := req.ToolChoice
+	if len(choice) == 0 {
+		choice = req.FunctionCall
+	}
 
-			// Convert result to JSON string
-			resultBytes, _ := json.Marshal(result)
-			toolResults = append(toolResults, OpenAIMessage{
-				Role:       "tool",
-				ToolCallID: tc.ID,
-				Content:    string(resultBytes),
-			})
+	var mode string
+	if json.Unmarshal(choice, &mode) == nil {
+		if mode == "none" {
+			return nil, " Tool choice is none."
 		}
+		return tools, ""
+	}
 
-		// Return response based on streaming preference
-		if req.Stream {
-			return streamOpenAIResponse(ctx, req.Model, toolResults)
+	// {"type": "function", "function": {"name": ...}}, or legacy {"name": ...}
+	var named struct {
+		Name     string `json:"name"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if json.Unmarshal(choice, &named) != nil {
+		return tools, ""
+	}
+	name := cmp.Or(named.Function.Name, named.Name)
+	if name == "" {
+		return tools, ""
+	}
+	for _, t := range tools {
+		if t.Name == name {
+			return []Tool{t}, fmt.Sprintf(" Tool choice requires %s.", name)
 		}
-		return sendOpenAIResponse(ctx, req.Model, toolResults)
 	}
+	return nil, fmt.Sprintf(" Tool choice requires %s, which is not available.", name)
 }
 
 // handleNoToolCalls returns a response when no tool calls are present
 func handleNoToolCalls(ctx *blaze.Context, req OpenAIChatRequest, tools []Tool) error {
+	tools, choiceNote := chosenTools(req, tools)
+
 	// Build tool list for response
 	toolDefs := make([]OpenAIToolDef, len(tools))
 	for i, t := range tools {
 		toolDefs[i] = t.ToOpenAI()
 	}
 
+	// A final prefix message is the start of the reply
+	var prefix string
+	if last := req.Messages[len(req.Messages)-1]; last.Role == "assistant" && last.Prefix {
+		prefix = last.Content
+	}
+
 	// Get last user message
 	var lastUserContent string
 	for i := len(req.Messages) - 1; i >= 0; i-- {
@@ -241,7 +426,7 @@ func handleNoToolCalls(ctx *blaze.Context, req OpenAIChatRequest, tools []Tool)
 				Index: 0,
 				Message: OpenAIMessage{
 					Role:    "assistant",
-					Content: fmt.Sprintf("I have access to %d tools. To use them, include tool_calls in your request. Your message: %s", len(tools), lastUserContent),
+					Content: prefix + fmt.Sprintf("I have access to %d tools. To use them, include tool_calls in your request.%s%s Your message: %s", len(tools), choiceNote, systemNote(req.SystemPrompt()), lastUserContent),
 				},
 				FinishReason: "stop",
 			},
@@ -289,8 +474,10 @@ func sendOpenAIResponse(ctx *blaze.Context, model string, toolResults []OpenAIMe
 	return ctx.JSON(200, response)
 }
 
-// streamOpenAIResponse sends a streaming SSE response
-func streamOpenAIResponse(ctx *blaze.Context, model string, toolResults []OpenAIMessage) error {
+// streamOpenAIResponse sends a streaming SSE response. Tools run inside the
+// stream so their progress messages are sent as content chunks before the
+// results.
+func streamOpenAIResponse(ctx *blaze.Context, model string, run func(ProgressFunc) []OpenAIMessage, heartbeat time.Duration) error {
 	ch := make(chan any)
 
 	go func() {
@@ -316,6 +503,27 @@ func streamOpenAIResponse(ctx *blaze.Context, model string, toolResults []OpenAI
 			},
 		}
 
+		// Relay progress as content chunks while the tools run
+		progress, stop := guardProgress(func(msg string) {
+			ch <- OpenAIStreamChunk{
+				ID:      id,
+				Object:  "chat.completion.chunk",
+				Created: created,
+				Model:   model,
+				Choices: []OpenAIStreamChoice{
+					{
+						Index: 0,
+						Delta: OpenAIDelta{
+							Content: msg + "\n",
+						},
+						FinishReason: nil,
+					},
+				},
+			}
+		})
+		toolResults := run(progress)
+		stop()
+
 		// Send content chunks for each tool result
 		for _, result := range toolResults {
 			ch <- OpenAIStreamChunk{
@@ -352,7 +560,7 @@ func streamOpenAIResponse(ctx *blaze.Context, model string, toolResults []OpenAI
 		}
 	}()
 
-	return ctx.StreamJSON(ch)
+	return streamJSON(ctx, ch, heartbeat)
 }
 
 // ============================================================================
@@ -366,7 +574,10 @@ type ToolListResponse struct {
 	Count     int              `json:"count"`
 }
 
-// ListToolsHandler creates a handler that returns available tools in multiple formats
+// ListToolsHandler creates a handler that returns available tools in multiple
+// formats. The response is JSON, or YAML when the Accept header prefers
+// application/yaml. With ?download=1 it is sent as an attachment
+// (tools.json or tools.yaml).
 func ListToolsHandler(tools ...Tool) blaze.HandlerFunc {
 	return func(ctx *blaze.Context) error {
 		openaiTools := make([]OpenAIToolDef, len(tools))
@@ -376,19 +587,79 @@ func ListToolsHandler(tools ...Tool) blaze.HandlerFunc {
 			openaiTools[i] = t.ToOpenAI()
 			anthropicTools[i] = t.ToAnthropic()
 		}
-
-		return ctx.JSON(200, ToolListResponse{
+		resp := ToolListResponse{
 			OpenAI:    openaiTools,
 			Anthropic: anthropicTools,
 			Count:     len(tools),
-		})
+		}
+
+		format := toolListFormat(ctx.Request.Header.Get("Accept"))
+		// Add rather than set, keeping e.g. the Vary: Origin of CORS
+		ctx.ResponseWriter.Header().Add("Vary", "Accept")
+		if ctx.Query("download") == "1" {
+			ctx.SetHeader("Content-Disposition", fmt.Sprintf(`attachment; filename="tools.%s"`, format))
+		}
+
+		if format == "yaml" {
+			doc, err := docfmt.Normalize(resp)
+			if err != nil {
+				return err
+			}
+			out, err := docfmt.FormatYAML(doc)
+			if err != nil {
+				return err
+			}
+			return ctx.Blob(200, "application/yaml", []byte(out))
+		}
+		return ctx.JSON(200, resp)
+	}
+}
+
+// yamlMediaTypes are the media types that select YAML output
+var yamlMediaTypes = []string{"application/yaml", "application/x-yaml", "text/yaml"}
+
+// toolListFormat returns "yaml" when accept gives a YAML media type a
+// higher quality than JSON, and "json" otherwise
+func toolListFormat(accept string) string {
+	jsonQ, yamlQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+
+		switch {
+		case slices.Contains(yamlMediaTypes, mediaType):
+			yamlQ = max(yamlQ, q)
+		case mediaType == "application/json", mediaType == "application/*", mediaType == "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	if yamlQ > 0 && yamlQ > jsonQ {
+		return "yaml"
 	}
+	return "json"
 }
 
 // ============================================================================
 // Helpers
 // ============================================================================
 
+// systemNote acknowledges a system prompt in the no-tool-call info response
+func systemNote(prompt string) string {
+	if prompt == "" {
+		return ""
+	}
+	return fmt.Sprintf(" System prompt received (%d chars).", len(prompt))
+}
+
 // generateID creates a unique ID with the given prefix
 func generateID(prefix string) string {
 	return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
@@ -261,6 +261,84 @@ func TestListToolsHandler(t *testing.T) {
 	}
 }
 
+// TestListToolsHandler_YAML tests YAML output for a YAML Accept header
+func TestListToolsHandler_YAML(t *testing.T) {
+	tool := NewTool("echo", "Echo back the input", map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"message": map[string]any{"type": "string"}},
+	}, nil)
+
+	e := blaze.New()
+	e.GET("/tools", ListToolsHandler(tool))
+
+	req := httptest.NewRequest(http.MethodGet, "/tools", nil)
+	req.Header.Set("Accept", "application/json;q=0.5, application/yaml")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Errorf("Expected application/yaml, got %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"count: 1\n", "openai:\n  - function:\n", "      name: echo\n", "anthropic:\n  - description: Echo back the input\n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected YAML to contain %q, got:\n%s", want, body)
+		}
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Errorf("Expected no Content-Disposition without ?download=1")
+	}
+}
+
+// TestListToolsHandler_VaryBehindCORS tests that Vary: Accept is added to the
+// Vary: Origin set by the CORS middleware
+func TestListToolsHandler_VaryBehindCORS(t *testing.T) {
+	e := blaze.New()
+	e.Use(blaze.CORS(blaze.CORSConfig{AllowOrigins: []string{"https://app.example.com"}}))
+	e.GET("/tools", ListToolsHandler(NewTool("echo", "Echo", nil, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/tools", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	vary := strings.Join(rec.Header().Values("Vary"), ", ")
+	if !strings.Contains(vary, "Origin") || !strings.Contains(vary, "Accept") {
+		t.Errorf("Expected Vary to list Origin and Accept, got %q", vary)
+	}
+}
+
+// TestListToolsHandler_Download tests the attachment disposition
+func TestListToolsHandler_Download(t *testing.T) {
+	e := blaze.New()
+	e.GET("/tools", ListToolsHandler(NewTool("echo", "Echo", nil, nil)))
+
+	tests := []struct {
+		accept      string
+		disposition string
+		contentType string
+	}{
+		{"", `attachment; filename="tools.json"`, "application/json"},
+		{"text/yaml", `attachment; filename="tools.yaml"`, "application/yaml"},
+		{"application/yaml;q=0.2, */*", `attachment; filename="tools.json"`, "application/json"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/tools?download=1", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Content-Disposition"); got != tt.disposition {
+			t.Errorf("Accept %q: expected disposition %q, got %q", tt.accept, tt.disposition, got)
+		}
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+			t.Errorf("Accept %q: expected content type %q, got %q", tt.accept, tt.contentType, got)
+		}
+	}
+}
+
 // TestToolToOpenAI tests the ToOpenAI conversion method
 func TestToolToOpenAI(t *testing.T) {
 	tool := NewTool(
@@ -313,3 +391,160 @@ func TestToolToAnthropic(t *testing.T) {
 		t.Error("Expected input_schema to be present")
 	}
 }
+
+// TestOpenAIAdapter_SystemMessages tests that system/developer messages are forwarded
+func TestOpenAIAdapter_SystemMessages(t *testing.T) {
+	var forwarded OpenAIChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(OpenAIChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+
+	reqBody := OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "developer", Content: "Prefer metric units."},
+			{Role: "user", Content: "Hello"},
+		},
+	}
+
+	rec := postJSON(t, OpenAIAdapterWithOptions(nil, WithFallback(backend)), reqBody)
+	if !strings.Contains(rec.Body.String(), "forwarded") {
+		t.Fatalf("Expected request to reach the fallback, got: %s", rec.Body.String())
+	}
+	if forwarded.SystemPrompt() != "You are terse.\nPrefer metric units." {
+		t.Errorf("Expected system messages to be forwarded, got %q", forwarded.SystemPrompt())
+	}
+
+	var resp OpenAIChatResponse
+	json.Unmarshal(postJSON(t, OpenAIAdapter(), reqBody).Body.Bytes(), &resp)
+	if !strings.Contains(resp.Choices[0].Message.Content, "System prompt received") {
+		t.Errorf("Expected system prompt acknowledgement, got: %s", resp.Choices[0].Message.Content)
+	}
+}
+
+// TestOpenAIAdapter_ImageParts tests that image_url parts survive parsing and forwarding
+func TestOpenAIAdapter_ImageParts(t *testing.T) {
+	var forwarded OpenAIChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(OpenAIChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+
+	reqBody := map[string]any{
+		"model": "gpt-4o",
+		"messages": []map[string]any{
+			{"role": "user", "content": []map[string]any{
+				{"type": "text", "text": "What is in this image?"},
+				{"type": "image_url", "image_url": map[string]any{
+					"url": "data:image/png;base64,iVBORw0KGgo=", "detail": "high",
+				}},
+			}},
+		},
+	}
+
+	rec := postJSON(t, OpenAIAdapterWithOptions(nil, WithFallback(backend)), reqBody)
+	if !strings.Contains(rec.Body.String(), "forwarded") {
+		t.Fatalf("Expected request to reach the fallback, got: %s", rec.Body.String())
+	}
+
+	last := forwarded.Messages[len(forwarded.Messages)-1]
+	if last.Content != "What is in this image?" {
+		t.Errorf("Expected text content, got %q", last.Content)
+	}
+	if len(last.Parts) != 2 || last.Parts[1].ImageURL == nil {
+		t.Fatalf("Expected image part to be preserved, got %+v", last.Parts)
+	}
+	if last.Parts[1].ImageURL.URL != "data:image/png;base64,iVBORw0KGgo=" || last.Parts[1].ImageURL.Detail != "high" {
+		t.Errorf("Image part corrupted: %+v", last.Parts[1].ImageURL)
+	}
+
+	// Re-encoding keeps the array form
+	out, _ := json.Marshal(last)
+	if !strings.Contains(string(out), `"image_url":{"url":"data:image/png;base64,iVBORw0KGgo=","detail":"high"}`) {
+		t.Errorf("Expected image part in encoded message, got %s", out)
+	}
+}
+
+// TestOpenAIAdapter_LegacyFunctionCall tests that a legacy function_call in
+// the last assistant message is executed
+func TestOpenAIAdapter_LegacyFunctionCall(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", nil, func(input json.RawMessage) (any, error) {
+		var v map[string]any
+		json.Unmarshal(input, &v)
+		return v, nil
+	})
+
+	rec := postJSON(t, OpenAIAdapter(echoTool), map[string]any{
+		"model":     "gpt-3.5-turbo",
+		"functions": []map[string]any{{"name": "echo", "parameters": map[string]any{"type": "object"}}},
+		"messages": []map[string]any{
+			{"role": "user", "content": "Echo hi"},
+			{"role": "assistant", "content": nil, "function_call": map[string]any{"name": "echo", "arguments": `{"msg":"hi"}`}},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp OpenAIChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if content := resp.Choices[0].Message.Content; !strings.Contains(content, `{"msg":"hi"}`) {
+		t.Errorf("Expected echoed arguments, got: %s", content)
+	}
+
+	calls, legacy := lastToolCalls([]OpenAIMessage{
+		{Role: "assistant", FunctionCall: &OpenAIFunctionCall{Name: "echo", Arguments: "{}"}},
+	})
+	if !legacy || len(calls) != 1 || calls[0].Function.Name != "echo" {
+		t.Errorf("Expected one legacy call to echo, got legacy=%v calls=%+v", legacy, calls)
+	}
+}
+
+// TestOpenAIAdapter_ToolChoice tests that tool_choice narrows the advertised tools
+func TestOpenAIAdapter_ToolChoice(t *testing.T) {
+	tools := []Tool{
+		NewTool("echo", "Echo back the input", nil, nil),
+		NewTool("web_search", "Search the web", nil, nil),
+	}
+
+	tests := []struct {
+		name    string
+		body    map[string]any
+		want    string
+		wantNot string
+	}{
+		{"auto", map[string]any{"tool_choice": "auto"}, "2 tools", "Tool choice"},
+		{"none", map[string]any{"tool_choice": "none"}, "0 tools", ""},
+		{"function", map[string]any{"tool_choice": map[string]any{"type": "function", "function": map[string]any{"name": "web_search"}}}, "1 tools. To use them, include tool_calls in your request. Tool choice requires web_search.", ""},
+		{"legacy", map[string]any{"function_call": map[string]any{"name": "echo"}}, "Tool choice requires echo.", ""},
+		{"unknown", map[string]any{"tool_choice": map[string]any{"type": "function", "function": map[string]any{"name": "nope"}}}, "0 tools", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := map[string]any{
+				"model":    "gpt-4",
+				"messages": []map[string]any{{"role": "user", "content": "Hello"}},
+			}
+			for k, v := range tt.body {
+				body[k] = v
+			}
+			rec := postJSON(t, OpenAIAdapter(tools...), body)
+
+			var resp OpenAIChatResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			content := resp.Choices[0].Message.Content
+			if !strings.Contains(content, tt.want) {
+				t.Errorf("Expected content to contain %q, got: %s", tt.want, content)
+			}
+			if tt.wantNot != "" && strings.Contains(content, tt.wantNot) {
+				t.Errorf("Expected content not to contain %q, got: %s", tt.wantNot, content)
+			}
+		})
+	}
+}
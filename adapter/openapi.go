@@ -0,0 +1,141 @@
+package adapter
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// OpenAPI Spec
+// ============================================================================
+
+// OpenAPISpec builds an OpenAPI 3.1 document describing the ExecHandler
+// endpoints (POST /tools/{name}) for the given tools. Each tool's InputSchema
+// is registered under components/schemas and referenced as the request body.
+// Tools with Methods get an operation per method; GET and HEAD take the
+// schema's properties as query parameters.
+func OpenAPISpec(tools ...Tool) map[string]any {
+	paths := make(map[string]any, len(tools))
+	schemas := map[string]any{
+		"ToolResult": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"result": map[string]any{"description": "Tool output"},
+				"error":  map[string]any{"type": "string", "description": "Error message if the call failed"},
+				"kind": map[string]any{
+					"type":        "string",
+					"enum":        []string{"invalid_input", "not_found", "upstream", "rate_limited", "internal"},
+					"description": "Why the call failed",
+				},
+				"retryable": map[string]any{"type": "boolean", "description": "Whether the failed call may succeed if retried later"},
+			},
+		},
+	}
+
+	for _, t := range tools {
+		schemaName := t.Name + "_input"
+		schema := t.InputSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		schemas[schemaName] = schema
+
+		methods := t.Methods
+		if len(methods) == 0 {
+			methods = []string{"POST"}
+		}
+		operations := make(map[string]any, len(methods))
+		for _, method := range methods {
+			method = strings.ToLower(method)
+			op := map[string]any{
+				"operationId": t.Name,
+				"summary":     t.Description,
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Tool result",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/ToolResult"},
+							},
+						},
+					},
+				},
+			}
+			if len(methods) > 1 {
+				op["operationId"] = t.Name + "_" + method
+			}
+			if method == "get" || method == "head" {
+				op["parameters"] = queryParameters(schema)
+			} else {
+				op["requestBody"] = map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{
+							"schema": map[string]any{"$ref": "#/components/schemas/" + schemaName},
+						},
+					},
+				}
+			}
+			operations[method] = op
+		}
+		paths["/tools/"+t.Name] = operations
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "Blaze Tools",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+}
+
+// queryParameters describes the top-level properties of a schema as query
+// parameters, the way ExecHandler reads GET input
+func queryParameters(schema any) []any {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return []any{}
+	}
+	var s struct {
+		Properties map[string]struct {
+			Description string `json:"description"`
+		} `json:"properties"`
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return []any{}
+	}
+
+	names := slices.Sorted(maps.Keys(s.Properties))
+	params := make([]any, 0, len(names))
+	for _, name := range names {
+		param := map[string]any{
+			"name":     name,
+			"in":       "query",
+			"required": slices.Contains(s.Required, name),
+			"schema":   map[string]any{"type": "string"},
+		}
+		if desc := s.Properties[name].Description; desc != "" {
+			param["description"] = desc
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// OpenAPIHandler creates a handler that serves OpenAPISpec as JSON
+func OpenAPIHandler(tools ...Tool) blaze.HandlerFunc {
+	spec := OpenAPISpec(tools...)
+	return func(ctx *blaze.Context) error {
+		return ctx.JSON(200, spec)
+	}
+}
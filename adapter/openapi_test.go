@@ -0,0 +1,214 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+)
+
+// TestOpenAPISpec tests that each tool gets a path referencing its input schema
+func TestOpenAPISpec(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", objectSchema, noopHandler)
+	timeTool := NewTool("datetime", "Work with dates", nil, noopHandler)
+
+	e := blaze.New()
+	e.GET("/openapi.json", OpenAPIHandler(echoTool, timeTool))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]struct {
+			Post struct {
+				Summary     string `json:"summary"`
+				RequestBody struct {
+					Content map[string]struct {
+						Schema map[string]string `json:"schema"`
+					} `json:"content"`
+				} `json:"requestBody"`
+			} `json:"post"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %s", spec.OpenAPI)
+	}
+	if len(spec.Paths) != 2 {
+		t.Fatalf("Expected 2 paths, got %d", len(spec.Paths))
+	}
+
+	echo, ok := spec.Paths["/tools/echo"]
+	if !ok {
+		t.Fatal("Expected path /tools/echo")
+	}
+	if echo.Post.Summary != "Echo back the input" {
+		t.Errorf("Expected description as summary, got %q", echo.Post.Summary)
+	}
+	ref := echo.Post.RequestBody.Content["application/json"].Schema["$ref"]
+	if ref != "#/components/schemas/echo_input" {
+		t.Errorf("Expected schema reference, got %q", ref)
+	}
+	if _, ok := spec.Components.Schemas["echo_input"]; !ok {
+		t.Error("Expected referenced schema in components")
+	}
+	if _, ok := spec.Components.Schemas["datetime_input"]; !ok {
+		t.Error("Expected placeholder schema for tool without InputSchema")
+	}
+}
+
+// TestExecHandler tests running a tool directly by name
+func TestExecHandler(t *testing.T) {
+	echoTool := NewTool("echo", "Echo back the input", objectSchema,
+		func(input json.RawMessage) (any, error) {
+			var data map[string]any
+			json.Unmarshal(input, &data)
+			return map[string]any{"echoed": data["message"]}, nil
+		},
+	)
+
+	e := blaze.New()
+	e.POST("/tools/:name", ExecHandler(echoTool))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/echo", strings.NewReader(`{"message":"hi"}`)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"result":{"echoed":"hi"}`) {
+		t.Errorf("Unexpected response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/echo", strings.NewReader(`not json`)))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"error":{"code":400,"message":"request body must be valid JSON"}`) {
+		t.Errorf("Expected 400 in the error envelope for invalid JSON, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestExecHandler_UnknownToolStatus tests that unknown tools get the
+// configured status on the exec path, while chat adapters keep 200
+func TestExecHandler_UnknownToolStatus(t *testing.T) {
+	lookup := NewTool("lookup", "Fails with not_found", objectSchema,
+		func(input json.RawMessage) (any, error) {
+			return nil, NewToolError(KindNotFound, "no such record")
+		},
+	)
+
+	e := blaze.New()
+	e.POST("/plain/:name", ExecHandler(lookup))
+	e.POST("/strict/:name", ExecHandlerWithOptions([]Tool{lookup}, WithUnknownToolStatus(http.StatusNotFound)))
+	e.POST("/chat", AnthropicAdapterWithOptions([]Tool{lookup}, WithUnknownToolStatus(http.StatusNotFound)))
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{"/plain/missing", `{}`, http.StatusOK, "Tool 'missing' not found"},
+		{"/strict/missing", `{}`, http.StatusNotFound, "Tool 'missing' not found"},
+		// A tool that runs and reports not_found is not an unknown tool
+		{"/strict/lookup", `{}`, http.StatusOK, "no such record"},
+		{"/chat", `{"model":"claude-3-5-sonnet","messages":[{"role":"user","content":[{"type":"tool_use","id":"t1","name":"missing","input":{}}]}]}`, http.StatusOK, "not found"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("%s: expected body to contain %q, got %s", tt.path, tt.want, rec.Body.String())
+		}
+	}
+}
+
+// TestExecHandler_Methods tests that tools listing Methods are only run for
+// those, with GET input taken from the query string
+func TestExecHandler_Methods(t *testing.T) {
+	lookup := NewTool("lookup", "Read a key", map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"key": map[string]any{"type": "string", "description": "Key to read"}},
+		"required":   []string{"key"},
+	}, func(input json.RawMessage) (any, error) {
+		var data map[string]any
+		json.Unmarshal(input, &data)
+		return data, nil
+	})
+	lookup.Methods = []string{"GET"}
+
+	e := blaze.New()
+	e.GET("/tools/:name", ExecHandler(lookup))
+	e.POST("/tools/:name", ExecHandler(lookup))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/lookup?key=a&tag=x&tag=y", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"result":{"key":"a","tag":["x","y"]}`) {
+		t.Errorf("GET: unexpected response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/lookup", strings.NewReader(`{"key":"a"}`)))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: expected 405, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET" {
+		t.Errorf("POST: expected Allow: GET, got %q", allow)
+	}
+
+	spec := OpenAPISpec(lookup)
+	ops := spec["paths"].(map[string]any)["/tools/lookup"].(map[string]any)
+	get, ok := ops["get"].(map[string]any)
+	if !ok || ops["post"] != nil {
+		t.Fatalf("Expected only a get operation, got %v", ops)
+	}
+	params := get["parameters"].([]any)
+	if len(params) != 1 || params[0].(map[string]any)["name"] != "key" || params[0].(map[string]any)["required"] != true {
+		t.Errorf("Expected a required key query parameter, got %v", params)
+	}
+}
+
+// TestExecHandler_SideEffectPostOnly tests that SideEffect tools don't run
+// for GET unless they opt in
+func TestExecHandler_SideEffectPostOnly(t *testing.T) {
+	wipe := NewTool("clear", "Clear the store", nil, func(input json.RawMessage) (any, error) {
+		return map[string]any{"cleared": true}, nil
+	})
+	wipe.SideEffect = true
+	optIn := wipe
+	optIn.Name = "touch"
+	optIn.Methods = []string{"GET", "POST"}
+
+	e := blaze.New()
+	e.GET("/tools/:name", ExecHandler(wipe, optIn))
+	e.POST("/tools/:name", ExecHandler(wipe, optIn))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/clear?action=clear", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "POST" {
+		t.Errorf("GET: expected 405 with Allow: POST, got %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+	if !strings.Contains(rec.Body.String(), `"message":"tool clear does not accept GET"`) {
+		t.Errorf("GET: expected the error envelope, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/clear", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("POST: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/touch", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET with opt-in: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,123 @@
+package adapter
+
+import (
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Adapter Options
+// ============================================================================
+
+// Option configures optional adapter behavior
+type Option func(*config)
+
+// FallbackFunc handles a chat request that contains no tool calls, e.g. by
+// forwarding it to a real model backend. req is the parsed provider request
+// (AnthropicChatRequest or OpenAIChatRequest), including any system prompt.
+type FallbackFunc func(ctx *blaze.Context, req any) error
+
+// config holds the settings shared by every adapter
+type config struct {
+	callLimits    map[string]int          // max calls per tool per request
+	rateLimits    map[string]*tokenBucket // per-tool calls per minute, across requests
+	dedupe        bool                    // reuse results of identical calls within a request
+	fallback      FallbackFunc            // handles requests without tool calls
+	sessions      *sessions               // conversation history, nil if disabled
+	maxDepth      int                     // max tool rounds per chain, 0 = unlimited
+	inputHooks    []InputHook             // run on tool inputs before the handler
+	registry      *ToolRegistry           // additional tools and enabled flags
+	maxResult     int                     // max bytes of a serialized tool result, 0 = unlimited
+	requestLog    *requestLogger          // logs tool calls, nil if disabled
+	heartbeat     time.Duration           // idle time before a stream heartbeat, 0 = disabled
+	strict        bool                    // reject requests missing fields the provider requires
+	unknownStatus int                     // ExecHandler status for unknown tools, 0 = 200
+}
+
+// newConfig applies opts on top of the defaults
+func newConfig(opts []Option) *config {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithToolLimits caps how many times each named tool may be called within a
+// single request. Calls beyond the cap return an error result instead of
+// executing.
+func WithToolLimits(limits map[string]int) Option {
+	return func(c *config) {
+		c.callLimits = limits
+	}
+}
+
+// WithToolRateLimits limits each named tool to the given number of calls per
+// minute across all requests, using a token bucket that allows short bursts
+// up to the per-minute amount.
+func WithToolRateLimits(perMinute map[string]int) Option {
+	return func(c *config) {
+		c.rateLimits = make(map[string]*tokenBucket, len(perMinute))
+		for name, n := range perMinute {
+			c.rateLimits[name] = newTokenBucket(n)
+		}
+	}
+}
+
+// WithDedupe makes the adapter execute identical tool calls (same name and
+// equivalent input) only once per request, reusing the first result for each
+// duplicate call ID
+func WithDedupe() Option {
+	return func(c *config) {
+		c.dedupe = true
+	}
+}
+
+// WithFallback forwards requests that contain no tool calls to fn instead of
+// returning the built-in tool info response
+func WithFallback(fn FallbackFunc) Option {
+	return func(c *config) {
+		c.fallback = fn
+	}
+}
+
+// WithRegistry resolves tool calls through registry as well as the tools
+// passed to the adapter, so tools registered later become callable. Calls to
+// tools disabled in the registry return a "tool disabled" error.
+func WithRegistry(registry *ToolRegistry) Option {
+	return func(c *config) {
+		c.registry = registry
+	}
+}
+
+// WithMaxToolResultBytes truncates tool results whose JSON encoding exceeds
+// maxBytes using TruncateResult, so large outputs don't overflow the model's
+// context window
+func WithMaxToolResultBytes(maxBytes int) Option {
+	return func(c *config) {
+		c.maxResult = maxBytes
+	}
+}
+
+// WithUnknownToolStatus sets the HTTP status ExecHandler responds with when
+// the requested tool is unknown or disabled, typically 404 or 405, so
+// clients other than models can tell a missing tool from a failed one. The
+// body is the usual error object.
+//
+// Chat adapters ignore it and keep answering 200: the model has to see the
+// error in the tool result. Batches also keep 200, with an error per call.
+func WithUnknownToolStatus(status int) Option {
+	return func(c *config) {
+		c.unknownStatus = status
+	}
+}
+
+// WithStrictValidation rejects requests that the real provider API would
+// reject for missing required fields, such as Anthropic's max_tokens, with
+// the provider's 400 error. Off by default, so lenient clients keep working.
+func WithStrictValidation() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
@@ -0,0 +1,36 @@
+//go:build otel
+
+package adapter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of tool calls
+const tracerName = "github.com/dvictor357/blaze/adapter"
+
+// startToolSpan starts a span for a call of the named tool, as a child of
+// the span in ctx, e.g. the request span of blaze.OTelMiddleware. end
+// records the call's error, if any, with its kind.
+//
+// This file is only compiled with the "otel" build tag; without it tool
+// calls aren't traced.
+func startToolSpan(ctx context.Context, name string) (context.Context, func(err error)) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "tool "+name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attribute.String("tool.name", name)),
+	)
+	return ctx, func(err error) {
+		if err != nil {
+			span.SetAttributes(attribute.String("tool.error.kind", string(ErrorKindOf(err))))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !otel
+
+package adapter
+
+import "context"
+
+// startToolSpan is a no-op without the "otel" build tag; see otel.go
+func startToolSpan(ctx context.Context, name string) (context.Context, func(err error)) {
+	return ctx, func(error) {}
+}
@@ -0,0 +1,75 @@
+//go:build otel
+
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestToolSpans tests that each tool call gets a child span of the request
+// span, carrying the tool name and, on failure, the error kind and status
+func TestToolSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ok := NewTool("ok", "Succeeds", nil, func(input json.RawMessage) (any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+	fail := NewTool("fail", "Fails", nil, func(input json.RawMessage) (any, error) {
+		return nil, NewToolError(KindUpstream, "service down")
+	})
+
+	e := blaze.New()
+	e.Use(blaze.OTelMiddleware("blaze-test"))
+	e.POST("/chat", OpenAIAdapter(ok, fail))
+	body, _ := json.Marshal(OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{Role: "assistant", ToolCalls: []OpenAIToolCall{
+			{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "ok", Arguments: `{}`}},
+			{ID: "call_2", Type: "function", Function: OpenAIFunctionCall{Name: "fail", Arguments: `{}`}},
+		}}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	request, okSpan, failSpan := spans["POST /chat"], spans["tool ok"], spans["tool fail"]
+	if request == nil || okSpan == nil || failSpan == nil {
+		t.Fatalf("Expected a request span and two tool spans, got %v", spans)
+	}
+	for _, s := range []sdktrace.ReadOnlySpan{okSpan, failSpan} {
+		if s.Parent().SpanID() != request.SpanContext().SpanID() || s.SpanContext().TraceID() != request.SpanContext().TraceID() {
+			t.Errorf("Expected %s to be a child of the request span", s.Name())
+		}
+	}
+
+	attrs := func(s sdktrace.ReadOnlySpan) map[string]string {
+		m := map[string]string{}
+		for _, kv := range s.Attributes() {
+			m[string(kv.Key)] = kv.Value.Emit()
+		}
+		return m
+	}
+	if a := attrs(okSpan); a["tool.name"] != "ok" || a["tool.error.kind"] != "" || okSpan.Status().Code == codes.Error {
+		t.Errorf("Unexpected ok span: %v %v", a, okSpan.Status())
+	}
+	if a := attrs(failSpan); a["tool.name"] != "fail" || a["tool.error.kind"] != "upstream" || failSpan.Status().Code != codes.Error {
+		t.Errorf("Unexpected fail span: %v %v", a, failSpan.Status())
+	}
+}
@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// ============================================================================
+// Progress Events
+// ============================================================================
+
+// ProgressFunc reports a human-readable progress message such as "searching..."
+type ProgressFunc func(msg string)
+
+// NewProgressTool creates a Tool whose handler can report progress while it
+// runs. Streaming adapters relay each message as an intermediate event before
+// the tool's result; elsewhere the messages are discarded.
+func NewProgressTool(name, desc string, schema any, handler func(json.RawMessage, ProgressFunc) (any, error)) Tool {
+	t := NewTool(name, desc, schema, func(input json.RawMessage) (any, error) {
+		return handler(input, func(string) {})
+	})
+	t.ProgressHandler = handler
+	return t
+}
+
+// NewContextTool creates a Tool whose handler receives the context of the
+// request that called it, so it can stop blocking work once the client goes
+// away. Called without a request, e.g. through Handler, the context is
+// context.Background().
+func NewContextTool(name, desc string, schema any, handler func(context.Context, json.RawMessage) (any, error)) Tool {
+	t := NewTool(name, desc, schema, func(input json.RawMessage) (any, error) {
+		return handler(context.Background(), input)
+	})
+	t.ContextHandler = handler
+	return t
+}
+
+// progressKey is the context key under which Invoke passes progress to
+// context-aware tools
+type progressKey struct{}
+
+// ContextProgress returns the progress function passed along with ctx to a
+// context-aware tool, or one that discards messages
+func ContextProgress(ctx context.Context) ProgressFunc {
+	if progress, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		return progress
+	}
+	return func(string) {}
+}
+
+// Invoke calls the tool's handler the way the adapters do: context-aware
+// tools get ctx, carrying progress for ContextProgress, and progress-aware
+// ones get progress. A nil progress discards messages. Tools that call
+// other tools, such as pipelines, use it to pass both along.
+func (t Tool) Invoke(ctx context.Context, input json.RawMessage, progress ProgressFunc) (any, error) {
+	if progress == nil {
+		progress = func(string) {}
+	}
+	if t.ContextHandler != nil {
+		return t.ContextHandler(context.WithValue(ctx, progressKey{}, progress), input)
+	}
+	if t.ProgressHandler != nil {
+		return t.ProgressHandler(input, progress)
+	}
+	return t.Handler(input)
+}
+
+// guardProgress wraps send so that messages emitted after stop is called,
+// e.g. from a goroutine a tool left running, are dropped instead of being
+// written to a finished stream
+func guardProgress(send ProgressFunc) (progress ProgressFunc, stop func()) {
+	var mu sync.Mutex
+	stopped := false
+	progress = func(msg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !stopped {
+			send(msg)
+		}
+	}
+	stop = func() {
+		mu.Lock()
+		stopped = true
+		mu.Unlock()
+	}
+	return progress, stop
+}
+
+// staticResults returns a producer for results that are already known
+func staticResults[T any](results []T) func(ProgressFunc) []T {
+	return func(ProgressFunc) []T { return results }
+}
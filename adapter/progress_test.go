@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+)
+
+// progressTool emits two progress messages before returning its result
+var progressTool = NewProgressTool("search", "Search", objectSchema,
+	func(input json.RawMessage, progress ProgressFunc) (any, error) {
+		progress("searching...")
+		progress("ranking results...")
+		return map[string]any{"hits": 3}, nil
+	},
+)
+
+// assertOrdered fails unless each of want appears in body after the previous one
+func assertOrdered(t *testing.T, body string, want ...string) {
+	t.Helper()
+	pos := 0
+	for _, w := range want {
+		i := strings.Index(body[pos:], w)
+		if i < 0 {
+			t.Fatalf("Expected %q after offset %d in stream:\n%s", w, pos, body)
+		}
+		pos += i + len(w)
+	}
+}
+
+// TestProgress_AnthropicStream tests that progress deltas precede the tool result
+func TestProgress_AnthropicStream(t *testing.T) {
+	rec := postJSON(t, AnthropicAdapter(progressTool), AnthropicChatRequest{
+		Model:  "claude-3-5-sonnet",
+		Stream: true,
+		Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "search", Input: map[string]any{}},
+		}}},
+	})
+
+	assertOrdered(t, rec.Body.String(), `"text":"searching..."`, `"text":"ranking results..."`, `\"hits\":3`, `"message_stop"`)
+}
+
+// TestProgress_OpenAIStream tests that progress chunks precede the tool result
+func TestProgress_OpenAIStream(t *testing.T) {
+	rec := postJSON(t, OpenAIAdapter(progressTool), OpenAIChatRequest{
+		Model:  "gpt-4",
+		Stream: true,
+		Messages: []OpenAIMessage{{
+			Role:      "assistant",
+			ToolCalls: []OpenAIToolCall{{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "search", Arguments: "{}"}}},
+		}},
+	})
+
+	assertOrdered(t, rec.Body.String(), "searching...", "ranking results...", `\"hits\":3`, `"finish_reason":"stop"`)
+}
+
+// TestProgress_NonStreaming tests that progress is discarded outside streams
+func TestProgress_NonStreaming(t *testing.T) {
+	rec := postJSON(t, AnthropicAdapter(progressTool), AnthropicChatRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "search", Input: map[string]any{}},
+		}}},
+	})
+
+	var resp AnthropicChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Content) != 1 || resp.Content[0].Content != `{"hits":3}` {
+		t.Errorf("Expected only the tool result, got %s", rec.Body.String())
+	}
+}
+
+// TestContextTool tests that context-aware tools get the request's context
+func TestContextTool(t *testing.T) {
+	type key struct{}
+	tool := NewContextTool("whoami", "Reads the request context", objectSchema,
+		func(ctx context.Context, input json.RawMessage) (any, error) {
+			return ctx.Value(key{}), nil
+		},
+	)
+
+	e := blaze.New()
+	e.POST("/tools/:name", ExecHandler(tool))
+	req := httptest.NewRequest(http.MethodPost, "/tools/whoami", strings.NewReader(`{}`))
+	req = req.WithContext(context.WithValue(req.Context(), key{}, "ada"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"result":"ada"`) {
+		t.Errorf("Expected the request context value, got %s", rec.Body.String())
+	}
+	if out, err := tool.Handler(json.RawMessage(`{}`)); err != nil || out != nil {
+		t.Errorf("Expected Handler to use a background context, got %v, %v", out, err)
+	}
+}
+
+// TestContextProgress tests that Invoke passes progress to context-aware tools
+func TestContextProgress(t *testing.T) {
+	tool := NewContextTool("work", "Work", objectSchema,
+		func(ctx context.Context, input json.RawMessage) (any, error) {
+			ContextProgress(ctx)("working...")
+			return "done", nil
+		},
+	)
+
+	var messages []string
+	if _, err := tool.Invoke(context.Background(), json.RawMessage(`{}`), func(msg string) {
+		messages = append(messages, msg)
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(messages) != 1 || messages[0] != "working..." {
+		t.Errorf("Expected the progress message, got %q", messages)
+	}
+
+	// Without Invoke, messages are discarded
+	if _, err := tool.Handler(json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Adapter Factory
+// ============================================================================
+
+// RequestInfo holds the request fields every provider adapter understands
+type RequestInfo struct {
+	Model         string
+	Stream        bool
+	SessionID     string
+	ToolCallDepth int
+	Timezone      string
+	DryRun        bool
+}
+
+// ProviderSpec describes a chat API for NewAdapter. R is the request type,
+// M a message of its conversation, C a tool call and T a tool result.
+//
+// The adapter decodes the request, applies the timezone and dry-run flags,
+// prepends the session history, enforces the tool call depth, executes the
+// calls with the configured Options and writes the results. The spec only
+// translates between the provider's format and these steps.
+type ProviderSpec[R, M, C, T any] struct {
+	// Normalize, if set, adjusts a decoded request before it is validated
+	Normalize func(req *R)
+
+	// Validate returns why req cannot be served, or "" if it can
+	Validate func(req R) string
+
+	// Strict, if set, is additional validation applied with
+	// WithStrictValidation, e.g. for fields the provider requires
+	Strict func(req R) string
+
+	// BadRequest writes a 400 response in the provider's error format
+	BadRequest func(ctx *blaze.Context, message string) error
+
+	// Info returns the provider-neutral fields of req
+	Info func(req R) RequestInfo
+
+	// Messages returns the conversation of req, so the stored history of a
+	// session can be prepended to it
+	Messages func(req *R) *[]M
+
+	// ToolCalls returns the calls to execute. With none, the request goes to
+	// the fallback or NoToolCalls.
+	ToolCalls func(req R) []C
+
+	// Call returns the tool name and JSON input of a call
+	Call func(call C) (name string, input json.RawMessage)
+
+	// Result converts the outcome of a call
+	Result func(call C, outcome ToolOutcome) T
+
+	// Text wraps text, such as the depth limit refusal, as a result
+	Text func(text string) T
+
+	// Conversation returns the messages to store for the session once the
+	// calls of req produced results
+	Conversation func(req R, results []T) []M
+
+	// History, if set, returns the messages to store for the session when
+	// req has no tool calls, e.g. to add a turn kept outside the
+	// conversation. By default the conversation is stored as is.
+	History func(req R) []M
+
+	// NoToolCalls answers a request without tool calls
+	NoToolCalls func(ctx *blaze.Context, req R, tools []Tool) error
+
+	// Send writes results as a complete response
+	Send func(ctx *blaze.Context, req R, results []T) error
+
+	// Stream writes a streaming response, calling run to execute the tools
+	// while it streams. Progress passed to run is relayed to the client.
+	Stream func(ctx *blaze.Context, req R, run func(ProgressFunc) []T, heartbeat time.Duration) error
+}
+
+// NewAdapter creates a Blaze handler for the chat API described by spec,
+// executing calls to tools. opts apply as for the built-in adapters.
+func NewAdapter[R, M, C, T any](spec ProviderSpec[R, M, C, T], tools []Tool, opts ...Option) blaze.HandlerFunc {
+	cfg := newConfig(opts)
+	toolMap := mustBuildToolMap(tools)
+
+	return func(ctx *blaze.Context) error {
+		var req R
+		if err := ctx.BindJSON(&req); err != nil {
+			return spec.BadRequest(ctx, fmt.Sprintf("Invalid request: %v", err))
+		}
+		if spec.Normalize != nil {
+			spec.Normalize(&req)
+		}
+		if msg := spec.Validate(req); msg != "" {
+			return spec.BadRequest(ctx, msg)
+		}
+		if cfg.strict && spec.Strict != nil {
+			if msg := spec.Strict(req); msg != "" {
+				return spec.BadRequest(ctx, msg)
+			}
+		}
+		info := spec.Info(req)
+
+		// Timezone and dry-run flags in the body take precedence over the headers
+		if info.Timezone != "" {
+			ctx.Request.Header.Set(TimezoneHeader, info.Timezone)
+		}
+		if info.DryRun {
+			ctx.Request.Header.Set(DryRunHeader, "true")
+		}
+
+		// Prepend the stored history of this session
+		id := ""
+		if cfg.sessions != nil {
+			id = sessionID(ctx, info.SessionID)
+		}
+		messages := spec.Messages(&req)
+		unlock := func() {}
+		if id != "" {
+			unlock = cfg.sessions.lock(id)
+			defer unlock()
+			var history []M
+			if cfg.sessions.load(id, &history) {
+				*messages = append(history, *messages...)
+			}
+		}
+
+		// Without tool calls, forward to the fallback or describe the tools
+		calls := spec.ToolCalls(req)
+		if len(calls) == 0 {
+			if id != "" {
+				if spec.History != nil {
+					cfg.sessions.save(id, spec.History(req))
+				} else {
+					cfg.sessions.save(id, *messages)
+				}
+			}
+			unlock()
+			if cfg.fallback != nil {
+				return cfg.fallback(ctx, req)
+			}
+			return spec.NoToolCalls(ctx, req, tools)
+		}
+
+		// Refuse to go deeper once the chain reached the depth limit
+		depth := toolCallDepth(ctx, info.ToolCallDepth)
+		if cfg.depthExceeded(depth) {
+			ctx.SetHeader(ToolDepthHeader, strconv.Itoa(depth))
+			refusal := []T{spec.Text(depthLimitMessage(depth, cfg.maxDepth))}
+			if info.Stream {
+				return spec.Stream(ctx, req, staticResults(refusal), cfg.heartbeat)
+			}
+			return spec.Send(ctx, req, refusal)
+		}
+		ctx.SetHeader(ToolDepthHeader, strconv.Itoa(depth+1))
+
+		// Execute the calls in order, relaying progress when streaming
+		runTools := func(progress ProgressFunc) []T {
+			exec := newExecutor(ctx, toolMap, cfg)
+			exec.progress = progress
+			defer exec.logRequest()
+			if info.Stream {
+				toolCtx, stop := drainContext(ctx.Request.Context())
+				defer stop()
+				exec.toolCtx = toolCtx
+			}
+			results := make([]T, 0, len(calls))
+			for _, call := range calls {
+				name, input := spec.Call(call)
+				results = append(results, spec.Result(call, exec.execute(name, input)))
+			}
+			if id != "" {
+				cfg.sessions.save(id, spec.Conversation(req, results))
+			}
+			unlock()
+			return results
+		}
+
+		if info.Stream {
+			return spec.Stream(ctx, req, runTools, cfg.heartbeat)
+		}
+		return spec.Send(ctx, req, runTools(nil))
+	}
+}
@@ -0,0 +1,96 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// toyRequest is the request of a minimal provider used to test NewAdapter
+type toyRequest struct {
+	Messages []toyCall `json:"messages"`
+	Depth    int       `json:"depth"`
+}
+
+type toyCall struct {
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+var toySpec = ProviderSpec[toyRequest, toyCall, toyCall, string]{
+	Validate: func(req toyRequest) string {
+		if req.Messages == nil {
+			return "messages required"
+		}
+		return ""
+	},
+	BadRequest: func(ctx *blaze.Context, message string) error {
+		return ctx.JSON(400, map[string]string{"problem": message})
+	},
+	Info: func(req toyRequest) RequestInfo {
+		return RequestInfo{ToolCallDepth: req.Depth}
+	},
+	Messages: func(req *toyRequest) *[]toyCall { return &req.Messages },
+	ToolCalls: func(req toyRequest) []toyCall {
+		var calls []toyCall
+		for _, c := range req.Messages {
+			if c.Name != "" {
+				calls = append(calls, c)
+			}
+		}
+		return calls
+	},
+	Call:         func(c toyCall) (string, json.RawMessage) { return c.Name, c.Input },
+	Result:       func(c toyCall, outcome ToolOutcome) string { return c.Name + "=" + outcome.Content },
+	Text:         func(text string) string { return "text=" + text },
+	Conversation: func(req toyRequest, results []string) []toyCall { return req.Messages },
+	NoToolCalls: func(ctx *blaze.Context, req toyRequest, tools []Tool) error {
+		return ctx.JSON(200, map[string]int{"tools": len(tools)})
+	},
+	Send: func(ctx *blaze.Context, req toyRequest, results []string) error {
+		return ctx.JSON(200, map[string]any{"results": results})
+	},
+	Stream: func(ctx *blaze.Context, req toyRequest, run func(ProgressFunc) []string, heartbeat time.Duration) error {
+		return ctx.JSON(200, map[string]any{"streamed": run(nil)})
+	},
+}
+
+// TestNewAdapter tests the shared request flow with a minimal provider
+func TestNewAdapter(t *testing.T) {
+	echo := NewTool("echo", "Echo", nil, func(input json.RawMessage) (any, error) {
+		var v any
+		json.Unmarshal(input, &v)
+		return v, nil
+	})
+	handler := NewAdapter(toySpec, []Tool{echo}, WithMaxToolDepth(2))
+
+	rec := postJSON(t, handler, map[string]any{"messages": []map[string]any{
+		{"name": "echo", "input": map[string]any{"a": 1}},
+		{"name": "missing", "input": map[string]any{}},
+	}})
+	if body := rec.Body.String(); rec.Code != 200 || !strings.Contains(body, `echo={\"a\":1}`) || !strings.Contains(body, "not_found") {
+		t.Errorf("Expected both calls to run, got %d: %s", rec.Code, body)
+	}
+	if depth := rec.Header().Get(ToolDepthHeader); depth != "1" {
+		t.Errorf("Expected next depth 1, got %q", depth)
+	}
+
+	rec = postJSON(t, handler, map[string]any{"messages": []map[string]any{{}}})
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"tools":1}` {
+		t.Errorf("Expected the no-call response, got %s", body)
+	}
+
+	rec = postJSON(t, handler, map[string]any{})
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "messages required") {
+		t.Errorf("Expected the spec's bad request response, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = postJSON(t, handler, map[string]any{"depth": 2, "messages": []map[string]any{{"name": "echo"}}})
+	if body := rec.Body.String(); !strings.Contains(body, "text=Tool call depth limit reached") {
+		t.Errorf("Expected the depth refusal as text, got %s", body)
+	}
+}
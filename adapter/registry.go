@@ -0,0 +1,147 @@
+package adapter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ============================================================================
+// Tool Registry
+// ============================================================================
+
+// ToolRegistry is a thread-safe set of tools, kept in registration order,
+// that can be changed at runtime. Tools can be disabled without removing
+// them; adapters using WithRegistry refuse calls to disabled tools.
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools []*registeredTool
+	index map[string]int // position in tools by name
+}
+
+// registeredTool is a tool plus its runtime enabled flag
+type registeredTool struct {
+	Tool
+	enabled atomic.Bool
+}
+
+// NewToolRegistry creates a registry holding tools, panicking on duplicate
+// names like the adapters do
+func NewToolRegistry(tools ...Tool) *ToolRegistry {
+	r := &ToolRegistry{index: make(map[string]int, len(tools))}
+	for _, t := range tools {
+		if err := r.Register(t); err != nil {
+			panic("adapter: " + err.Error())
+		}
+	}
+	return r
+}
+
+// Register adds a tool, returning an error if the name is already taken.
+// Use Tool.Validate for stricter checks.
+func (r *ToolRegistry) Register(tool Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.index[tool.Name]; exists {
+		return fmt.Errorf("duplicate tool name %q", tool.Name)
+	}
+	rt := &registeredTool{Tool: tool}
+	rt.enabled.Store(true)
+	r.index[tool.Name] = len(r.tools)
+	r.tools = append(r.tools, rt)
+	return nil
+}
+
+// Unregister removes a tool, reporting whether it was registered
+func (r *ToolRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, exists := r.index[name]
+	if !exists {
+		return false
+	}
+	r.tools = append(r.tools[:i], r.tools[i+1:]...)
+	delete(r.index, name)
+	for j := i; j < len(r.tools); j++ {
+		r.index[r.tools[j].Name] = j
+	}
+	return true
+}
+
+// Get returns the named tool, whether enabled or not
+func (r *ToolRegistry) Get(name string) (Tool, bool) {
+	rt := r.lookup(name)
+	if rt == nil {
+		return Tool{}, false
+	}
+	return rt.Tool, true
+}
+
+// Enable re-enables a disabled tool, reporting whether it is registered
+func (r *ToolRegistry) Enable(name string) bool {
+	return r.setEnabled(name, true)
+}
+
+// Disable turns a tool off without unregistering it, reporting whether it
+// is registered
+func (r *ToolRegistry) Disable(name string) bool {
+	return r.setEnabled(name, false)
+}
+
+// Enabled reports whether the named tool is registered and enabled
+func (r *ToolRegistry) Enabled(name string) bool {
+	rt := r.lookup(name)
+	return rt != nil && rt.enabled.Load()
+}
+
+func (r *ToolRegistry) setEnabled(name string, enabled bool) bool {
+	rt := r.lookup(name)
+	if rt == nil {
+		return false
+	}
+	rt.enabled.Store(enabled)
+	return true
+}
+
+func (r *ToolRegistry) lookup(name string) *registeredTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, exists := r.index[name]
+	if !exists {
+		return nil
+	}
+	return r.tools[i]
+}
+
+// Tools returns a snapshot of all registered tools in registration order
+func (r *ToolRegistry) Tools() []Tool {
+	return r.snapshot(false)
+}
+
+// EnabledTools returns a snapshot of the enabled tools in registration order
+func (r *ToolRegistry) EnabledTools() []Tool {
+	return r.snapshot(true)
+}
+
+func (r *ToolRegistry) snapshot(enabledOnly bool) []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.tools))
+	for _, rt := range r.tools {
+		if !enabledOnly || rt.enabled.Load() {
+			tools = append(tools, rt.Tool)
+		}
+	}
+	return tools
+}
+
+// Len returns the number of registered tools
+func (r *ToolRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.tools)
+}
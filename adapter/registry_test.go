@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestToolRegistry tests registration order, lookups and removal
+func TestToolRegistry(t *testing.T) {
+	r := NewToolRegistry(NewTool("a", "A", objectSchema, noopHandler), NewTool("b", "B", objectSchema, noopHandler))
+
+	if err := r.Register(NewTool("a", "again", objectSchema, noopHandler)); err == nil {
+		t.Error("Expected duplicate registration to fail")
+	}
+	if err := r.Register(NewTool("c", "C", objectSchema, noopHandler)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if !r.Unregister("b") || r.Unregister("b") {
+		t.Error("Expected Unregister to remove b exactly once")
+	}
+
+	names := []string{}
+	for _, tool := range r.Tools() {
+		names = append(names, tool.Name)
+	}
+	if len(names) != 2 || names[0] != "a" || names[1] != "c" {
+		t.Errorf("Expected [a c], got %v", names)
+	}
+	if tool, ok := r.Get("c"); !ok || tool.Description != "C" {
+		t.Errorf("Expected to get c after removal of b, got %+v %v", tool, ok)
+	}
+}
+
+// TestToolRegistry_Disable tests that disabled tools are refused while others still work
+func TestToolRegistry_Disable(t *testing.T) {
+	ok := NewTool("datetime", "Time", objectSchema, func(input json.RawMessage) (any, error) {
+		return "now", nil
+	})
+	web := NewTool("web_fetch", "Fetch", objectSchema, func(input json.RawMessage) (any, error) {
+		return "page", nil
+	})
+	registry := NewToolRegistry(ok, web)
+	h := AnthropicAdapterWithOptions(nil, WithRegistry(registry))
+
+	call := func() []AnthropicContentBlock {
+		rec := postJSON(t, h, AnthropicChatRequest{
+			Model: "claude-3-5-sonnet",
+			Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+				{Type: "tool_use", ID: "toolu_1", Name: "datetime", Input: map[string]any{}},
+				{Type: "tool_use", ID: "toolu_2", Name: "web_fetch", Input: map[string]any{}},
+			}}},
+		})
+		var resp AnthropicChatResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if len(resp.Content) != 2 {
+			t.Fatalf("Expected 2 results, got %s", rec.Body.String())
+		}
+		return resp.Content
+	}
+
+	if results := call(); results[0].IsError || results[1].IsError {
+		t.Fatalf("Expected both tools to work while enabled, got %+v", results)
+	}
+
+	if !registry.Disable("web_fetch") || registry.Enabled("web_fetch") {
+		t.Fatal("Expected web_fetch to be disabled")
+	}
+	results := call()
+	if results[0].IsError {
+		t.Errorf("Expected datetime to keep working, got %s", results[0].Content)
+	}
+	if !results[1].IsError || !strings.Contains(results[1].Content, "Tool 'web_fetch' is disabled") {
+		t.Errorf("Expected disabled error for web_fetch, got %s", results[1].Content)
+	}
+	if tools := registry.EnabledTools(); len(tools) != 1 || tools[0].Name != "datetime" {
+		t.Errorf("Expected only datetime to be enabled, got %v", tools)
+	}
+
+	registry.Enable("web_fetch")
+	if results := call(); results[1].IsError {
+		t.Errorf("Expected web_fetch to work after re-enabling, got %s", results[1].Content)
+	}
+}
@@ -0,0 +1,105 @@
+package adapter
+
+import (
+	"log/slog"
+	"time"
+)
+
+// ============================================================================
+// Request Logging
+// ============================================================================
+
+// requestLogger is the configuration set by WithRequestLogger
+type requestLogger struct {
+	logger *slog.Logger
+	redact func(string) string // nil = don't log content
+}
+
+// callRecord is one tool call of a request, kept for the request log
+type callRecord struct {
+	name    string
+	latency time.Duration
+	outcome ToolOutcome
+	input   string
+}
+
+// WithRequestLogger logs each request that executes tools: one "tool call"
+// record per call with the tool name, latency and error kind, followed by a
+// "tool request" record with the tools called, the call count and the total
+// latency.
+//
+// Tool inputs and results may contain personal data, so they are only
+// logged when redactor is non-nil, and always after passing through it.
+// Use an identity function to log content unchanged. A nil logger uses
+// slog.Default().
+func WithRequestLogger(logger *slog.Logger, redactor func(string) string) Option {
+	return func(c *config) {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		c.requestLog = &requestLogger{logger: logger, redact: redactor}
+	}
+}
+
+// record adds a finished call to the request log, if logging is enabled
+func (x *executor) record(name string, input []byte, started time.Time, outcome ToolOutcome) {
+	if x.cfg.requestLog == nil {
+		return
+	}
+	x.mu.Lock()
+	x.records = append(x.records, callRecord{
+		name:    name,
+		latency: time.Since(started),
+		outcome: outcome,
+		input:   string(input),
+	})
+	x.mu.Unlock()
+}
+
+// logRequest writes the records of the calls made so far, if logging is
+// enabled. Adapters call it once all of a request's calls are done.
+func (x *executor) logRequest() {
+	rl := x.cfg.requestLog
+	if rl == nil {
+		return
+	}
+	ctx := x.requestContext()
+
+	x.mu.Lock()
+	records := x.records
+	x.mu.Unlock()
+
+	names := make([]string, 0, len(records))
+	failed := 0
+	for _, r := range records {
+		names = append(names, r.name)
+		attrs := []slog.Attr{
+			slog.String("tool", r.name),
+			slog.Duration("latency", r.latency),
+		}
+		level := slog.LevelInfo
+		if r.outcome.IsError {
+			failed++
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("error_kind", string(r.outcome.Kind)))
+		}
+		if rl.redact != nil {
+			attrs = append(attrs,
+				slog.String("input", rl.redact(r.input)),
+				slog.String("output", rl.redact(r.outcome.Content)),
+			)
+		}
+		rl.logger.LogAttrs(ctx, level, "tool call", attrs...)
+	}
+
+	attrs := []slog.Attr{
+		slog.Any("tools", names),
+		slog.Int("calls", len(records)),
+		slog.Int("errors", failed),
+		slog.Duration("latency", time.Since(x.started)),
+	}
+	if x.ctx != nil {
+		attrs = append(attrs, slog.String("path", x.ctx.Request.URL.Path))
+	}
+	rl.logger.LogAttrs(ctx, slog.LevelInfo, "tool request", attrs...)
+}
@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestWithRequestLogger tests the logged fields and that content is redacted
+func TestWithRequestLogger(t *testing.T) {
+	echo := NewTool("echo", "Echo", nil, func(input json.RawMessage) (any, error) {
+		var v map[string]any
+		json.Unmarshal(input, &v)
+		return v, nil
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	redact := func(s string) string {
+		return strings.ReplaceAll(s, "alice@example.com", "[email]")
+	}
+	handler := AnthropicAdapterWithOptions([]Tool{echo}, WithRequestLogger(logger, redact))
+
+	rec := postJSON(t, handler, AnthropicChatRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []AnthropicMessage{{Role: "user", Content: []AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "echo", Input: map[string]any{"to": "alice@example.com"}},
+			{Type: "tool_use", ID: "toolu_2", Name: "missing", Input: map[string]any{}},
+		}}},
+	})
+	if rec.Code != 200 {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if strings.Contains(buf.String(), "alice@example.com") {
+		t.Errorf("Expected content to be redacted, got log: %s", buf.String())
+	}
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var r map[string]any
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("Failed to parse log line %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 2 call records and 1 request record, got %d: %s", len(records), buf.String())
+	}
+
+	first := records[0]
+	if first["msg"] != "tool call" || first["tool"] != "echo" || first["input"] != `{"to":"[email]"}` || first["output"] != `{"to":"[email]"}` {
+		t.Errorf("Unexpected first call record: %v", first)
+	}
+	if _, ok := first["latency"]; !ok {
+		t.Errorf("Expected latency in call record: %v", first)
+	}
+	if second := records[1]; second["level"] != "WARN" || second["error_kind"] != string(KindNotFound) {
+		t.Errorf("Unexpected second call record: %v", second)
+	}
+
+	summary := records[2]
+	tools, _ := summary["tools"].([]any)
+	if summary["msg"] != "tool request" || summary["calls"] != float64(2) || summary["errors"] != float64(1) || len(tools) != 2 || tools[0] != "echo" {
+		t.Errorf("Unexpected request record: %v", summary)
+	}
+	if summary["path"] != "/chat" {
+		t.Errorf("Expected path /chat, got %v", summary["path"])
+	}
+}
+
+// TestWithRequestLogger_NoRedactor tests that content is omitted without a redactor
+func TestWithRequestLogger_NoRedactor(t *testing.T) {
+	echo := NewTool("echo", "Echo", nil, func(input json.RawMessage) (any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+
+	var buf bytes.Buffer
+	handler := ExecHandlerWithOptions([]Tool{echo}, WithRequestLogger(slog.New(slog.NewJSONHandler(&buf, nil)), nil))
+	rec := postJSON(t, handler, map[string]any{"secret": "s3cr3t"})
+	if rec.Code != 200 {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	if strings.Contains(buf.String(), "s3cr3t") || strings.Contains(buf.String(), `"input"`) {
+		t.Errorf("Expected no content in log, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"msg":"tool request"`) {
+		t.Errorf("Expected a request record, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+package adapter
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// ============================================================================
+// Conversation Sessions
+// ============================================================================
+
+// SessionHeader is the request header carrying the session ID when the
+// request body has no session_id field
+const SessionHeader = "X-Session-ID"
+
+// SessionStore persists conversation history between requests.
+// *tool.MemoryStore satisfies it.
+type SessionStore interface {
+	Set(key string, value any, ttlSeconds int) (map[string]any, error)
+	Get(key string) (map[string]any, error)
+}
+
+// sessions loads and saves message history in a SessionStore
+type sessions struct {
+	store SessionStore
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*sessionLock // by session ID, while held or awaited
+}
+
+// sessionLock serialises the requests of one session
+type sessionLock struct {
+	mu   sync.Mutex
+	refs int // requests holding or waiting for mu
+}
+
+// WithSessions makes the adapter remember the message history of each
+// session, so clients only need to send the latest turn. The session ID comes
+// from the request's session_id field or the X-Session-ID header. Stored
+// history is prepended to the request's messages, and the new messages plus
+// any tool results are saved back with the given TTL (0 = no expiry).
+// Concurrent requests of one session are handled one at a time, so none of
+// their turns are lost.
+func WithSessions(store SessionStore, ttl time.Duration) Option {
+	return func(c *config) {
+		c.sessions = &sessions{store: store, ttl: ttl}
+	}
+}
+
+// sessionID returns the ID from the request body, else from the header
+func sessionID(ctx *blaze.Context, fromBody string) string {
+	if fromBody != "" {
+		return fromBody
+	}
+	return ctx.Request.Header.Get(SessionHeader)
+}
+
+// lock waits until no other request holds session id and returns the
+// function releasing it, which is safe to call more than once. Holding the
+// lock from load to save keeps concurrent requests from overwriting each
+// other's turns.
+func (s *sessions) lock(id string) (unlock func()) {
+	s.mu.Lock()
+	if s.locks == nil {
+		s.locks = make(map[string]*sessionLock)
+	}
+	l := s.locks[id]
+	if l == nil {
+		l = &sessionLock{}
+		s.locks[id] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.mu.Lock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Unlock()
+			s.mu.Lock()
+			if l.refs--; l.refs == 0 {
+				delete(s.locks, id)
+			}
+			s.mu.Unlock()
+		})
+	}
+}
+
+// load decodes the stored history for id into v. It reports whether any
+// history was found.
+func (s *sessions) load(id string, v any) bool {
+	result, err := s.store.Get(sessionKey(id))
+	if err != nil || result["found"] != true {
+		return false
+	}
+	raw, ok := result["value"].(string)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal([]byte(raw), v) == nil
+}
+
+// save stores v as the history for id. History is stored as JSON so later
+// changes to the request values cannot leak into the store.
+func (s *sessions) save(id string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	ttl := int(s.ttl / time.Second)
+	if s.ttl > 0 && ttl == 0 {
+		ttl = 1
+	}
+	s.store.Set(sessionKey(id), string(data), ttl)
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
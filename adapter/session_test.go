@@ -0,0 +1,248 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+)
+
+// mapStore is a minimal SessionStore for tests
+type mapStore struct {
+	mu      sync.Mutex
+	values  map[string]any
+	expires map[string]time.Time
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{values: map[string]any{}, expires: map[string]time.Time{}}
+}
+
+func (s *mapStore) Set(key string, value any, ttlSeconds int) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	delete(s.expires, key)
+	if ttlSeconds > 0 {
+		s.expires[key] = time.Now().Add(time.Duration(ttlSeconds) * time.Second)
+	}
+	return map[string]any{"success": true}, nil
+}
+
+func (s *mapStore) Get(key string) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	if exp, has := s.expires[key]; !ok || (has && time.Now().After(exp)) {
+		return map[string]any{"found": false}, nil
+	}
+	return map[string]any{"found": true, "value": value}, nil
+}
+
+// TestAnthropicAdapter_Session tests that history accumulates across requests
+func TestAnthropicAdapter_Session(t *testing.T) {
+	store := newMapStore()
+	var forwarded AnthropicChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(AnthropicChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+	h := AnthropicAdapterWithOptions([]Tool{
+		NewTool("echo", "Echo", objectSchema, func(input json.RawMessage) (any, error) {
+			return "echoed", nil
+		}),
+	}, WithSessions(store, time.Minute), WithFallback(backend))
+
+	// First turn: a tool call, whose result is stored with the history
+	postJSON(t, h, map[string]any{
+		"model":      "claude-3-5-sonnet",
+		"session_id": "s1",
+		"messages": []map[string]any{
+			{"role": "user", "content": []map[string]any{
+				{"type": "tool_use", "id": "toolu_1", "name": "echo", "input": map[string]any{}},
+			}},
+		},
+	})
+
+	var saved []AnthropicMessage
+	if err := json.Unmarshal([]byte(store.values[sessionKey("s1")].(string)), &saved); err != nil {
+		t.Fatalf("Failed to parse stored session: %v", err)
+	}
+	if len(saved) != 2 || saved[1].Role != "user" {
+		t.Fatalf("Expected tool results saved as a user turn, got %+v", saved)
+	}
+	if blocks := parseContentBlocks(saved[1].Content); len(blocks) != 1 || blocks[0].Type != "tool_result" {
+		t.Errorf("Expected a saved tool_result, got %+v", blocks)
+	}
+
+	// Second turn: only the latest message is sent
+	postJSON(t, h, map[string]any{
+		"model":      "claude-3-5-sonnet",
+		"session_id": "s1",
+		"messages":   []map[string]any{{"role": "user", "content": "Thanks"}},
+	})
+
+	if len(forwarded.Messages) != 3 {
+		t.Fatalf("Expected 3 messages in history, got %d: %+v", len(forwarded.Messages), forwarded.Messages)
+	}
+	if forwarded.Messages[1].Role != "user" {
+		t.Errorf("Expected stored tool results as user turn, got %s", forwarded.Messages[1].Role)
+	}
+	results := parseContentBlocks(forwarded.Messages[1].Content)
+	if len(results) != 1 || results[0].Type != "tool_result" || results[0].ToolUseID != "toolu_1" {
+		t.Errorf("Expected stored tool_result, got %+v", results)
+	}
+	if forwarded.Messages[2].Content != "Thanks" {
+		t.Errorf("Expected latest message last, got %v", forwarded.Messages[2].Content)
+	}
+
+	// Another session starts empty
+	postJSON(t, h, map[string]any{
+		"model":      "claude-3-5-sonnet",
+		"session_id": "s2",
+		"messages":   []map[string]any{{"role": "user", "content": "Hi"}},
+	})
+	if len(forwarded.Messages) != 1 {
+		t.Errorf("Expected a fresh session, got %d messages", len(forwarded.Messages))
+	}
+}
+
+// TestOpenAIAdapter_SessionHeader tests session history keyed by the X-Session-ID header
+func TestOpenAIAdapter_SessionHeader(t *testing.T) {
+	store := newMapStore()
+	var forwarded OpenAIChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(OpenAIChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+	e := blaze.New()
+	e.POST("/chat", OpenAIAdapterWithOptions(nil, WithSessions(store, time.Minute), WithFallback(backend)))
+
+	send := func(content string) {
+		body, _ := json.Marshal(OpenAIChatRequest{
+			Model:    "gpt-4",
+			Messages: []OpenAIMessage{{Role: "user", Content: content}},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(SessionHeader, "abc")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	send("first")
+	send("second")
+
+	if len(forwarded.Messages) != 2 {
+		t.Fatalf("Expected 2 messages in history, got %d", len(forwarded.Messages))
+	}
+	if forwarded.Messages[0].Content != "first" || forwarded.Messages[1].Content != "second" {
+		t.Errorf("Unexpected history order: %+v", forwarded.Messages)
+	}
+}
+
+// TestAnthropicAdapter_SessionConcurrent tests that concurrent requests of
+// one session all keep their turns
+func TestAnthropicAdapter_SessionConcurrent(t *testing.T) {
+	store := newMapStore()
+	h := AnthropicAdapterWithOptions([]Tool{
+		NewTool("echo", "Echo", objectSchema, func(input json.RawMessage) (any, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "echoed", nil
+		}),
+	}, WithSessions(store, time.Minute))
+
+	const turns = 5
+	var wg sync.WaitGroup
+	for i := range turns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			postJSON(t, h, map[string]any{
+				"model":      "claude-3-5-sonnet",
+				"session_id": "s1",
+				"messages": []map[string]any{
+					{"role": "user", "content": []map[string]any{
+						{"type": "tool_use", "id": fmt.Sprintf("toolu_%d", i), "name": "echo", "input": map[string]any{}},
+					}},
+				},
+			})
+		}()
+	}
+	wg.Wait()
+
+	var history []AnthropicMessage
+	if !(&sessions{store: store}).load("s1", &history) {
+		t.Fatal("Expected stored history")
+	}
+	if len(history) != 2*turns {
+		t.Errorf("Expected %d messages from %d turns, got %d", 2*turns, turns, len(history))
+	}
+}
+
+// TestCohereAdapter_Session tests that the user's message is kept in the
+// session history, with and without tool calls
+func TestCohereAdapter_Session(t *testing.T) {
+	store := newMapStore()
+	var forwarded CohereChatRequest
+	backend := func(ctx *blaze.Context, req any) error {
+		forwarded = req.(CohereChatRequest)
+		return ctx.JSON(200, map[string]any{"forwarded": true})
+	}
+	h := CohereAdapterWithOptions([]Tool{
+		NewTool("echo", "Echo", objectSchema, func(input json.RawMessage) (any, error) {
+			return "echoed", nil
+		}),
+	}, WithSessions(store, time.Minute), WithFallback(backend))
+	send := func(req CohereChatRequest) {
+		t.Helper()
+		e := blaze.New()
+		e.POST("/chat", h)
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set(SessionHeader, "c1")
+		e.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	send(CohereChatRequest{Message: "What's the weather?"})
+	send(CohereChatRequest{Message: "In Paris", ChatHistory: []CohereMessage{
+		{Role: "CHATBOT", ToolCalls: []CohereToolCall{{Name: "echo"}}},
+	}})
+	send(CohereChatRequest{Message: "Thanks"})
+
+	var roles, texts []string
+	for _, m := range forwarded.ChatHistory {
+		roles = append(roles, m.Role)
+		texts = append(texts, m.Message)
+	}
+	if want := []string{"USER", "CHATBOT", "USER", "TOOL"}; !slices.Equal(roles, want) {
+		t.Fatalf("Expected roles %v in history, got %v", want, roles)
+	}
+	if texts[0] != "What's the weather?" || texts[2] != "In Paris" {
+		t.Errorf("Expected the user messages to be replayed, got %q", texts)
+	}
+	if forwarded.Message != "Thanks" {
+		t.Errorf("Expected the current message outside the history, got %q", forwarded.Message)
+	}
+}
+
+// TestSessions_TTL tests that expired sessions start over
+func TestSessions_TTL(t *testing.T) {
+	store := newMapStore()
+	s := &sessions{store: store, ttl: time.Second}
+	s.save("x", []OpenAIMessage{{Role: "user", Content: "old"}})
+
+	store.expires[sessionKey("x")] = time.Now().Add(-time.Second)
+
+	var history []OpenAIMessage
+	if s.load("x", &history) {
+		t.Errorf("Expected expired session to be empty, got %+v", history)
+	}
+}
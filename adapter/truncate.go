@@ -0,0 +1,146 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// Result Truncation
+// ============================================================================
+
+// TruncateResult returns v unchanged if its JSON encoding fits in maxBytes,
+// otherwise a smaller value marked with "_truncated": true. Objects shrink
+// their largest fields, arrays keep their leading items and strings are cut
+// on a UTF-8 boundary. Arrays and strings at the top level are wrapped as
+// {"items": [...], "total_items": n} or {"content": "..."} to carry the
+// marker. A maxBytes of 0 or less disables truncation.
+func TruncateResult(v any, maxBytes int) any {
+	data, err := json.Marshal(v)
+	if err != nil || maxBytes <= 0 || len(data) <= maxBytes {
+		return v
+	}
+
+	var generic any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return v
+	}
+
+	var out map[string]any
+	switch g := generic.(type) {
+	case map[string]any:
+		out = shrinkObject(g, maxBytes)
+	case []any:
+		out = map[string]any{"_truncated": true, "total_items": len(g), "items": []any{}}
+		out["items"] = shrinkArray(g, maxBytes-jsonSize(out)+2)
+	case string:
+		out = map[string]any{"_truncated": true, "content": ""}
+		out["content"] = shrinkString(g, maxBytes-jsonSize(out)+2)
+	default:
+		out = map[string]any{"_truncated": true}
+	}
+
+	// Many small fields can't be trimmed structurally; fall back to raw text
+	if jsonSize(out) > maxBytes {
+		out = map[string]any{"_truncated": true, "content": ""}
+		out["content"] = shrinkString(string(data), maxBytes-jsonSize(out)+2)
+	}
+	return out
+}
+
+// shrinkObject trims the largest fields of obj until it encodes within max
+func shrinkObject(obj map[string]any, max int) map[string]any {
+	obj["_truncated"] = true
+	for range 64 {
+		size := jsonSize(obj)
+		if size <= max {
+			break
+		}
+
+		key := largestField(obj)
+		if key == "" {
+			break
+		}
+		target := jsonSize(obj[key]) - (size - max)
+		switch v := obj[key].(type) {
+		case string:
+			if target > 2 {
+				obj[key] = shrinkString(v, target)
+				continue
+			}
+		case []any:
+			if target > 2 {
+				obj[key] = shrinkArray(v, target)
+				continue
+			}
+		case map[string]any:
+			if target > len(`{"_truncated":true}`) {
+				obj[key] = shrinkObject(v, target)
+				continue
+			}
+		}
+		delete(obj, key)
+	}
+	return obj
+}
+
+// largestField returns the key whose value has the longest encoding
+func largestField(obj map[string]any) string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		if k != "_truncated" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys) // deterministic on ties
+
+	best, bestSize := "", -1
+	for _, k := range keys {
+		if size := jsonSize(obj[k]); size > bestSize {
+			best, bestSize = k, size
+		}
+	}
+	return best
+}
+
+// shrinkArray returns the longest prefix of items that encodes within max
+func shrinkArray(items []any, max int) []any {
+	lo, hi := 0, len(items)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if jsonSize(items[:mid]) <= max {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return items[:lo]
+}
+
+// shrinkString returns the longest prefix of s, cut on a rune boundary, whose
+// JSON encoding fits within max
+func shrinkString(s string, max int) string {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if jsonSize(s[:mid]) <= max {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	for lo > 0 && lo < len(s) && !utf8.RuneStart(s[lo]) {
+		lo--
+	}
+	return s[:lo]
+}
+
+// jsonSize returns the length of v's JSON encoding
+func jsonSize(v any) int {
+	b, _ := json.Marshal(v)
+	return len(b)
+}
@@ -0,0 +1,113 @@
+package adapter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestTruncateResult_Array tests that oversized arrays keep their leading items
+func TestTruncateResult_Array(t *testing.T) {
+	items := make([]any, 100)
+	for i := range items {
+		items[i] = map[string]any{"id": i, "title": strings.Repeat("x", 20)}
+	}
+
+	out := TruncateResult(items, 500)
+	b, _ := json.Marshal(out)
+	if len(b) > 500 {
+		t.Errorf("Expected at most 500 bytes, got %d", len(b))
+	}
+
+	m, ok := out.(map[string]any)
+	if !ok || m["_truncated"] != true {
+		t.Fatalf("Expected truncated wrapper, got %s", b)
+	}
+	kept := m["items"].([]any)
+	if len(kept) == 0 || len(kept) >= 100 {
+		t.Errorf("Expected a partial prefix of items, got %d", len(kept))
+	}
+	if m["total_items"] != 100 {
+		t.Errorf("Expected total_items 100, got %v", m["total_items"])
+	}
+}
+
+// TestTruncateResult_String tests that oversized strings are cut on a rune boundary
+func TestTruncateResult_String(t *testing.T) {
+	s := strings.Repeat("héllo wörld ", 200)
+
+	out := TruncateResult(s, 300)
+	b, _ := json.Marshal(out)
+	if len(b) > 300 {
+		t.Errorf("Expected at most 300 bytes, got %d", len(b))
+	}
+
+	m := out.(map[string]any)
+	content := m["content"].(string)
+	if m["_truncated"] != true || content == "" || !strings.HasPrefix(s, content) {
+		t.Errorf("Expected truncated prefix, got %s", b)
+	}
+	if !utf8.ValidString(content) {
+		t.Errorf("Expected valid UTF-8, got %q", content)
+	}
+}
+
+// TestShrinkString_Fits tests that a string within the limit is returned whole
+func TestShrinkString_Fits(t *testing.T) {
+	if got := shrinkString("héllo", 100); got != "héllo" {
+		t.Errorf("Expected the whole string, got %q", got)
+	}
+}
+
+// TestTruncateResult_Object tests that the largest field of an object is trimmed
+func TestTruncateResult_Object(t *testing.T) {
+	v := map[string]any{
+		"status": 200,
+		"url":    "https://example.com",
+		"body":   strings.Repeat("a", 5000),
+	}
+
+	out := TruncateResult(v, 1000).(map[string]any)
+	b, _ := json.Marshal(out)
+	if len(b) > 1000 {
+		t.Errorf("Expected at most 1000 bytes, got %d", len(b))
+	}
+	if out["_truncated"] != true || out["url"] != "https://example.com" || out["status"] == nil {
+		t.Errorf("Expected small fields kept and marker set, got %s", b)
+	}
+	if body := out["body"].(string); len(body) == 0 || len(body) >= 5000 {
+		t.Errorf("Expected body trimmed, got %d bytes", len(body))
+	}
+
+	// Results within the budget are returned unchanged
+	small := map[string]any{"ok": true}
+	if got := TruncateResult(small, 1000); got.(map[string]any)["_truncated"] != nil {
+		t.Errorf("Expected small result untouched, got %v", got)
+	}
+}
+
+// TestWithMaxToolResultBytes tests that the executor truncates large results
+func TestWithMaxToolResultBytes(t *testing.T) {
+	big := NewTool("big", "Big", objectSchema, func(input json.RawMessage) (any, error) {
+		return strings.Repeat("z", 10000), nil
+	})
+	h := OpenAIAdapterWithOptions([]Tool{big}, WithMaxToolResultBytes(200))
+
+	rec := postJSON(t, h, OpenAIChatRequest{
+		Model: "gpt-4",
+		Messages: []OpenAIMessage{{
+			Role:      "assistant",
+			ToolCalls: []OpenAIToolCall{{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "big", Arguments: "{}"}}},
+		}},
+	})
+
+	var resp OpenAIChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if len(content) > 200 || !strings.Contains(content, `"_truncated":true`) {
+		t.Errorf("Expected truncated result within 200 bytes, got %d bytes: %s", len(content), content)
+	}
+}
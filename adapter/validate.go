@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ============================================================================
+// Tool Validation
+// ============================================================================
+
+// toolNamePattern is the name format accepted by both OpenAI and Anthropic
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Validate checks that the tool has a provider-compatible name, a handler,
+// and an InputSchema that serializes to a JSON object
+func (t Tool) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if !toolNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("tool name %q must match %s", t.Name, toolNamePattern)
+	}
+	if t.Handler == nil {
+		return fmt.Errorf("tool %q has no handler", t.Name)
+	}
+
+	schemaBytes, err := json.Marshal(t.InputSchema)
+	if err != nil {
+		return fmt.Errorf("tool %q input schema is not JSON-serializable: %w", t.Name, err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(schemaBytes, &schema); err != nil || schema == nil {
+		return fmt.Errorf("tool %q input schema must be a JSON object", t.Name)
+	}
+	return nil
+}
+
+// NewToolChecked is like NewTool but returns an error if the tool is invalid
+func NewToolChecked(name, desc string, schema any, handler func(json.RawMessage) (any, error)) (Tool, error) {
+	t := NewTool(name, desc, schema, handler)
+	if err := t.Validate(); err != nil {
+		return Tool{}, err
+	}
+	return t, nil
+}
+
+// ValidateTools validates each tool and checks that no two share a name
+func ValidateTools(tools ...Tool) error {
+	for _, t := range tools {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+	if name := duplicateToolName(tools); name != "" {
+		return fmt.Errorf("duplicate tool name %q", name)
+	}
+	return nil
+}
+
+// duplicateToolName returns the first name registered more than once, or ""
+func duplicateToolName(tools []Tool) string {
+	seen := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		if seen[t.Name] {
+			return t.Name
+		}
+		seen[t.Name] = true
+	}
+	return ""
+}
+
+// mustBuildToolMap indexes tools by name, panicking on duplicates since one
+// would silently shadow the other
+func mustBuildToolMap(tools []Tool) map[string]Tool {
+	if name := duplicateToolName(tools); name != "" {
+		panic(fmt.Sprintf("adapter: duplicate tool name %q", name))
+	}
+	toolMap := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		toolMap[tool.Name] = tool
+	}
+	return toolMap
+}
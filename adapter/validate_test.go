@@ -0,0 +1,51 @@
+package adapter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func noopHandler(input json.RawMessage) (any, error) { return nil, nil }
+
+var objectSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+
+// TestTool_Validate tests name and schema validation
+func TestTool_Validate(t *testing.T) {
+	if _, err := NewToolChecked("web_search-v2", "Search", objectSchema, noopHandler); err != nil {
+		t.Errorf("Expected valid tool, got error: %v", err)
+	}
+
+	cases := map[string]Tool{
+		"empty name":     NewTool("", "desc", objectSchema, noopHandler),
+		"bad name":       NewTool("web search!", "desc", objectSchema, noopHandler),
+		"long name":      NewTool(strings.Repeat("a", 65), "desc", objectSchema, noopHandler),
+		"nil handler":    NewTool("tool", "desc", objectSchema, nil),
+		"non-object":     NewTool("tool", "desc", []string{"a"}, noopHandler),
+		"nil schema":     NewTool("tool", "desc", nil, noopHandler),
+		"unserializable": NewTool("tool", "desc", map[string]any{"f": func() {}}, noopHandler),
+	}
+	for name, tool := range cases {
+		if err := tool.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+// TestValidateTools_Duplicate tests duplicate name detection
+func TestValidateTools_Duplicate(t *testing.T) {
+	a := NewTool("echo", "first", objectSchema, noopHandler)
+	b := NewTool("echo", "second", objectSchema, noopHandler)
+
+	err := ValidateTools(a, b)
+	if err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("Expected duplicate error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected adapter construction to panic on duplicate tools")
+		}
+	}()
+	AnthropicAdapter(a, b)
+}
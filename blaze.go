@@ -1,8 +1,14 @@
 package blaze
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 // HandlerFunc defines the handler signature with error return
@@ -11,17 +17,31 @@ type HandlerFunc func(*Context) error
 // MiddlewareFunc defines the middleware signature
 type MiddlewareFunc func(HandlerFunc) HandlerFunc
 
+// ErrorHandler handles an error returned by a route handler
+type ErrorHandler func(*Context, error)
+
+// ErrShutdown is the cause of the shutdown context once Engine.Shutdown is
+// called
+var ErrShutdown = errors.New("server shutting down")
+
 // Engine is the core framework instance
 type Engine struct {
 	router     *Router
 	middleware []MiddlewareFunc
+
+	mu       sync.Mutex
+	server   *http.Server // set by Listen
+	draining context.Context
+	drain    context.CancelCauseFunc
 }
 
 // New creates a new Engine instance
 func New() *Engine {
-	return &Engine{
+	e := &Engine{
 		router: newRouter(),
 	}
+	e.draining, e.drain = context.WithCancelCause(context.Background())
+	return e
 }
 
 // Use adds global middleware
@@ -29,6 +49,21 @@ func (e *Engine) Use(middleware ...MiddlewareFunc) {
 	e.middleware = append(e.middleware, middleware...)
 }
 
+// OnError sets a central handler for errors returned by route handlers,
+// replacing the default response (HTTPError as JSON, anything else as 500).
+// Use it to log errors or render a consistent error envelope.
+func (e *Engine) OnError(h ErrorHandler) {
+	e.router.errorHandler = h
+}
+
+// StrictJSON makes Context.BindJSON behave like BindJSONStrict for every
+// route, rejecting request bodies with fields the target has no field for.
+// The adapters bind requests with BindJSON, so they answer such requests
+// with a 400 naming the field.
+func (e *Engine) StrictJSON() {
+	e.router.strictJSON = true
+}
+
 // Handle registers a route with any HTTP method
 func (e *Engine) Handle(method, path string, handler HandlerFunc) {
 	// Apply middleware in reverse order
@@ -52,14 +87,87 @@ func (e *Engine) Group(prefix string) *Group {
 	return &Group{engine: e, prefix: prefix}
 }
 
-// Listen starts the HTTP server
+// Mount serves prefix and every path below it with h, which may be another
+// Engine. The prefix is stripped from the request path, so a request for
+// prefix+"/users" reaches h as "/users" and prefix itself as "/". Engine
+// middleware runs before h; routes registered below prefix take precedence.
+func (e *Engine) Mount(prefix string, h http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	handler := func(c *Context) error {
+		h.ServeHTTP(c.ResponseWriter, stripPrefix(c.Request, prefix))
+		return nil
+	}
+	for _, method := range routeMethods {
+		e.Handle(method, prefix, handler)
+		e.Handle(method, prefix+"/*path", handler)
+	}
+}
+
+// stripPrefix returns a shallow copy of r with prefix removed from its path
+func stripPrefix(r *http.Request, prefix string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = cmp.Or(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if r.URL.RawPath != "" {
+		r2.URL.RawPath = cmp.Or(strings.TrimPrefix(r.URL.RawPath, prefix), "/")
+	}
+	return r2
+}
+
+// Listen starts the HTTP server. It returns nil once Shutdown stopped it.
 func (e *Engine) Listen(addr string) error {
+	srv := &http.Server{Addr: addr, Handler: e}
+	e.mu.Lock()
+	e.server = srv
+	e.mu.Unlock()
+
 	log.Printf("Blaze running on %s", addr)
-	return http.ListenAndServe(addr, e)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the server started by Listen. It first cancels
+// the shutdown context of every request (see ShutdownContext), so streaming
+// handlers can finish their responses with a terminal event, then waits
+// for active requests to complete until ctx is done, like
+// http.Server.Shutdown. When e is served by an http.Server of your own,
+// Shutdown only sends the signal; shut that server down afterwards.
+func (e *Engine) Shutdown(ctx context.Context) error {
+	e.drain(ErrShutdown)
+
+	e.mu.Lock()
+	srv := e.server
+	e.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+// shutdownKey is the request context key of the shutdown context
+type shutdownKey struct{}
+
+// ShutdownContext returns a context that is cancelled, with cause
+// ErrShutdown, once the Engine serving the request with context ctx shuts
+// down. Long-running handlers, such as streams, select on its Done channel
+// to end early. Outside an Engine it is never cancelled.
+func ShutdownContext(ctx context.Context) context.Context {
+	if draining, ok := ctx.Value(shutdownKey{}).(context.Context); ok {
+		return draining
+	}
+	return context.Background()
 }
 
 // ServeHTTP implements http.Handler
 func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// A mounted Engine keeps the shutdown context of the outermost one
+	if r.Context().Value(shutdownKey{}) == nil {
+		r = r.WithContext(context.WithValue(r.Context(), shutdownKey{}, e.draining))
+	}
 	e.router.ServeHTTP(w, r)
 }
 
@@ -77,14 +185,28 @@ func (g *Group) Use(middleware ...MiddlewareFunc) {
 
 // Handle registers a route within the group
 func (g *Group) Handle(method, path string, handler HandlerFunc) {
-	// Apply group middleware first, then engine middleware
+	g.engine.router.handle(method, g.prefix+path, g.wrap(handler))
+}
+
+// NotFound sets the handler for requests below the group's prefix that
+// match no route, with any method, in place of the global 404. Routes of
+// the group and of nested groups, including wildcards, take precedence.
+func (g *Group) NotFound(handler HandlerFunc) {
+	handler = g.wrap(handler)
+	for _, method := range routeMethods {
+		g.engine.router.handleFallback(method, g.prefix, handler)
+	}
+}
+
+// wrap applies the group middleware first, then the engine middleware
+func (g *Group) wrap(handler HandlerFunc) HandlerFunc {
 	for i := len(g.middleware) - 1; i >= 0; i-- {
 		handler = g.middleware[i](handler)
 	}
 	for i := len(g.engine.middleware) - 1; i >= 0; i-- {
 		handler = g.engine.middleware[i](handler)
 	}
-	g.engine.router.handle(method, g.prefix+path, handler)
+	return handler
 }
 
 // HTTP method shortcuts for Group
@@ -0,0 +1,272 @@
+package blaze
+
+import (
+	"bytes"
+	"cmp"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig defines response cache options
+type CacheConfig struct {
+	TTL        time.Duration // how long a stored response stays fresh
+	MaxEntries int           // LRU capacity; oldest entries are evicted first
+	Methods    []string      // cacheable methods (default: GET)
+	HashBody   bool          // include a hash of the request body in the key
+}
+
+// DefaultCacheConfig provides sensible defaults
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		TTL:        5 * time.Minute,
+		MaxEntries: 1000,
+		Methods:    []string{"GET"},
+	}
+}
+
+// cachedResponse is a stored handler response
+type cachedResponse struct {
+	key       string
+	status    int
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+	vary      []string // for a marker entry: the request headers the response varies on
+}
+
+// responseCache is a fixed-size LRU of responses
+type responseCache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // front = most recently used
+	maxEntries int
+}
+
+func newResponseCache(maxEntries int) *responseCache {
+	return &responseCache{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+	}
+}
+
+// get returns a fresh entry and marks it as recently used
+func (rc *responseCache) get(key string) (*cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	el, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cachedResponse)
+	if time.Now().After(entry.expiresAt) {
+		rc.order.Remove(el)
+		delete(rc.entries, key)
+		return nil, false
+	}
+	rc.order.MoveToFront(el)
+	return entry, true
+}
+
+// set stores an entry, evicting the least recently used one when full
+func (rc *responseCache) set(entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if el, ok := rc.entries[entry.key]; ok {
+		el.Value = entry
+		rc.order.MoveToFront(el)
+		return
+	}
+
+	rc.entries[entry.key] = rc.order.PushFront(entry)
+	for rc.maxEntries > 0 && rc.order.Len() > rc.maxEntries {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// Cache returns a middleware that serves repeated requests from an in-memory
+// LRU cache. Only successful (2xx) responses are stored, with the headers
+// the handler set. Responses with a Vary header are stored per value of the
+// request headers it names; Vary: * is not stored. Zero fields of config
+// take their values from DefaultCacheConfig.
+func Cache(config ...CacheConfig) MiddlewareFunc {
+	def := DefaultCacheConfig()
+	cfg := def
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.TTL = cmp.Or(cfg.TTL, def.TTL)
+	cfg.MaxEntries = cmp.Or(cfg.MaxEntries, def.MaxEntries)
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = def.Methods
+	}
+
+	store := newResponseCache(cfg.MaxEntries)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if !contains(cfg.Methods, c.Request.Method) {
+				return next(c)
+			}
+
+			key, err := cacheKey(c.Request, cfg.HashBody)
+			if err != nil {
+				return err
+			}
+
+			base := key
+			entry, ok := store.get(key)
+			if ok && entry.vary != nil {
+				key += varySuffix(c.Request, entry.vary)
+				entry, ok = store.get(key)
+			}
+			if ok {
+				// Keep what outer middleware set, e.g. CORS headers
+				h := c.ResponseWriter.Header()
+				for k, values := range entry.header {
+					for _, v := range values {
+						if !slices.Contains(h[k], v) {
+							h[k] = append(h[k], v)
+						}
+					}
+				}
+				h.Set("X-Cache", "HIT")
+				c.ResponseWriter.WriteHeader(entry.status)
+				_, err := c.ResponseWriter.Write(entry.body)
+				return err
+			}
+
+			c.SetHeader("X-Cache", "MISS")
+			before := c.ResponseWriter.Header().Clone()
+			rec := &cacheRecorder{ResponseWriter: c.ResponseWriter, status: http.StatusOK}
+			c.ResponseWriter = rec
+			err = next(c)
+			c.ResponseWriter = rec.ResponseWriter
+
+			if err != nil || rec.status < 200 || rec.status >= 300 {
+				return err
+			}
+			vary := varyNames(rec.Header())
+			if slices.Contains(vary, "*") {
+				return nil
+			}
+			expiresAt := time.Now().Add(cfg.TTL)
+			key = base
+			if len(vary) > 0 {
+				store.set(&cachedResponse{key: base, vary: vary, expiresAt: expiresAt})
+				key += varySuffix(c.Request, vary)
+			}
+			store.set(&cachedResponse{
+				key:       key,
+				status:    rec.status,
+				header:    addedHeaders(before, rec.Header()),
+				body:      rec.body.Bytes(),
+				expiresAt: expiresAt,
+			})
+			return nil
+		}
+	}
+}
+
+// addedHeaders returns the header values in after that aren't in before,
+// i.e. those set by the handler rather than by outer middleware
+func addedHeaders(before, after http.Header) http.Header {
+	added := http.Header{}
+	for k, values := range after {
+		if prev := before[k]; len(values) >= len(prev) && slices.Equal(values[:len(prev)], prev) {
+			values = values[len(prev):]
+		}
+		if len(values) > 0 {
+			added[k] = slices.Clone(values)
+		}
+	}
+	return added
+}
+
+// varyNames returns the canonical, sorted header names listed in h's Vary
+func varyNames(h http.Header) []string {
+	var names []string
+	for _, line := range h.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// varySuffix returns the part of the cache key formed by the values of the
+// request headers in names
+func varySuffix(r *http.Request, names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString("\x00" + name + "=" + strings.Join(r.Header.Values(name), ","))
+	}
+	return b.String()
+}
+
+// cacheKey builds the cache key from method, path and query, and optionally
+// a hash of the body (which is restored for the handler)
+func cacheKey(r *http.Request, hashBody bool) (string, error) {
+	key := r.Method + " " + r.URL.Path + "?" + r.URL.Query().Encode()
+	if !hashBody || r.Body == nil {
+		return key, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha256.Sum256(body)
+	return key + "#" + hex.EncodeToString(sum[:]), nil
+}
+
+// cacheRecorder tees the response into a buffer while writing it through
+type cacheRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *cacheRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *cacheRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher so streaming handlers keep working
+func (r *cacheRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// contains reports whether s is in list
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
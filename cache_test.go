@@ -0,0 +1,186 @@
+package blaze
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCache_HitAfterMiss(t *testing.T) {
+	calls := 0
+	e := New()
+	e.Use(Cache(CacheConfig{TTL: time.Minute, MaxEntries: 10}))
+	e.GET("/search", func(c *Context) error {
+		calls++
+		return c.String(200, "results for "+c.Query("q"))
+	})
+
+	for i, want := range []string{"MISS", "HIT"} {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", "/search?q=go", nil))
+
+		if got := w.Header().Get("X-Cache"); got != want {
+			t.Fatalf("request %d: expected X-Cache=%s, got %s", i, want, got)
+		}
+		if w.Body.String() != "results for go" {
+			t.Fatalf("request %d: unexpected body %q", i, w.Body.String())
+		}
+		if w.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Fatalf("request %d: content type not preserved", i)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+
+	// A different query is a different key
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/search?q=rust", nil))
+	if w.Header().Get("X-Cache") != "MISS" || calls != 2 {
+		t.Fatalf("expected miss for new query, got %s (calls=%d)", w.Header().Get("X-Cache"), calls)
+	}
+}
+
+func TestCache_TTLExpiry(t *testing.T) {
+	calls := 0
+	e := New()
+	e.Use(Cache(CacheConfig{TTL: 20 * time.Millisecond, MaxEntries: 10}))
+	e.GET("/time", func(c *Context) error {
+		calls++
+		return c.String(200, "ok")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/time", nil))
+	time.Sleep(40 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/time", nil))
+	if w.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("expected expired entry to miss, got %s", w.Header().Get("X-Cache"))
+	}
+	if calls != 2 {
+		t.Fatalf("expected handler to run twice, ran %d times", calls)
+	}
+}
+
+func TestCache_SkipsErrors(t *testing.T) {
+	calls := 0
+	e := New()
+	e.Use(Cache())
+	e.GET("/flaky", func(c *Context) error {
+		calls++
+		return c.String(502, "upstream down")
+	})
+
+	for range 2 {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/flaky", nil))
+	}
+	if calls != 2 {
+		t.Fatalf("expected non-2xx responses to bypass the cache, handler ran %d times", calls)
+	}
+}
+
+func TestCache_LRUEviction(t *testing.T) {
+	rc := newResponseCache(2)
+	expires := time.Now().Add(time.Minute)
+	rc.set(&cachedResponse{key: "a", expiresAt: expires})
+	rc.set(&cachedResponse{key: "b", expiresAt: expires})
+	rc.get("a") // a is now most recently used
+	rc.set(&cachedResponse{key: "c", expiresAt: expires})
+
+	if _, ok := rc.get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, ok := rc.get("a"); !ok {
+		t.Fatal("expected a to survive eviction")
+	}
+}
+
+func TestCache_PartialConfigUsesDefaults(t *testing.T) {
+	calls := 0
+	e := New()
+	e.Use(Cache(CacheConfig{MaxEntries: 10}))
+	e.GET("/search", func(c *Context) error {
+		calls++
+		return c.String(200, "ok")
+	})
+
+	for range 2 {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/search", nil))
+	}
+	if calls != 1 {
+		t.Fatalf("expected the default TTL to keep the entry, handler ran %d times", calls)
+	}
+}
+
+func TestCache_StoresOnlyHandlerHeaders(t *testing.T) {
+	e := New()
+	n := 0
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			n++
+			c.SetHeader("X-Trace", strconv.Itoa(n))
+			return next(c)
+		}
+	})
+	e.Use(Cache())
+	e.GET("/data", func(c *Context) error {
+		c.SetHeader("X-Handler", "yes")
+		return c.String(200, "ok")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/data", nil))
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/data", nil))
+
+	if w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expected a hit, got %s", w.Header().Get("X-Cache"))
+	}
+	if got := w.Header().Values("X-Trace"); len(got) != 1 || got[0] != "2" {
+		t.Errorf("expected only the outer header of this request, got %q", got)
+	}
+	if w.Header().Get("X-Handler") != "yes" {
+		t.Errorf("expected the handler's header to be replayed")
+	}
+}
+
+func TestCache_Vary(t *testing.T) {
+	calls := 0
+	e := New()
+	e.Use(CORS(CORSConfig{AllowOrigins: []string{"https://a.example.com", "https://b.example.com"}}))
+	e.Use(Cache())
+	e.GET("/tools", func(c *Context) error {
+		calls++
+		c.ResponseWriter.Header().Add("Vary", "Accept")
+		return c.String(200, "format: "+c.Request.Header.Get("Accept"))
+	})
+
+	get := func(origin, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/tools", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+		return w
+	}
+
+	get("https://a.example.com", "application/json")
+	if w := get("https://a.example.com", "application/yaml"); w.Header().Get("X-Cache") != "MISS" || w.Body.String() != "format: application/yaml" {
+		t.Errorf("expected a miss for another Accept, got %s %q", w.Header().Get("X-Cache"), w.Body.String())
+	}
+	w := get("https://b.example.com", "application/json")
+	if w.Header().Get("X-Cache") != "MISS" || w.Header().Get("Access-Control-Allow-Origin") != "https://b.example.com" {
+		t.Errorf("expected a miss with b's origin for another Origin, got %s %q", w.Header().Get("X-Cache"), w.Header().Get("Access-Control-Allow-Origin"))
+	}
+	w = get("https://a.example.com", "application/json")
+	if w.Header().Get("X-Cache") != "HIT" || w.Body.String() != "format: application/json" {
+		t.Errorf("expected a hit for a repeated request, got %s %q", w.Header().Get("X-Cache"), w.Body.String())
+	}
+	if got := w.Header().Values("Access-Control-Allow-Origin"); len(got) != 1 || got[0] != "https://a.example.com" {
+		t.Errorf("expected a single allowed origin, got %q", got)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 handler calls, got %d", calls)
+	}
+}
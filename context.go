@@ -1,8 +1,19 @@
 package blaze
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 // Context wraps the request and response for convenient access
@@ -11,6 +22,9 @@ type Context struct {
 	Request        *http.Request
 	params         map[string]string
 	statusCode     int
+	route          string
+	strictJSON     bool
+	body           []byte // buffered request body, see Body
 }
 
 // Param returns a URL path parameter by key
@@ -18,6 +32,85 @@ func (c *Context) Param(key string) string {
 	return c.params[key]
 }
 
+// ParamInt returns a URL path parameter as an int. A value that isn't a
+// base-10 integer fails with a 400 HTTPError naming the parameter, so
+// handlers can return it as is.
+func (c *Context) ParamInt(key string) (int, error) {
+	value := c.params[key]
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("path parameter %q must be an integer", key), value)
+	}
+	return n, nil
+}
+
+// ParamIntDefault returns a URL path parameter as an int, or defaultVal if
+// it is missing or not an integer
+func (c *Context) ParamIntDefault(key string, defaultVal int) int {
+	if n, err := c.ParamInt(key); err == nil {
+		return n
+	}
+	return defaultVal
+}
+
+// ParamUUID returns a URL path parameter that must be a UUID in its
+// 8-4-4-4-12 hex form, lowercased. Anything else fails with a 400
+// HTTPError naming the parameter. The version and variant bits aren't
+// checked.
+func (c *Context) ParamUUID(key string) (string, error) {
+	value := c.params[key]
+	if !isUUID(value) {
+		return "", NewHTTPError(http.StatusBadRequest, fmt.Sprintf("path parameter %q must be a UUID", key), value)
+	}
+	return strings.ToLower(value), nil
+}
+
+// isUUID reports whether s is 32 hex digits grouped 8-4-4-4-12 by hyphens
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Route returns the registered route pattern that matched the request
+// (e.g. "/users/:id")
+func (c *Context) Route() string {
+	return c.route
+}
+
+// ClientIP returns the client's IP address, preferring the first entry of
+// X-Forwarded-For, then X-Real-IP, then the connection's remote address.
+// The proxy headers are client-controlled unless a trusted proxy sets them.
+func (c *Context) ClientIP() string {
+	if fwd := c.Request.Header.Get("X-Forwarded-For"); fwd != "" {
+		ip, _, _ := strings.Cut(fwd, ",")
+		if ip = strings.TrimSpace(ip); ip != "" {
+			return ip
+		}
+	}
+	if ip := strings.TrimSpace(c.Request.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		return c.Request.RemoteAddr
+	}
+	return host
+}
+
 // Query returns a query parameter by key
 func (c *Context) Query(key string) string {
 	return c.Request.URL.Query().Get(key)
@@ -57,6 +150,80 @@ func (c *Context) JSON(code int, data any) error {
 	return json.NewEncoder(c.ResponseWriter).Encode(data)
 }
 
+// jsonpCallbackRe matches dotted JavaScript identifiers such as "cb" or
+// "widget.onTools"
+var jsonpCallbackRe = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*(\.[a-zA-Z_$][a-zA-Z0-9_$]*)*$`)
+
+// JSONP sends data as JSON wrapped in a call to callback. The callback must
+// be a (dotted) JavaScript identifier of at most 128 characters; anything
+// else returns a 400 HTTPError without writing a response.
+func (c *Context) JSONP(code int, callback string, data any) error {
+	if len(callback) > 128 || !jsonpCallbackRe.MatchString(callback) {
+		return NewHTTPError(http.StatusBadRequest, "invalid JSONP callback")
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	c.SetHeader("Content-Type", "application/javascript; charset=utf-8")
+	c.SetHeader("X-Content-Type-Options", "nosniff")
+	c.ResponseWriter.WriteHeader(code)
+	// The leading comment guards against content-sniffing attacks on the callback
+	_, err = c.ResponseWriter.Write([]byte("/**/" + callback + "(" + string(b) + ");"))
+	return err
+}
+
+// JSONWithETag sends a JSON response tagged with a weak ETag computed over the
+// encoded body, or a 304 Not Modified if the client already has it
+func (c *Context) JSONWithETag(code int, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	c.SetETag(`W/"` + hex.EncodeToString(sum[:16]) + `"`)
+	if c.CheckNotModified() {
+		return nil
+	}
+
+	c.SetHeader("Content-Type", "application/json")
+	c.ResponseWriter.WriteHeader(code)
+	_, err = c.ResponseWriter.Write(append(body, '\n'))
+	return err
+}
+
+// SetETag sets the ETag response header
+func (c *Context) SetETag(etag string) {
+	c.SetHeader("ETag", etag)
+}
+
+// CheckNotModified compares the response ETag against the request's
+// If-None-Match header and writes a 304 if they match (weak comparison).
+// Handlers should return without writing a body when it reports true.
+// Only GET and HEAD requests can be answered with a 304; for other methods
+// If-None-Match is ignored, since the handler has usually acted already.
+func (c *Context) CheckNotModified() bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	etag := c.ResponseWriter.Header().Get("ETag")
+	match := c.Request.Header.Get("If-None-Match")
+	if etag == "" || match == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(match, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.ResponseWriter.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
 // HTML sends an HTML response
 func (c *Context) HTML(code int, html string) error {
 	c.SetHeader("Content-Type", "text/html; charset=utf-8")
@@ -65,6 +232,45 @@ func (c *Context) HTML(code int, html string) error {
 	return err
 }
 
+// Blob sends raw bytes with the given content type
+func (c *Context) Blob(code int, contentType string, data []byte) error {
+	c.SetHeader("Content-Type", contentType)
+	c.SetHeader("Content-Length", strconv.Itoa(len(data)))
+	c.ResponseWriter.WriteHeader(code)
+	_, err := c.ResponseWriter.Write(data)
+	return err
+}
+
+// streamChunkSize is how much Stream copies between flushes
+const streamChunkSize = 32 * 1024
+
+// Stream copies r to the response with the given content type, flushing
+// after each chunk so large bodies reach the client progressively
+func (c *Context) Stream(code int, contentType string, r io.Reader) error {
+	c.SetHeader("Content-Type", contentType)
+	c.ResponseWriter.WriteHeader(code)
+
+	flusher, _ := c.ResponseWriter.(http.Flusher)
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := c.ResponseWriter.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
 // Redirect sends an HTTP redirect
 func (c *Context) Redirect(code int, url string) error {
 	http.Redirect(c.ResponseWriter, c.Request, url, code)
@@ -77,10 +283,126 @@ func (c *Context) NoContent() error {
 	return nil
 }
 
-// BindJSON decodes the request body as JSON
+// Error writes an error response with status code in the shape set by
+// ErrorEnvelope, by default {"error": {"code": code, "message": message}}
+func (c *Context) Error(code int, message string) error {
+	return c.JSON(code, ErrorEnvelope(&HTTPError{Code: code, Message: message}))
+}
+
+// Errorf is like Error but formats the message as fmt.Sprintf does
+func (c *Context) Errorf(code int, format string, args ...any) error {
+	return c.Error(code, fmt.Sprintf(format, args...))
+}
+
+// BindJSON decodes the request body as JSON. Fields of the body that v
+// has no field for are ignored, unless the engine uses StrictJSON.
 func (c *Context) BindJSON(v any) error {
+	return c.bindJSON(v, c.strictJSON, false)
+}
+
+// BindJSONStrict is like BindJSON but fails on fields of the body that v has
+// no field for, naming the first, so that typos such as "maxTokens" for
+// "max_tokens" don't go unnoticed
+func (c *Context) BindJSONStrict(v any) error {
+	return c.bindJSON(v, true, false)
+}
+
+// BindJSONNumber is like BindJSON but decodes numbers into any-typed
+// targets as json.Number rather than float64, so integers beyond 2^53,
+// such as large IDs, keep every digit. JSONInt64 and JSONFloat64 read them.
+func (c *Context) BindJSONNumber(v any) error {
+	return c.bindJSON(v, c.strictJSON, true)
+}
+
+// bindJSON decodes the request body into v, rejecting unknown fields if
+// strict and keeping numbers as json.Number if numbers
+func (c *Context) bindJSON(v any, strict, numbers bool) error {
 	defer c.Request.Body.Close()
-	return json.NewDecoder(c.Request.Body).Decode(v)
+	dec := json.NewDecoder(c.Request.Body)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if numbers {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(v); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("unknown field %s", field)
+		}
+		return err
+	}
+	return nil
+}
+
+// Body returns the raw request body. The first call reads and buffers it;
+// every call resets Request.Body to a fresh reader over the buffer, so
+// middleware can inspect the body and the handler still bind it. Use
+// CaptureBody to cap the size.
+func (c *Context) Body() ([]byte, error) {
+	return c.captureBody(0)
+}
+
+// captureBody buffers the request body once, failing with a 413 HTTPError
+// when it exceeds maxSize (0 = no limit)
+func (c *Context) captureBody(maxSize int64) ([]byte, error) {
+	if c.body == nil {
+		if c.Request.Body == nil {
+			c.Request.Body = http.NoBody
+		}
+		r := io.Reader(c.Request.Body)
+		if maxSize > 0 {
+			r = io.LimitReader(r, maxSize+1)
+		}
+		body, err := io.ReadAll(r)
+		c.Request.Body.Close()
+		switch {
+		case errors.Is(err, errBodyTooLarge), err == nil && maxSize > 0 && int64(len(body)) > maxSize:
+			return nil, NewHTTPError(http.StatusRequestEntityTooLarge, errBodyTooLarge.Error())
+		case err != nil:
+			return nil, NewHTTPError(http.StatusBadRequest, "failed to read body", err.Error())
+		}
+		c.body = body
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(c.body))
+	return c.body, nil
+}
+
+// JSONInt64 returns a decoded JSON number as an int64. It accepts
+// json.Number, as from BindJSONNumber, and float64 and int holding a whole
+// number; anything else reports false.
+func JSONInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	}
+	return 0, false
+}
+
+// JSONFloat64 returns a decoded JSON number as a float64. It accepts
+// json.Number, float64, int and int64.
+func JSONFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
 }
 
 // StreamJSON streams JSON objects from a channel
@@ -99,3 +421,36 @@ func (c *Context) StreamJSON(dataChan <-chan any) error {
 	}
 	return nil
 }
+
+// StreamJSONArray streams values from a channel as a single JSON array,
+// flushing after each element. A closed empty channel produces [].
+func (c *Context) StreamJSONArray(dataChan <-chan any) error {
+	c.SetHeader("Content-Type", "application/json")
+	c.SetHeader("Transfer-Encoding", "chunked")
+
+	flusher, _ := c.ResponseWriter.(http.Flusher)
+	if _, err := c.ResponseWriter.Write([]byte("[")); err != nil {
+		return err
+	}
+
+	first := true
+	for data := range dataChan {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if !first {
+			b = append([]byte(","), b...)
+		}
+		first = false
+		if _, err := c.ResponseWriter.Write(b); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	_, err := c.ResponseWriter.Write([]byte("]"))
+	return err
+}
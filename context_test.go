@@ -0,0 +1,384 @@
+package blaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestContext_JSONWithETag(t *testing.T) {
+	e := New()
+	e.GET("/tools", func(c *Context) error {
+		return c.JSONWithETag(200, map[string]any{"count": 3})
+	})
+
+	// First request: full response with ETag
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/tools", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected weak ETag, got %q", etag)
+	}
+	if strings.TrimSpace(w.Body.String()) != `{"count":3}` {
+		t.Fatalf("unexpected body %q", w.Body.String())
+	}
+
+	// Second request with matching If-None-Match: 304 and no body
+	req := httptest.NewRequest("GET", "/tools", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got %q", w.Body.String())
+	}
+	if w.Header().Get("ETag") != etag {
+		t.Fatalf("expected ETag to be echoed on 304")
+	}
+}
+
+func TestContext_CheckNotModified_Mismatch(t *testing.T) {
+	e := New()
+	e.GET("/file", func(c *Context) error {
+		c.SetETag(`"v2"`)
+		if c.CheckNotModified() {
+			return nil
+		}
+		return c.String(200, "content")
+	})
+
+	req := httptest.NewRequest("GET", "/file", nil)
+	req.Header.Set("If-None-Match", `"v1", W/"v0"`)
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for stale ETag, got %d", w.Code)
+	}
+	if w.Body.String() != "content" {
+		t.Fatalf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestContext_CheckNotModified_Post(t *testing.T) {
+	e := New()
+	e.POST("/items", func(c *Context) error {
+		return c.JSONWithETag(201, map[string]any{"id": 1})
+	})
+
+	post := func(match string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/items", nil)
+		if match != "" {
+			req.Header.Set("If-None-Match", match)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+		return w
+	}
+	etag := post("").Header().Get("ETag")
+
+	// A matching ETag doesn't turn a POST into a 304
+	for _, match := range []string{etag, "*"} {
+		w := post(match)
+		if w.Code != http.StatusCreated || strings.TrimSpace(w.Body.String()) != `{"id":1}` {
+			t.Errorf("If-None-Match %s: expected 201 with the body, got %d %q", match, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestContext_StreamJSONArray(t *testing.T) {
+	e := New()
+	e.GET("/items", func(c *Context) error {
+		ch := make(chan any)
+		go func() {
+			defer close(ch)
+			for i := range 3 {
+				ch <- map[string]int{"n": i}
+			}
+		}()
+		return c.StreamJSONArray(ch)
+	})
+	e.GET("/empty", func(c *Context) error {
+		ch := make(chan any)
+		close(ch)
+		return c.StreamJSONArray(ch)
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/items", nil))
+
+	var items []map[string]int
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("expected a valid JSON array, got %q: %v", w.Body.String(), err)
+	}
+	if len(items) != 3 || items[2]["n"] != 2 {
+		t.Fatalf("unexpected items %v", items)
+	}
+	if !w.Flushed {
+		t.Fatalf("expected elements to be flushed")
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/empty", nil))
+	if w.Body.String() != "[]" {
+		t.Fatalf("expected [] for empty channel, got %q", w.Body.String())
+	}
+}
+
+func TestContext_Blob(t *testing.T) {
+	pdf := []byte("%PDF-1.4\x00\x01binary")
+	e := New()
+	e.GET("/report", func(c *Context) error {
+		return c.Blob(200, "application/pdf", pdf)
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Fatalf("expected application/pdf, got %q", ct)
+	}
+	if cl := w.Header().Get("Content-Length"); cl != strconv.Itoa(len(pdf)) {
+		t.Fatalf("expected Content-Length %d, got %q", len(pdf), cl)
+	}
+	if !bytes.Equal(w.Body.Bytes(), pdf) {
+		t.Fatalf("unexpected body %q", w.Body.Bytes())
+	}
+}
+
+func TestContext_Stream(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10_000) // larger than one chunk
+	e := New()
+	e.GET("/download", func(c *Context) error {
+		return c.Stream(201, "application/octet-stream", bytes.NewReader(data))
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/download", nil))
+
+	if w.Code != 201 {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/octet-stream" {
+		t.Fatalf("expected application/octet-stream, got %q", ct)
+	}
+	if !bytes.Equal(w.Body.Bytes(), data) {
+		t.Fatalf("body mismatch: got %d bytes, want %d", w.Body.Len(), len(data))
+	}
+	if !w.Flushed {
+		t.Fatalf("expected the stream to be flushed")
+	}
+}
+
+func TestContext_JSONP(t *testing.T) {
+	e := New()
+	e.GET("/tools.js", func(c *Context) error {
+		return c.JSONP(200, c.Query("callback"), map[string]any{"count": 2})
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/tools.js?callback=widget.onTools", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/javascript") {
+		t.Fatalf("expected application/javascript, got %q", ct)
+	}
+	if w.Body.String() != `/**/widget.onTools({"count":2});` {
+		t.Fatalf("unexpected body %q", w.Body.String())
+	}
+
+	for _, cb := range []string{"alert(1);cb", "cb</script>", "", "1cb", "a..b", strings.Repeat("a", 129)} {
+		w = httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", "/tools.js?callback="+url.QueryEscape(cb), nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("callback %q: expected 400, got %d", cb, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "count") {
+			t.Errorf("callback %q: expected no payload, got %q", cb, w.Body.String())
+		}
+	}
+}
+
+func TestContext_ClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		remote  string
+		want    string
+	}{
+		{"remote addr", nil, "192.0.2.1:1234", "192.0.2.1"},
+		{"forwarded for", map[string]string{"X-Forwarded-For": "203.0.113.9, 10.0.0.1"}, "10.0.0.1:80", "203.0.113.9"},
+		{"real ip", map[string]string{"X-Real-IP": "198.51.100.7"}, "10.0.0.1:80", "198.51.100.7"},
+		{"ipv6 remote", nil, "[2001:db8::1]:443", "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			c := &Context{Request: req}
+			if got := c.ClientIP(); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext_ParamInt(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", func(c *Context) error {
+		id, err := c.ParamInt("id")
+		if err != nil {
+			return err
+		}
+		return c.String(200, strconv.Itoa(id*2))
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/users/21", nil))
+	if w.Code != 200 || w.Body.String() != "42" {
+		t.Errorf("expected 42, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/users/abc", nil))
+	if w.Code != 400 || !strings.Contains(w.Body.String(), `path parameter \"id\" must be an integer`) {
+		t.Errorf("expected a 400 naming the parameter, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestContext_ParamIntDefault(t *testing.T) {
+	c := &Context{params: map[string]string{"page": "3", "size": "big"}}
+	if got := c.ParamIntDefault("page", 1); got != 3 {
+		t.Errorf("expected 3, got %d", got)
+	}
+	if got := c.ParamIntDefault("size", 20); got != 20 {
+		t.Errorf("expected the default for a non-numeric param, got %d", got)
+	}
+	if got := c.ParamIntDefault("missing", 1); got != 1 {
+		t.Errorf("expected the default for a missing param, got %d", got)
+	}
+}
+
+func TestContext_ParamUUID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"123E4567-E89B-12D3-A456-426614174000", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"123e4567e89b12d3a456426614174000", "", false},
+		{"123e4567-e89b-12d3-a456-42661417400g", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		c := &Context{params: map[string]string{"id": tt.value}}
+		got, err := c.ParamUUID("id")
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParamUUID(%q) = %q, %v", tt.value, got, err)
+		}
+		var he *HTTPError
+		if !tt.ok && (!errors.As(err, &he) || he.Code != 400) {
+			t.Errorf("ParamUUID(%q): expected a 400 HTTPError, got %v", tt.value, err)
+		}
+	}
+}
+
+func TestContext_BindJSONStrict(t *testing.T) {
+	type request struct {
+		MaxTokens int `json:"max_tokens"`
+	}
+	body := `{"maxTokens": 100}`
+	newContext := func() *Context {
+		return &Context{Request: httptest.NewRequest("POST", "/", strings.NewReader(body))}
+	}
+
+	var lenient request
+	if err := newContext().BindJSON(&lenient); err != nil {
+		t.Fatalf("lenient: unexpected error %v", err)
+	}
+
+	var strict request
+	err := newContext().BindJSONStrict(&strict)
+	if err == nil || !strings.Contains(err.Error(), `"maxTokens"`) {
+		t.Fatalf("strict: expected an error naming maxTokens, got %v", err)
+	}
+
+	body = `{"max_tokens": 100}`
+	if err := newContext().BindJSONStrict(&strict); err != nil || strict.MaxTokens != 100 {
+		t.Fatalf("strict: expected known fields to bind, got %v, %+v", err, strict)
+	}
+}
+
+func TestEngine_StrictJSON(t *testing.T) {
+	handler := func(c *Context) error {
+		var v struct {
+			Name string `json:"name"`
+		}
+		if err := c.BindJSON(&v); err != nil {
+			return NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return c.String(200, v.Name)
+	}
+
+	for _, strict := range []bool{false, true} {
+		e := New()
+		if strict {
+			e.StrictJSON()
+		}
+		e.POST("/", handler)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"a","nmae":"b"}`)))
+
+		want := http.StatusOK
+		if strict {
+			want = http.StatusBadRequest
+		}
+		if w.Code != want {
+			t.Errorf("strict=%v: expected %d, got %d: %s", strict, want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestContext_BindJSONNumber(t *testing.T) {
+	body := `{"id": 12345678901234567, "ratio": 0.5}`
+	c := &Context{Request: httptest.NewRequest("POST", "/", strings.NewReader(body))}
+
+	var v map[string]any
+	if err := c.BindJSONNumber(&v); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := JSONInt64(v["id"]); !ok || id != 12345678901234567 {
+		t.Errorf("expected id 12345678901234567, got %v", v["id"])
+	}
+	if ratio, ok := JSONFloat64(v["ratio"]); !ok || ratio != 0.5 {
+		t.Errorf("expected ratio 0.5, got %v", v["ratio"])
+	}
+	if _, ok := JSONInt64(v["ratio"]); ok {
+		t.Error("expected a fraction not to read as int64")
+	}
+
+	out, _ := json.Marshal(v)
+	if !strings.Contains(string(out), `"id":12345678901234567`) {
+		t.Errorf("expected the id to round-trip, got %s", out)
+	}
+}
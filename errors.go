@@ -0,0 +1,57 @@
+package blaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error carrying the HTTP status and message to send to the
+// client. Handlers return it to produce a non-500 error response.
+type HTTPError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Detail  any    `json:"detail,omitempty"`
+}
+
+// NewHTTPError creates an HTTPError with the given status code and message.
+// An optional detail value is included in the JSON body.
+func NewHTTPError(code int, message string, detail ...any) *HTTPError {
+	e := &HTTPError{Code: code, Message: message}
+	if len(detail) > 0 {
+		e.Detail = detail[0]
+	}
+	return e
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// ErrorEnvelope builds the JSON body of error responses: those written by
+// Context.Error and Errorf, and HTTPErrors returned by handlers. The default,
+// DefaultErrorEnvelope, produces {"error": {"code": ..., "message": ...}}.
+// Replace it at startup to match another API's shape, e.g. with
+// adapter.AnthropicErrorEnvelope or adapter.OpenAIErrorEnvelope.
+var ErrorEnvelope = DefaultErrorEnvelope
+
+// DefaultErrorEnvelope wraps e as {"error": e}
+func DefaultErrorEnvelope(e *HTTPError) any {
+	return map[string]any{"error": e}
+}
+
+// writeError writes err to w: HTTPErrors as a JSON envelope with their own
+// status, anything else as a plain 500
+func writeError(w http.ResponseWriter, err error) {
+	var he *HTTPError
+	if !errors.As(err, &he) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(he.Code)
+	json.NewEncoder(w).Encode(ErrorEnvelope(he))
+}
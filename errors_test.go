@@ -0,0 +1,161 @@
+package blaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHTTPError_Response(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", func(c *Context) error {
+		return NewHTTPError(http.StatusNotFound, "user not found", map[string]string{"id": c.Param("id")})
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/users/42", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON content type, got %s", ct)
+	}
+
+	var body struct {
+		Error struct {
+			Code    int               `json:"code"`
+			Message string            `json:"message"`
+			Detail  map[string]string `json:"detail"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if body.Error.Code != 404 || body.Error.Message != "user not found" || body.Error.Detail["id"] != "42" {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestHTTPError_Wrapped(t *testing.T) {
+	e := New()
+	e.GET("/wrapped", func(c *Context) error {
+		return fmt.Errorf("lookup: %w", NewHTTPError(http.StatusConflict, "conflict"))
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/wrapped", nil))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
+
+func TestHTTPError_PlainErrorIs500(t *testing.T) {
+	e := New()
+	e.GET("/boom", func(c *Context) error {
+		return errors.New("database unavailable")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+}
+
+func TestEngine_OnError(t *testing.T) {
+	var got error
+	e := New()
+	e.OnError(func(c *Context, err error) {
+		got = err
+		c.JSON(http.StatusTeapot, map[string]string{"failure": err.Error()})
+	})
+	e.GET("/boom", func(c *Context) error {
+		return errors.New("kettle empty")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+
+	if got == nil || got.Error() != "kettle empty" {
+		t.Fatalf("expected hook to receive the handler error, got %v", got)
+	}
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("expected custom status 418, got %d", w.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["failure"] != "kettle empty" {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestEngine_OnError_NotCalledOnSuccess(t *testing.T) {
+	called := false
+	e := New()
+	e.OnError(func(c *Context, err error) { called = true })
+	e.GET("/ok", func(c *Context) error { return c.String(200, "ok") })
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+	if called {
+		t.Fatal("expected hook not to run for successful handlers")
+	}
+}
+
+func TestContext_Error(t *testing.T) {
+	e := New()
+	e.GET("/bad", func(c *Context) error { return c.Error(http.StatusBadRequest, "name is required") })
+	e.GET("/fail", func(c *Context) error {
+		return c.Errorf(http.StatusInternalServerError, "store %s unavailable", "users")
+	})
+
+	tests := []struct {
+		path    string
+		code    int
+		message string
+	}{
+		{"/bad", 400, "name is required"},
+		{"/fail", 500, "store users unavailable"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.code, w.Code)
+		}
+		var body map[string]map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid JSON body: %v", tt.path, err)
+		}
+		want := map[string]any{"code": float64(tt.code), "message": tt.message}
+		if len(body) != 1 || !reflect.DeepEqual(body["error"], want) {
+			t.Errorf("%s: unexpected body %s", tt.path, w.Body.String())
+		}
+	}
+}
+
+func TestErrorEnvelope_Custom(t *testing.T) {
+	defer func(prev func(*HTTPError) any) { ErrorEnvelope = prev }(ErrorEnvelope)
+	ErrorEnvelope = func(e *HTTPError) any {
+		return map[string]any{"type": "error", "message": e.Message}
+	}
+
+	e := New()
+	e.GET("/context", func(c *Context) error { return c.Error(http.StatusBadRequest, "bad") })
+	e.GET("/returned", func(c *Context) error { return NewHTTPError(http.StatusBadRequest, "bad") })
+
+	for _, path := range []string{"/context", "/returned"} {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if got := strings.TrimSpace(w.Body.String()); got != `{"message":"bad","type":"error"}` {
+			t.Errorf("%s: unexpected body %s", path, got)
+		}
+	}
+}
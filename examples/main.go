@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/dvictor357/blaze"
 	"github.com/dvictor357/blaze/adapter"
@@ -120,6 +122,11 @@ func main() {
 	// Returns tools in both OpenAI and Anthropic formats
 	engine.GET("/tools", adapter.ListToolsHandler(allTools...))
 
+	// Run tools directly (one or a batch), documented by an OpenAPI spec
+	engine.POST("/tools/batch", adapter.BatchExecHandler(allTools...))
+	engine.POST("/tools/:name", adapter.ExecHandler(allTools...))
+	engine.GET("/openapi.json", adapter.OpenAPIHandler(allTools...))
+
 	// Also add a simple health check endpoint
 	engine.GET("/", func(c *blaze.Context) error {
 		return c.JSON(200, map[string]string{
@@ -129,10 +136,30 @@ func main() {
 		})
 	})
 
+	// Liveness and readiness probes
+	engine.GET("/livez", blaze.LivenessHandler())
+	engine.GET("/readyz", blaze.HealthHandler(
+		blaze.NewHealthCheck("duckduckgo", func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, "HEAD", "https://html.duckduckgo.com/html/", nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}),
+	))
+
 	fmt.Println("🔥 Blaze AI Tool Server running on :8080")
 	fmt.Println("Endpoints:")
 	fmt.Println("  POST /chat   - Anthropic/Claude format")
 	fmt.Println("  POST /openai - OpenAI format")
 	fmt.Println("  GET  /tools  - List available tools")
+	fmt.Println("  POST /tools/:name - Execute a tool directly")
+	fmt.Println("  GET  /openapi.json - OpenAPI spec for tool endpoints")
+	fmt.Println("  GET  /livez  - Liveness probe")
+	fmt.Println("  GET  /readyz - Readiness probe (checks DuckDuckGo)")
 	engine.Listen(":8080")
 }
@@ -0,0 +1,125 @@
+package blaze
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthCheck is a named dependency check used by HealthHandler
+type HealthCheck struct {
+	Name    string
+	Check   func(context.Context) error
+	Timeout time.Duration // default: 5s
+}
+
+// NewHealthCheck creates a HealthCheck with the default timeout
+func NewHealthCheck(name string, check func(context.Context) error) HealthCheck {
+	return HealthCheck{Name: name, Check: check}
+}
+
+// CheckResult is the outcome of a single HealthCheck
+type CheckResult struct {
+	Status   string `json:"status"` // "ok" or "fail"
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// HealthResponse is the body returned by the health handlers
+type HealthResponse struct {
+	Status string                 `json:"status"` // "ok" or "unavailable"
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// LivenessHandler reports that the process is up. It runs no dependency
+// checks, so orchestrators don't restart the server when a dependency is down.
+func LivenessHandler() HandlerFunc {
+	return func(c *Context) error {
+		return c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+	}
+}
+
+// HealthHandler runs all checks concurrently and returns 200 when every check
+// passes, or 503 with per-check status when any fails. A check that panics
+// is reported as failed. Use it as a readiness endpoint. It panics if two
+// checks share a name.
+func HealthHandler(checks ...HealthCheck) HandlerFunc {
+	names := make(map[string]bool, len(checks))
+	for _, hc := range checks {
+		if names[hc.Name] {
+			panic(fmt.Sprintf("blaze: duplicate health check name %q", hc.Name))
+		}
+		names[hc.Name] = true
+	}
+
+	return func(c *Context) error {
+		results := make(map[string]CheckResult, len(checks))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+
+		for _, hc := range checks {
+			wg.Add(1)
+			go func(hc HealthCheck) {
+				defer wg.Done()
+				result := runHealthCheck(c.Request.Context(), hc)
+				mu.Lock()
+				results[hc.Name] = result
+				mu.Unlock()
+			}(hc)
+		}
+		wg.Wait()
+
+		resp := HealthResponse{Status: "ok", Checks: results}
+		code := http.StatusOK
+		for _, r := range results {
+			if r.Status != "ok" {
+				resp.Status = "unavailable"
+				code = http.StatusServiceUnavailable
+				break
+			}
+		}
+		return c.JSON(code, resp)
+	}
+}
+
+// ReadinessHandler is an alias for HealthHandler
+func ReadinessHandler(checks ...HealthCheck) HandlerFunc {
+	return HealthHandler(checks...)
+}
+
+// runHealthCheck runs hc with its timeout
+func runHealthCheck(parent context.Context, hc HealthCheck) CheckResult {
+	timeout := hc.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- hc.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{Status: "ok", Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+	return result
+}
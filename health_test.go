@@ -0,0 +1,105 @@
+package blaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getHealth(t *testing.T, h HandlerFunc) (int, HealthResponse) {
+	t.Helper()
+	e := New()
+	e.GET("/health", h)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+	var resp HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	return w.Code, resp
+}
+
+func TestHealthHandler_AllPassing(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	code, resp := getHealth(t, HealthHandler(
+		NewHealthCheck("memory", ok),
+		NewHealthCheck("duckduckgo", ok),
+	))
+
+	if code != http.StatusOK || resp.Status != "ok" {
+		t.Fatalf("expected 200/ok, got %d/%s", code, resp.Status)
+	}
+	if len(resp.Checks) != 2 || resp.Checks["duckduckgo"].Status != "ok" {
+		t.Fatalf("unexpected checks: %+v", resp.Checks)
+	}
+}
+
+func TestHealthHandler_OneFailing(t *testing.T) {
+	code, resp := getHealth(t, HealthHandler(
+		NewHealthCheck("memory", func(ctx context.Context) error { return nil }),
+		NewHealthCheck("duckduckgo", func(ctx context.Context) error { return errors.New("dial timeout") }),
+	))
+
+	if code != http.StatusServiceUnavailable || resp.Status != "unavailable" {
+		t.Fatalf("expected 503/unavailable, got %d/%s", code, resp.Status)
+	}
+	if resp.Checks["memory"].Status != "ok" {
+		t.Fatalf("expected passing check to be reported ok")
+	}
+	if got := resp.Checks["duckduckgo"]; got.Status != "fail" || got.Error != "dial timeout" {
+		t.Fatalf("unexpected failing check result: %+v", got)
+	}
+}
+
+func TestHealthHandler_Timeout(t *testing.T) {
+	slow := HealthCheck{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	code, resp := getHealth(t, HealthHandler(slow))
+
+	if code != http.StatusServiceUnavailable || resp.Checks["slow"].Status != "fail" {
+		t.Fatalf("expected timed out check to fail, got %d %+v", code, resp.Checks)
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	code, resp := getHealth(t, LivenessHandler())
+	if code != http.StatusOK || resp.Status != "ok" || len(resp.Checks) != 0 {
+		t.Fatalf("expected bare 200/ok, got %d %+v", code, resp)
+	}
+}
+
+func TestHealthHandler_PanickingCheck(t *testing.T) {
+	code, resp := getHealth(t, HealthHandler(
+		NewHealthCheck("memory", func(ctx context.Context) error { return nil }),
+		NewHealthCheck("broken", func(ctx context.Context) error { panic("nil pool") }),
+	))
+
+	if code != http.StatusServiceUnavailable || resp.Status != "unavailable" {
+		t.Fatalf("expected 503/unavailable, got %d/%s", code, resp.Status)
+	}
+	if got := resp.Checks["broken"]; got.Status != "fail" || got.Error != "check panicked: nil pool" {
+		t.Fatalf("unexpected panicking check result: %+v", got)
+	}
+}
+
+func TestHealthHandler_DuplicateNames(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate check names to panic")
+		}
+	}()
+	ok := func(ctx context.Context) error { return nil }
+	HealthHandler(NewHealthCheck("db", ok), NewHealthCheck("db", ok))
+}
@@ -0,0 +1,80 @@
+// Package docfmt decodes and encodes JSON, YAML and TOML documents as plain
+// values: map[string]any, []any, string, int64, float64, bool and nil. It
+// backs the format_convert tool and the YAML output of the tool listing.
+package docfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseJSON decodes JSON keeping whole numbers as int64. Syntax errors
+// report the line they occur on.
+func ParseJSON(src string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+	var doc any
+	err := dec.Decode(&doc)
+	if err == nil && dec.More() {
+		err = errors.New("unexpected content after the document")
+	}
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset := int(syntaxErr.Offset)
+			line := strings.Count(src[:offset], "\n") + 1
+			text := src[strings.LastIndexByte(src[:offset], '\n')+1:]
+			text, _, _ = strings.Cut(text, "\n")
+			return nil, fmt.Errorf("line %d: %v: %q", line, err, text)
+		}
+		return nil, err
+	}
+	return jsonNumbers(doc), nil
+}
+
+// jsonNumbers replaces the json.Numbers in v with int64 for whole numbers
+// and float64 otherwise
+func jsonNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, item := range v {
+			v[k] = jsonNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = jsonNumbers(item)
+		}
+	}
+	return v
+}
+
+// FormatJSON encodes v as indented JSON
+func FormatJSON(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("cannot encode as JSON: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Normalize converts any JSON-encodable value, such as a struct, to the
+// plain values the encoders accept
+func Normalize(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSON(string(b))
+}
@@ -0,0 +1,627 @@
+package docfmt
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// This file implements TOML 1.0 decoding and encoding for format conversion.
+// Dates and times have no JSON or YAML equivalent and are kept as strings.
+
+// tomlParser decodes a TOML document
+type tomlParser struct {
+	src     string
+	i       int
+	root    map[string]any
+	table   map[string]any  // table that key/value pairs are added to
+	headers map[string]bool // [table] headers already seen, by dotted path
+}
+
+// ParseTOML decodes a TOML document into maps, slices, strings, int64,
+// float64 and bools
+func ParseTOML(src string) (map[string]any, error) {
+	p := &tomlParser{src: strings.ReplaceAll(src, "\r\n", "\n"), root: map[string]any{}, headers: map[string]bool{}}
+	p.table = p.root
+
+	for {
+		p.skipBlank()
+		if p.i >= len(p.src) {
+			return p.root, nil
+		}
+		var err error
+		if p.src[p.i] == '[' {
+			err = p.parseHeader()
+		} else {
+			err = p.parseKeyValue(p.table)
+		}
+		if err == nil {
+			err = p.endOfLine()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// errorf returns an error pointing at the line being parsed
+func (p *tomlParser) errorf(format string, args ...any) error {
+	pos := min(p.i, len(p.src))
+	start := strings.LastIndexByte(p.src[:pos], '\n') + 1
+	end := strings.IndexByte(p.src[pos:], '\n')
+	if end < 0 {
+		end = len(p.src)
+	} else {
+		end += pos
+	}
+	line := strings.Count(p.src[:pos], "\n") + 1
+	return fmt.Errorf("line %d: %s: %q", line, fmt.Sprintf(format, args...), p.src[start:end])
+}
+
+// space skips spaces and tabs
+func (p *tomlParser) space() {
+	for p.i < len(p.src) && (p.src[p.i] == ' ' || p.src[p.i] == '\t') {
+		p.i++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments
+func (p *tomlParser) skipBlank() {
+	for p.i < len(p.src) {
+		switch p.src[p.i] {
+		case ' ', '\t', '\n':
+			p.i++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// skipComment skips a comment up to the end of the line
+func (p *tomlParser) skipComment() {
+	if end := strings.IndexByte(p.src[p.i:], '\n'); end >= 0 {
+		p.i += end
+	} else {
+		p.i = len(p.src)
+	}
+}
+
+// endOfLine checks that only a comment follows on the current line
+func (p *tomlParser) endOfLine() error {
+	p.space()
+	if p.i < len(p.src) && p.src[p.i] == '#' {
+		p.skipComment()
+	}
+	if p.i < len(p.src) && p.src[p.i] != '\n' {
+		return p.errorf("unexpected %q", p.src[p.i:p.i+1])
+	}
+	return nil
+}
+
+// parseHeader parses a [table] or [[array of tables]] header and makes it
+// the current table
+func (p *tomlParser) parseHeader() error {
+	array := strings.HasPrefix(p.src[p.i:], "[[")
+	if array {
+		p.i += 2
+	} else {
+		p.i++
+	}
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	closer := "]"
+	if array {
+		closer = "]]"
+	}
+	p.space()
+	if !strings.HasPrefix(p.src[p.i:], closer) {
+		return p.errorf("expected '%s'", closer)
+	}
+	p.i += len(closer)
+
+	parent, err := p.descend(p.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+
+	if array {
+		list, ok := parent[last].([]any)
+		if parent[last] != nil && !ok {
+			return p.errorf("key %q is not an array of tables", last)
+		}
+		p.table = map[string]any{}
+		parent[last] = append(list, p.table)
+		return nil
+	}
+
+	path := strings.Join(keys, "\x00")
+	if p.headers[path] {
+		return p.errorf("table %q is defined twice", strings.Join(keys, "."))
+	}
+	p.headers[path] = true
+	p.table, err = p.descend(parent, []string{last})
+	return err
+}
+
+// descend walks keys down from m, creating missing tables. A key holding an
+// array of tables refers to its last table.
+func (p *tomlParser) descend(m map[string]any, keys []string) (map[string]any, error) {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case nil:
+			next := map[string]any{}
+			m[k] = next
+			m = next
+		case map[string]any:
+			m = v
+		case []any:
+			var next map[string]any
+			if len(v) > 0 {
+				next, _ = v[len(v)-1].(map[string]any)
+			}
+			if next == nil {
+				return nil, p.errorf("key %q is not a table", k)
+			}
+			m = next
+		default:
+			return nil, p.errorf("key %q is not a table", k)
+		}
+	}
+	return m, nil
+}
+
+// parseKeyValue parses a "key = value" pair into table
+func (p *tomlParser) parseKeyValue(table map[string]any) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.space()
+	if p.i >= len(p.src) || p.src[p.i] != '=' {
+		return p.errorf("expected '=' after key")
+	}
+	p.i++
+	p.space()
+
+	val, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := p.descend(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, dup := parent[last]; dup {
+		return p.errorf("duplicate key %q", last)
+	}
+	parent[last] = val
+	return nil
+}
+
+// parseKey parses a bare, quoted or dotted key
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.space()
+		if p.i >= len(p.src) {
+			return nil, p.errorf("expected key")
+		}
+		switch p.src[p.i] {
+		case '"', '\'':
+			k, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, k)
+		default:
+			start := p.i
+			for p.i < len(p.src) && isTOMLBareKeyChar(p.src[p.i]) {
+				p.i++
+			}
+			if p.i == start {
+				return nil, p.errorf("expected key")
+			}
+			keys = append(keys, p.src[start:p.i])
+		}
+		p.space()
+		if p.i >= len(p.src) || p.src[p.i] != '.' {
+			return keys, nil
+		}
+		p.i++
+	}
+}
+
+// isTOMLBareKeyChar reports whether c may appear in a bare key
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseValue parses a string, number, boolean, date, array or inline table
+func (p *tomlParser) parseValue() (any, error) {
+	if p.i >= len(p.src) {
+		return nil, p.errorf("expected value")
+	}
+	switch p.src[p.i] {
+	case '"', '\'':
+		return p.parseString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.i
+	for p.i < len(p.src) && !strings.ContainsRune(" \t\n,]}#", rune(p.src[p.i])) {
+		p.i++
+	}
+	tok := p.src[start:p.i]
+	if tok == "" {
+		return nil, p.errorf("expected value")
+	}
+	// A date and time may be separated by a space
+	if isTOMLDate(tok) && p.i+3 < len(p.src) && p.src[p.i] == ' ' && isDigit(p.src[p.i+1]) && isDigit(p.src[p.i+2]) && p.src[p.i+3] == ':' {
+		p.i++
+		for p.i < len(p.src) && !strings.ContainsRune(" \t\n,]}#", rune(p.src[p.i])) {
+			p.i++
+		}
+		tok = p.src[start:p.i]
+	}
+
+	switch {
+	case tok == "true":
+		return true, nil
+	case tok == "false":
+		return false, nil
+	case tok == "inf" || tok == "+inf":
+		return math.Inf(1), nil
+	case tok == "-inf":
+		return math.Inf(-1), nil
+	case tok == "nan" || tok == "+nan" || tok == "-nan":
+		return math.NaN(), nil
+	case isTOMLDate(tok) || len(tok) >= 8 && tok[2] == ':' && tok[5] == ':':
+		return tok, nil
+	}
+
+	num := strings.ReplaceAll(tok, "_", "")
+	if len(num) > 2 && num[0] == '0' && strings.ContainsRune("xob", rune(num[1])) {
+		if n, err := strconv.ParseInt(num, 0, 64); err == nil {
+			return n, nil
+		}
+	} else if !strings.ContainsAny(num, ".eE") {
+		if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+			return n, nil
+		}
+	} else if f, err := strconv.ParseFloat(num, 64); err == nil && !strings.ContainsAny(num, "xXpP") {
+		return f, nil
+	}
+	p.i = start
+	return nil, p.errorf("invalid value %q", tok)
+}
+
+// isTOMLDate reports whether s starts with a YYYY-MM-DD date
+func isTOMLDate(s string) bool {
+	return len(s) >= 10 && s[4] == '-' && s[7] == '-' && isDigit(s[0]) && isDigit(s[5]) && isDigit(s[8])
+}
+
+// parseString parses a basic, literal or multi-line string
+func (p *tomlParser) parseString() (string, error) {
+	q := p.src[p.i]
+	delim := string(q)
+	if strings.HasPrefix(p.src[p.i:], strings.Repeat(delim, 3)) {
+		delim = strings.Repeat(delim, 3)
+		p.i += 3
+		// A newline right after the opening delimiter is trimmed
+		if p.i < len(p.src) && p.src[p.i] == '\n' {
+			p.i++
+		}
+	} else {
+		p.i++
+	}
+	multiline := len(delim) == 3
+
+	var b strings.Builder
+	for p.i < len(p.src) {
+		if strings.HasPrefix(p.src[p.i:], delim) {
+			p.i += len(delim)
+			// Up to two quotes may directly precede a closing """
+			for n := 0; multiline && n < 2 && p.i < len(p.src) && p.src[p.i] == q; n++ {
+				b.WriteByte(q)
+				p.i++
+			}
+			return b.String(), nil
+		}
+
+		c := p.src[p.i]
+		switch {
+		case c == '\n' && !multiline:
+			return "", p.errorf("unterminated string")
+		case c == '\\' && q == '"':
+			p.i++
+			if err := p.parseEscape(&b, multiline); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.i++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// tomlEscapes maps the single-character escapes of basic strings
+var tomlEscapes = map[byte]byte{'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', '"': '"', '\\': '\\'}
+
+// parseEscape decodes the escape sequence after a backslash
+func (p *tomlParser) parseEscape(b *strings.Builder, multiline bool) error {
+	if p.i >= len(p.src) {
+		return p.errorf("unterminated string")
+	}
+	c := p.src[p.i]
+	p.i++
+	if r, ok := tomlEscapes[c]; ok {
+		b.WriteByte(r)
+		return nil
+	}
+
+	// In multi-line strings a backslash at the end of a line joins it
+	// with the next non-blank text
+	if multiline && (c == ' ' || c == '\t' || c == '\n') {
+		rest := strings.TrimLeft(p.src[p.i-1:], " \t")
+		if !strings.HasPrefix(rest, "\n") {
+			return p.errorf("invalid escape")
+		}
+		p.i = len(p.src) - len(strings.TrimLeft(rest, " \t\n"))
+		return nil
+	}
+
+	size := map[byte]int{'u': 4, 'U': 8}[c]
+	if size == 0 || p.i+size > len(p.src) {
+		return p.errorf("invalid escape '\\%c'", c)
+	}
+	n, err := strconv.ParseUint(p.src[p.i:p.i+size], 16, 32)
+	if err != nil {
+		return p.errorf("invalid escape '\\%c%s'", c, p.src[p.i:p.i+size])
+	}
+	p.i += size
+	b.WriteRune(rune(n))
+	return nil
+}
+
+// parseArray parses an array, which may span lines
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.i++
+	items := []any{}
+	for {
+		p.skipBlank()
+		if p.i >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.src[p.i] == ']' {
+			p.i++
+			return items, nil
+		}
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		p.skipBlank()
+		switch {
+		case p.i >= len(p.src):
+			return nil, p.errorf("unterminated array")
+		case p.src[p.i] == ',':
+			p.i++
+		case p.src[p.i] != ']':
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// parseInlineTable parses an inline table such as {x = 1, y = 2}
+func (p *tomlParser) parseInlineTable() (map[string]any, error) {
+	p.i++
+	table := map[string]any{}
+	p.space()
+	if p.i < len(p.src) && p.src[p.i] == '}' {
+		p.i++
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.space()
+		switch {
+		case p.i >= len(p.src):
+			return nil, p.errorf("unterminated inline table")
+		case p.src[p.i] == '}':
+			p.i++
+			return table, nil
+		case p.src[p.i] != ',':
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+		p.i++
+	}
+}
+
+// FormatTOML encodes a table as a TOML document with sorted keys. Nested
+// objects become [table] sections and arrays of objects [[array]] sections.
+func FormatTOML(v any) (string, error) {
+	root, ok := v.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("TOML documents must be an object at the top level")
+	}
+	var b strings.Builder
+	if err := writeTOMLTable(&b, root, nil); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(b.String(), "\n"), nil
+}
+
+// writeTOMLTable writes the keys of table, then its sub-tables, then its
+// arrays of tables, so that each key lands in the right section
+func writeTOMLTable(b *strings.Builder, table map[string]any, path []string) error {
+	keys := slices.Sorted(maps.Keys(table))
+	var tables, arrays []string
+	for _, k := range keys {
+		switch v := table[k].(type) {
+		case map[string]any:
+			tables = append(tables, k)
+			continue
+		case []any:
+			if isTOMLTableArray(v) {
+				arrays = append(arrays, k)
+				continue
+			}
+		}
+		s, err := tomlValue(table[k], append(slices.Clip(path), k))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "%s = %s\n", tomlKey(k), s)
+	}
+
+	for _, k := range tables {
+		sub := append(slices.Clip(path), k)
+		fmt.Fprintf(b, "\n[%s]\n", tomlPath(sub))
+		if err := writeTOMLTable(b, table[k].(map[string]any), sub); err != nil {
+			return err
+		}
+	}
+	for _, k := range arrays {
+		sub := append(slices.Clip(path), k)
+		for _, item := range table[k].([]any) {
+			fmt.Fprintf(b, "\n[[%s]]\n", tomlPath(sub))
+			if err := writeTOMLTable(b, item.(map[string]any), sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isTOMLTableArray reports whether list is non-empty and holds only objects
+func isTOMLTableArray(list []any) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]any); !ok {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+// tomlValue returns v as an inline TOML value. path locates v for errors.
+func tomlValue(v any, path []string) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", fmt.Errorf("TOML has no null value (at %s)", tomlPath(path))
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "inf", nil
+		case math.IsInf(v, -1):
+			return "-inf", nil
+		case math.IsNaN(v):
+			return "nan", nil
+		}
+		return floatLiteral(v), nil
+	case string:
+		return tomlString(v), nil
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			s, err := tomlValue(item, append(slices.Clip(path), strconv.Itoa(i)))
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}", nil
+		}
+		var parts []string
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			s, err := tomlValue(v[k], append(slices.Clip(path), k))
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, tomlKey(k)+" = "+s)
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	}
+	return "", fmt.Errorf("cannot encode %T as TOML", v)
+}
+
+// tomlPath joins keys into a dotted key
+func tomlPath(keys []string) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = tomlKey(k)
+	}
+	return strings.Join(parts, ".")
+}
+
+// tomlKey returns k bare when possible and quoted otherwise
+func tomlKey(k string) string {
+	for i := 0; i < len(k); i++ {
+		if !isTOMLBareKeyChar(k[i]) {
+			return tomlString(k)
+		}
+	}
+	if k == "" {
+		return `""`
+	}
+	return k
+}
+
+// tomlString returns s as a basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,732 @@
+package docfmt
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the subset of YAML found in configuration files:
+// block mappings and sequences, flow collections ([a, b], {k: v}), plain and
+// quoted scalars, literal (|) and folded (>) block scalars, and comments.
+// Anchors, aliases, tags, complex keys and multiple documents are rejected.
+
+// yamlLine is one line of a YAML document
+type yamlLine struct {
+	num    int    // 1-based line number
+	indent int    // leading spaces
+	text   string // content after the indent, comments included
+}
+
+// yamlParser parses a YAML document line by line
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// ParseYAML decodes a YAML document into maps, slices, strings, int64,
+// float64, bools and nil
+func ParseYAML(src string) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		text := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(text)
+		if strings.HasPrefix(text, "\t") && strings.TrimSpace(text) != "" {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: indent, text: strings.TrimRight(text, " \t")})
+	}
+
+	p.skip()
+	if !p.eof() && yamlContent(p.cur().text) == "---" {
+		p.pos++
+		p.skip()
+	}
+	if p.eof() {
+		return nil, nil
+	}
+
+	v, err := p.parseBlock(p.cur().indent)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skip()
+	if !p.eof() {
+		switch yamlContent(p.cur().text) {
+		case "...":
+		case "---":
+			return nil, p.errorf("multiple documents are not supported")
+		default:
+			return nil, p.errorf("unexpected content")
+		}
+	}
+	return v, nil
+}
+
+// cur returns the current line
+func (p *yamlParser) cur() yamlLine {
+	return p.lines[p.pos]
+}
+
+// eof reports whether all lines have been consumed
+func (p *yamlParser) eof() bool {
+	return p.pos >= len(p.lines)
+}
+
+// skip advances past blank and comment-only lines
+func (p *yamlParser) skip() {
+	for !p.eof() && yamlContent(p.cur().text) == "" {
+		p.pos++
+	}
+}
+
+// errorf returns an error pointing at the current line
+func (p *yamlParser) errorf(format string, args ...any) error {
+	if p.eof() {
+		return fmt.Errorf("line %d: %s", len(p.lines), fmt.Sprintf(format, args...))
+	}
+	line := p.cur()
+	return fmt.Errorf("line %d: %s: %q", line.num, fmt.Sprintf(format, args...), strings.Repeat(" ", line.indent)+line.text)
+}
+
+// parseBlock parses the node starting at the current line, which is
+// indented by indent
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	text := yamlContent(p.cur().text)
+	if isYAMLSeqItem(text) {
+		return p.parseSeq(indent)
+	}
+	if _, _, ok := splitYAMLKey(text); ok {
+		return p.parseMap(indent)
+	}
+	if text[0] == '|' || text[0] == '>' {
+		return p.parseBlockScalar(text, indent-1)
+	}
+	return p.parseValue(text)
+}
+
+// parseSeq parses a block sequence whose dashes are at indent
+func (p *yamlParser) parseSeq(indent int) ([]any, error) {
+	items := []any{}
+	for p.skip(); !p.eof(); p.skip() {
+		line := p.cur()
+		if line.indent < indent || !isYAMLSeqItem(yamlContent(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("bad indentation")
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if yamlContent(rest) == "" {
+			p.pos++
+			p.skip()
+			if p.eof() || p.cur().indent <= indent {
+				items = append(items, nil)
+				continue
+			}
+			item, err := p.parseBlock(p.cur().indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		// "- key: value" starts a mapping indented to where its key begins
+		itemIndent := indent + len(line.text) - len(rest)
+		p.lines[p.pos] = yamlLine{num: line.num, indent: itemIndent, text: rest}
+		item, err := p.parseBlock(itemIndent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseMap parses a block mapping whose keys are at indent
+func (p *yamlParser) parseMap(indent int) (map[string]any, error) {
+	m := map[string]any{}
+	for p.skip(); !p.eof(); p.skip() {
+		line := p.cur()
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("bad indentation")
+		}
+		key, rest, ok := splitYAMLKey(yamlContent(line.text))
+		if !ok {
+			return nil, p.errorf("expected 'key: value'")
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+
+		var val any
+		var err error
+		switch {
+		case rest == "":
+			p.pos++
+			p.skip()
+			if !p.eof() && (p.cur().indent > indent || p.cur().indent == indent && isYAMLSeqItem(yamlContent(p.cur().text))) {
+				val, err = p.parseBlock(p.cur().indent)
+			}
+		case rest[0] == '|' || rest[0] == '>':
+			val, err = p.parseBlockScalar(rest, indent)
+		default:
+			val, err = p.parseValue(rest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = val
+	}
+	return m, nil
+}
+
+// parseValue parses an inline scalar or flow collection that starts on the
+// current line. Flow collections may continue on the following lines.
+func (p *yamlParser) parseValue(text string) (any, error) {
+	start := p.pos
+	if text[0] == '[' || text[0] == '{' {
+		for !yamlFlowClosed(text) && p.pos+1 < len(p.lines) {
+			p.pos++
+			text += " " + yamlContent(p.lines[p.pos].text)
+		}
+	}
+
+	f := &yamlFlow{src: text}
+	v, err := f.value(false)
+	if err == nil {
+		f.space()
+		if f.i < len(f.src) {
+			err = fmt.Errorf("unexpected %q after value", f.src[f.i:])
+		}
+	}
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("%v", err)
+	}
+	p.pos++
+	return v, nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar whose
+// header is on the current line and whose content is indented past parent
+func (p *yamlParser) parseBlockScalar(header string, parent int) (string, error) {
+	folded := header[0] == '>'
+	var chomp byte
+	indent := -1
+	for _, c := range []byte(header[1:]) {
+		switch {
+		case c == '-' || c == '+':
+			chomp = c
+		case c >= '1' && c <= '9':
+			indent = parent + int(c-'0')
+		default:
+			return "", p.errorf("invalid block scalar header")
+		}
+	}
+	p.pos++
+
+	var lines []string
+	for ; !p.eof(); p.pos++ {
+		line := p.cur()
+		if line.text == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if indent < 0 {
+			indent = line.indent
+		}
+		if line.indent < indent || line.indent <= parent {
+			break
+		}
+		lines = append(lines, strings.Repeat(" ", line.indent-indent)+line.text)
+	}
+
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case !folded || line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(prev, " "):
+				b.WriteByte('\n')
+			case prev != "":
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(line)
+	}
+
+	switch {
+	case b.Len() == 0 || chomp == '-':
+	case chomp == '+':
+		b.WriteString(strings.Repeat("\n", trailing+1))
+	default:
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// yamlContent returns text without its trailing comment
+func yamlContent(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote == '\'' && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return strings.TrimRight(text[:i], " \t")
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[{,", text[i-1]) >= 0):
+			quote = c
+		}
+	}
+	return strings.TrimRight(text, " \t")
+}
+
+// isYAMLSeqItem reports whether text is a block sequence entry
+func isYAMLSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits a "key: value" line into its key and value
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		f := &yamlFlow{src: text}
+		key, err := f.quoted()
+		if err != nil {
+			return "", "", false
+		}
+		after := strings.TrimLeft(text[f.i:], " \t")
+		if !strings.HasPrefix(after, ":") || len(after) > 1 && after[1] != ' ' && after[1] != '\t' {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(after[1:]), true
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\t') {
+			return strings.TrimRight(text[:i], " \t"), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// yamlFlowClosed reports whether every bracket opened in text is closed
+func yamlFlowClosed(text string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// yamlFlow scans an inline YAML value
+type yamlFlow struct {
+	src string
+	i   int
+}
+
+// space skips spaces and tabs
+func (f *yamlFlow) space() {
+	for f.i < len(f.src) && (f.src[f.i] == ' ' || f.src[f.i] == '\t') {
+		f.i++
+	}
+}
+
+// value parses a scalar or flow collection. Inside a collection (inFlow),
+// plain scalars end at ',', ':' and brackets.
+func (f *yamlFlow) value(inFlow bool) (any, error) {
+	f.space()
+	if f.i >= len(f.src) {
+		return nil, nil
+	}
+	switch f.src[f.i] {
+	case '[':
+		return f.list()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		return f.quoted()
+	case '&', '*', '!':
+		return nil, fmt.Errorf("anchors, aliases and tags are not supported")
+	}
+	plain := f.plain(inFlow)
+	if !inFlow && (strings.Contains(plain, ": ") || strings.Contains(plain, ":\t") || strings.HasSuffix(plain, ":")) {
+		return nil, fmt.Errorf("unexpected ':' in plain value")
+	}
+	return resolveYAMLScalar(plain), nil
+}
+
+// plain scans a plain scalar
+func (f *yamlFlow) plain(inFlow bool) string {
+	start := f.i
+	for ; f.i < len(f.src); f.i++ {
+		c := f.src[f.i]
+		if inFlow && strings.IndexByte(",[]{}", c) >= 0 {
+			break
+		}
+		if inFlow && c == ':' && (f.i+1 == len(f.src) || strings.IndexByte(" \t,]}", f.src[f.i+1]) >= 0) {
+			break
+		}
+	}
+	return strings.TrimSpace(f.src[start:f.i])
+}
+
+// list parses a flow sequence such as [a, b]
+func (f *yamlFlow) list() ([]any, error) {
+	f.i++
+	items := []any{}
+	for {
+		f.space()
+		if f.i >= len(f.src) {
+			return nil, fmt.Errorf("unterminated '['")
+		}
+		if f.src[f.i] == ']' {
+			f.i++
+			return items, nil
+		}
+		item, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// mapping parses a flow mapping such as {a: 1, b: 2}
+func (f *yamlFlow) mapping() (map[string]any, error) {
+	f.i++
+	m := map[string]any{}
+	for {
+		f.space()
+		if f.i >= len(f.src) {
+			return nil, fmt.Errorf("unterminated '{'")
+		}
+		if f.src[f.i] == '}' {
+			f.i++
+			return m, nil
+		}
+
+		var key string
+		if c := f.src[f.i]; c == '"' || c == '\'' {
+			k, err := f.quoted()
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		} else {
+			key = f.plain(true)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+
+		f.space()
+		var val any
+		if f.i < len(f.src) && f.src[f.i] == ':' {
+			f.i++
+			v, err := f.value(true)
+			if err != nil {
+				return nil, err
+			}
+			val = v
+		}
+		m[key] = val
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the ',' after a flow entry, or checks that the
+// collection ends with closer
+func (f *yamlFlow) separator(closer byte) error {
+	f.space()
+	switch {
+	case f.i >= len(f.src):
+		return fmt.Errorf("unterminated '%c'", map[byte]byte{']': '[', '}': '{'}[closer])
+	case f.src[f.i] == ',':
+		f.i++
+	case f.src[f.i] != closer:
+		return fmt.Errorf("expected ',' or '%c', got %q", closer, f.src[f.i:])
+	}
+	return nil
+}
+
+// quoted parses a single- or double-quoted scalar
+func (f *yamlFlow) quoted() (string, error) {
+	q := f.src[f.i]
+	f.i++
+	var b strings.Builder
+	for f.i < len(f.src) {
+		c := f.src[f.i]
+		f.i++
+		switch {
+		case c == '\'' && q == '\'' && f.i < len(f.src) && f.src[f.i] == '\'':
+			b.WriteByte('\'')
+			f.i++
+		case c == q:
+			return b.String(), nil
+		case c == '\\' && q == '"':
+			if err := f.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted string")
+}
+
+// yamlEscapes maps the single-character escapes of double-quoted scalars
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f",
+	'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// escape decodes the escape sequence after a backslash
+func (f *yamlFlow) escape(b *strings.Builder) error {
+	if f.i >= len(f.src) {
+		return fmt.Errorf("unterminated quoted string")
+	}
+	c := f.src[f.i]
+	f.i++
+	if s, ok := yamlEscapes[c]; ok {
+		b.WriteString(s)
+		return nil
+	}
+
+	size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+	if size == 0 || f.i+size > len(f.src) {
+		return fmt.Errorf("invalid escape '\\%c'", c)
+	}
+	n, err := strconv.ParseUint(f.src[f.i:f.i+size], 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid escape '\\%c%s'", c, f.src[f.i:f.i+size])
+	}
+	f.i += size
+	b.WriteRune(rune(n))
+	return nil
+}
+
+// resolveYAMLScalar types a plain scalar using the YAML 1.2 core schema
+func resolveYAMLScalar(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+
+	if unsigned := strings.TrimLeft(s, "+-"); len(s)-len(unsigned) <= 1 && strings.ContainsAny(unsigned, "0123456789") {
+		if strings.Trim(unsigned, "0123456789") == "" {
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return n
+			}
+		}
+		if strings.Trim(unsigned, "0123456789.eE+-") == "" {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		}
+	}
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'o') && strings.Trim(s[2:], "0123456789abcdefABCDEF") == "" {
+		base := 16
+		if s[1] == 'o' {
+			base = 8
+		}
+		if n, err := strconv.ParseInt(s[2:], base, 64); err == nil {
+			return n
+		}
+	}
+	return s
+}
+
+// FormatYAML encodes v as a block-style YAML document with sorted keys
+func FormatYAML(v any) (string, error) {
+	if !isYAMLBlock(v) {
+		s, err := yamlScalar(v)
+		if err != nil {
+			return "", err
+		}
+		return s + "\n", nil
+	}
+	lines, err := yamlBlockLines(v, 0)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// isYAMLBlock reports whether v is written as an indented block, i.e. it is
+// a non-empty mapping or sequence
+func isYAMLBlock(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		return len(v) > 0
+	case []any:
+		return len(v) > 0
+	}
+	return false
+}
+
+// yamlBlockLines returns the lines of a block mapping or sequence, each
+// indented by indent spaces
+func yamlBlockLines(v any, indent int) ([]string, error) {
+	pad := strings.Repeat(" ", indent)
+	var lines []string
+	add := func(prefix string, item any) error {
+		if isYAMLBlock(item) {
+			sub, err := yamlBlockLines(item, indent+2)
+			if err != nil {
+				return err
+			}
+			if prefix == "- " {
+				// The first entry of a nested block shares the dash's line
+				sub[0] = pad + prefix + sub[0][indent+2:]
+				lines = append(lines, sub...)
+			} else {
+				lines = append(append(lines, pad+strings.TrimSpace(prefix)), sub...)
+			}
+			return nil
+		}
+		s, err := yamlScalar(item)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, pad+prefix+s)
+		return nil
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if err := add(yamlString(k)+": ", v[k]); err != nil {
+				return nil, err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := add("- ", item); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return lines, nil
+}
+
+// yamlScalar returns v as an inline YAML value
+func yamlScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return ".inf", nil
+		case math.IsInf(v, -1):
+			return "-.inf", nil
+		case math.IsNaN(v):
+			return ".nan", nil
+		}
+		return floatLiteral(v), nil
+	case string:
+		return yamlString(v), nil
+	case map[string]any:
+		return "{}", nil
+	case []any:
+		return "[]", nil
+	}
+	return "", fmt.Errorf("cannot encode %T as YAML", v)
+}
+
+// yamlString returns s as a plain scalar when that reads back as the same
+// string, and double-quoted otherwise. Words that YAML 1.1 reads as
+// booleans, such as yes and off, are quoted too.
+func yamlString(s string) string {
+	plain := s != "" && s != "..." && resolveYAMLScalar(s) == any(s) && !isYAML11Bool(s) &&
+		!strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@` ") &&
+		!strings.HasSuffix(s, " ") && !strings.HasSuffix(s, ":") &&
+		!strings.Contains(s, ": ") && !strings.Contains(s, " #") &&
+		strings.IndexFunc(s, func(r rune) bool { return r != ' ' && !unicode.IsPrint(r) }) < 0
+	if plain {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// isYAML11Bool reports whether s is a boolean in YAML 1.1 but a string in
+// YAML 1.2
+func isYAML11Bool(s string) bool {
+	switch s {
+	case "y", "Y", "yes", "Yes", "YES", "n", "N", "no", "No", "NO",
+		"on", "On", "ON", "off", "Off", "OFF":
+		return true
+	}
+	return false
+}
+
+// floatLiteral formats a finite float so that it reads back as a float in
+// YAML and TOML, e.g. 3 as "3.0"
+func floatLiteral(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package blaze
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// RequestIDHeader is the header read for the request_id log field
+const RequestIDHeader = "X-Request-ID"
+
+// LoggerConfig defines which requests Logger and SlogLogger record
+type LoggerConfig struct {
+	Skipper    func(*Context) bool // return true to not log a request
+	SampleRate int                 // log 1 in N requests; 0 or 1 logs every request
+}
+
+// DefaultLoggerConfig provides sensible defaults
+func DefaultLoggerConfig() LoggerConfig {
+	return LoggerConfig{
+		SampleRate: 1,
+	}
+}
+
+// SkipPaths returns a Skipper that skips requests for the given exact paths,
+// e.g. SkipPaths("/health", "/metrics")
+func SkipPaths(paths ...string) func(*Context) bool {
+	return func(c *Context) bool {
+		return contains(paths, c.Request.URL.Path)
+	}
+}
+
+// logSampler applies a LoggerConfig's skipper and sampling rate
+type logSampler struct {
+	cfg LoggerConfig
+	n   atomic.Uint64
+}
+
+func newLogSampler(config []LoggerConfig) *logSampler {
+	cfg := DefaultLoggerConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return &logSampler{cfg: cfg}
+}
+
+// shouldLog reports whether the request should be logged. Skipped requests
+// don't count towards sampling.
+func (s *logSampler) shouldLog(c *Context) bool {
+	if s.cfg.Skipper != nil && s.cfg.Skipper(c) {
+		return false
+	}
+	if s.cfg.SampleRate <= 1 {
+		return true
+	}
+	return s.n.Add(1)%uint64(s.cfg.SampleRate) == 1
+}
+
+// SlogLogger returns a middleware that logs each request as a structured
+// slog record with method, path, route, status, latency, client IP, response
+// size and request ID. Server errors are logged at Error level with the
+// handler error. A nil logger uses slog.Default(); wrap any slog.Handler with
+// slog.New to plug in a custom pipeline.
+func SlogLogger(logger *slog.Logger, config ...LoggerConfig) MiddlewareFunc {
+	sampler := newLogSampler(config)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if !sampler.shouldLog(c) {
+				return next(c)
+			}
+
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: c.ResponseWriter}
+			c.ResponseWriter = sw
+			err := next(c)
+			c.ResponseWriter = sw.ResponseWriter
+
+			l := logger
+			if l == nil {
+				l = slog.Default()
+			}
+
+			status := responseStatus(sw, err)
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.String("route", c.Route()),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("client_ip", c.ClientIP()),
+				slog.Int("bytes", sw.size),
+				slog.String("request_id", requestID(c)),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			l.LogAttrs(context.Background(), level, "request", attrs...)
+			return err
+		}
+	}
+}
+
+// requestID returns the request's X-Request-ID, falling back to one set on
+// the response by an earlier middleware
+func requestID(c *Context) string {
+	if id := c.Request.Header.Get(RequestIDHeader); id != "" {
+		return id
+	}
+	return c.ResponseWriter.Header().Get(RequestIDHeader)
+}
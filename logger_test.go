@@ -0,0 +1,139 @@
+package blaze
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordHandler is a slog.Handler that keeps every record for inspection
+type recordHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+// attrs flattens a record's attributes into a map
+func attrs(r slog.Record) map[string]slog.Value {
+	m := map[string]slog.Value{}
+	r.Attrs(func(a slog.Attr) bool {
+		m[a.Key] = a.Value
+		return true
+	})
+	return m
+}
+
+func TestSlogLogger(t *testing.T) {
+	h := &recordHandler{}
+	e := New()
+	e.Use(SlogLogger(slog.New(h)))
+	e.GET("/users/:id", func(c *Context) error {
+		return c.String(200, "hello")
+	})
+	e.GET("/boom", func(c *Context) error {
+		return errors.New("db down")
+	})
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	req.Header.Set("X-Forwarded-For", "203.0.113.9, 10.0.0.1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+
+	if len(h.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(h.records))
+	}
+
+	r := h.records[0]
+	if r.Level != slog.LevelInfo || r.Message != "request" {
+		t.Fatalf("unexpected record %v %q", r.Level, r.Message)
+	}
+	a := attrs(r)
+	want := map[string]any{
+		"method":     "GET",
+		"path":       "/users/42",
+		"route":      "/users/:id",
+		"status":     int64(200),
+		"client_ip":  "203.0.113.9",
+		"bytes":      int64(5),
+		"request_id": "req-1",
+	}
+	for key, v := range want {
+		if got := a[key].Any(); got != v {
+			t.Errorf("%s: expected %v, got %v", key, v, got)
+		}
+	}
+	if a["latency"].Kind() != slog.KindDuration {
+		t.Errorf("expected latency duration, got %v", a["latency"])
+	}
+
+	r = h.records[1]
+	a = attrs(r)
+	if r.Level != slog.LevelError || a["status"].Int64() != 500 || a["error"].String() != "db down" {
+		t.Errorf("expected error record for 500, got level=%v attrs=%v", r.Level, a)
+	}
+}
+
+func TestLogger_Skipper(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	h := &recordHandler{}
+	cfg := LoggerConfig{Skipper: SkipPaths("/health")}
+	e := New()
+	e.Use(Logger(cfg), SlogLogger(slog.New(h), cfg))
+	e.GET("/health", func(c *Context) error { return c.String(200, "ok") })
+	e.GET("/users", func(c *Context) error { return c.String(200, "users") })
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	if buf.Len() != 0 || len(h.records) != 0 {
+		t.Fatalf("expected no log for skipped path, got %q and %d records", buf.String(), len(h.records))
+	}
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+	if !strings.Contains(buf.String(), "/users") || len(h.records) != 1 {
+		t.Fatalf("expected /users to be logged, got %q and %d records", buf.String(), len(h.records))
+	}
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	h := &recordHandler{}
+	cfg := LoggerConfig{SampleRate: 10}
+	e := New()
+	e.Use(Logger(cfg), SlogLogger(slog.New(h), cfg))
+	e.GET("/ping", func(c *Context) error { return c.String(200, "pong") })
+
+	const requests = 1000
+	for range requests {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ping", nil))
+	}
+
+	lines := strings.Count(buf.String(), "\n")
+	for name, got := range map[string]int{"Logger": lines, "SlogLogger": len(h.records)} {
+		if got < 90 || got > 110 {
+			t.Errorf("%s: expected about %d lines at 1 in 10, got %d", name, requests/10, got)
+		}
+	}
+}
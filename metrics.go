@@ -0,0 +1,240 @@
+package blaze
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultBuckets are the latency histogram upper bounds in seconds
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// MetricsRegistry collects request metrics for the Metrics middleware
+type MetricsRegistry struct {
+	mu        sync.Mutex
+	requests  map[requestLabels]uint64
+	latencies map[routeLabels]*histogram
+	inFlight  atomic.Int64
+	buckets   []float64
+}
+
+type routeLabels struct {
+	method string
+	route  string
+}
+
+type requestLabels struct {
+	routeLabels
+	status int
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, non-cumulative
+	sum    float64
+	count  uint64
+}
+
+// NewMetricsRegistry creates an empty registry
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		requests:  make(map[requestLabels]uint64),
+		latencies: make(map[routeLabels]*histogram),
+		buckets:   defaultBuckets,
+	}
+}
+
+// DefaultMetrics is the registry used when none is passed to Metrics or
+// MetricsHandler
+var DefaultMetrics = NewMetricsRegistry()
+
+// observe records one completed request
+func (m *MetricsRegistry) observe(method, route string, status int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rl := routeLabels{method: method, route: route}
+	m.requests[requestLabels{routeLabels: rl, status: status}]++
+
+	h := m.latencies[rl]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[rl] = h
+	}
+	seconds := d.Seconds()
+	for i, upper := range m.buckets {
+		if seconds <= upper {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// RequestCount returns the number of requests recorded for a method, route
+// template and status
+func (m *MetricsRegistry) RequestCount(method, route string, status int) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests[requestLabels{routeLabels: routeLabels{method: method, route: route}, status: status}]
+}
+
+// Metrics returns a middleware that records request counts, latencies and
+// in-flight requests. Requests are labeled by route template (e.g.
+// "/users/:id") rather than concrete path to keep cardinality bounded.
+func Metrics(registry ...*MetricsRegistry) MiddlewareFunc {
+	m := DefaultMetrics
+	if len(registry) > 0 {
+		m = registry[0]
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			m.inFlight.Add(1)
+			defer m.inFlight.Add(-1)
+
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: c.ResponseWriter}
+			c.ResponseWriter = sw
+			err := next(c)
+			c.ResponseWriter = sw.ResponseWriter
+
+			m.observe(c.Request.Method, c.Route(), responseStatus(sw, err), time.Since(start))
+			return err
+		}
+	}
+}
+
+// MetricsHandler renders the registry in the Prometheus text exposition format
+func MetricsHandler(registry ...*MetricsRegistry) HandlerFunc {
+	m := DefaultMetrics
+	if len(registry) > 0 {
+		m = registry[0]
+	}
+
+	return func(c *Context) error {
+		c.SetHeader("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.ResponseWriter.WriteHeader(http.StatusOK)
+		_, err := c.ResponseWriter.Write([]byte(m.render()))
+		return err
+	}
+}
+
+// render formats all metrics in Prometheus text format, sorted for stable output
+func (m *MetricsRegistry) render() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP blaze_http_requests_total Total number of HTTP requests.\n")
+	b.WriteString("# TYPE blaze_http_requests_total counter\n")
+	reqKeys := make([]requestLabels, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		if reqKeys[i].routeLabels != reqKeys[j].routeLabels {
+			return lessRoute(reqKeys[i].routeLabels, reqKeys[j].routeLabels)
+		}
+		return reqKeys[i].status < reqKeys[j].status
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(&b, "blaze_http_requests_total{method=\"%s\",route=\"%s\",status=\"%d\"} %d\n",
+			escapeLabel(k.method), escapeLabel(k.route), k.status, m.requests[k])
+	}
+
+	b.WriteString("# HELP blaze_http_request_duration_seconds HTTP request latency in seconds.\n")
+	b.WriteString("# TYPE blaze_http_request_duration_seconds histogram\n")
+	routes := make([]routeLabels, 0, len(m.latencies))
+	for k := range m.latencies {
+		routes = append(routes, k)
+	}
+	sort.Slice(routes, func(i, j int) bool { return lessRoute(routes[i], routes[j]) })
+	for _, k := range routes {
+		h := m.latencies[k]
+		labels := fmt.Sprintf("method=\"%s\",route=\"%s\"", escapeLabel(k.method), escapeLabel(k.route))
+		var cumulative uint64
+		for i, upper := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "blaze_http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(upper, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "blaze_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(&b, "blaze_http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "blaze_http_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	b.WriteString("# HELP blaze_http_requests_in_flight Number of HTTP requests currently being served.\n")
+	b.WriteString("# TYPE blaze_http_requests_in_flight gauge\n")
+	fmt.Fprintf(&b, "blaze_http_requests_in_flight %d\n", m.inFlight.Load())
+
+	return b.String()
+}
+
+func lessRoute(a, b routeLabels) bool {
+	if a.route != b.route {
+		return a.route < b.route
+	}
+	return a.method < b.method
+}
+
+// labelEscaper escapes label values per the Prometheus text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value for use inside double quotes
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// statusWriter records the status code and bytes written to a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher so streaming handlers keep working
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// responseStatus returns the status that was (or will be) sent: the written
+// status, else the status ServeHTTP derives from the handler error
+func responseStatus(w *statusWriter, err error) int {
+	if w.status != 0 {
+		return w.status
+	}
+	if err != nil {
+		var he *HTTPError
+		if errors.As(err, &he) {
+			return he.Code
+		}
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
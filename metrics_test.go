@@ -0,0 +1,83 @@
+package blaze
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMetrics_CountsByRouteTemplate(t *testing.T) {
+	reg := NewMetricsRegistry()
+	e := New()
+	e.Use(Metrics(reg))
+	e.GET("/users/:id", func(c *Context) error { return c.String(200, "ok") })
+	e.GET("/missing", func(c *Context) error { return NewHTTPError(404, "nope") })
+	e.GET("/metrics", MetricsHandler(reg))
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/3", "/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	if got := reg.RequestCount("GET", "/users/:id", 200); got != 3 {
+		t.Fatalf("expected 3 requests for /users/:id, got %d", got)
+	}
+	if got := reg.RequestCount("GET", "/missing", 404); got != 1 {
+		t.Fatalf("expected HTTPError status to be recorded, got %d", got)
+	}
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	if strings.Contains(body, "/users/1") {
+		t.Fatal("expected concrete paths not to appear as labels")
+	}
+
+	// Every non-comment line must be `name{labels} value` or `name value`
+	line := regexp.MustCompile(`^([a-z_]+)(\{[a-z]+="[^"]*"(,[a-z]+="[^"]*")*\})? (\S+)$`)
+	samples := map[string]string{}
+	for _, l := range strings.Split(strings.TrimSpace(body), "\n") {
+		if strings.HasPrefix(l, "#") {
+			continue
+		}
+		m := line.FindStringSubmatch(l)
+		if m == nil {
+			t.Fatalf("unparseable exposition line: %q", l)
+		}
+		if _, err := strconv.ParseFloat(m[4], 64); err != nil {
+			t.Fatalf("non-numeric sample value in %q", l)
+		}
+		samples[m[1]+m[2]] = m[4]
+	}
+
+	if samples[`blaze_http_requests_total{method="GET",route="/users/:id",status="200"}`] != "3" {
+		t.Fatalf("missing request counter in:\n%s", body)
+	}
+	if samples[`blaze_http_request_duration_seconds_count{method="GET",route="/users/:id"}`] != "3" {
+		t.Fatalf("missing histogram count in:\n%s", body)
+	}
+	if samples[`blaze_http_request_duration_seconds_bucket{method="GET",route="/users/:id",le="+Inf"}`] != "3" {
+		t.Fatalf("missing +Inf bucket in:\n%s", body)
+	}
+	// The /metrics request itself is in flight while rendering
+	if samples["blaze_http_requests_in_flight"] != "1" {
+		t.Fatalf("expected 1 in-flight request, got %s", samples["blaze_http_requests_in_flight"])
+	}
+}
+
+func TestContext_Route(t *testing.T) {
+	var route string
+	e := New()
+	api := e.Group("/api")
+	api.GET("/files/*filepath", func(c *Context) error {
+		route = c.Route()
+		return nil
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/files/a/b.txt", nil))
+	if route != "/api/files/*filepath" {
+		t.Fatalf("expected route template, got %q", route)
+	}
+}
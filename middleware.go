@@ -1,15 +1,36 @@
 package blaze
 
 import (
+	"cmp"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
 	"log"
+	"mime"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// Logger returns a middleware that logs request info
-func Logger() MiddlewareFunc {
+// Logger returns a middleware that logs request info. An optional
+// LoggerConfig skips or samples requests.
+func Logger(config ...LoggerConfig) MiddlewareFunc {
+	sampler := newLogSampler(config)
+
 	return func(next HandlerFunc) HandlerFunc {
 		return func(c *Context) error {
+			if !sampler.shouldLog(c) {
+				return next(c)
+			}
+
 			start := time.Now()
 			err := next(c)
 			status := "OK"
@@ -39,9 +60,11 @@ func Recovery() MiddlewareFunc {
 
 // CORSConfig defines CORS options
 type CORSConfig struct {
-	AllowOrigins []string
-	AllowMethods []string
-	AllowHeaders []string
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string // "*" allows any requested header
+	AllowCredentials bool     // send Access-Control-Allow-Credentials: true
+	MaxAge           int      // seconds browsers may cache a preflight, 0 = unset
 }
 
 // DefaultCORSConfig provides sensible defaults
@@ -50,41 +73,58 @@ func DefaultCORSConfig() CORSConfig {
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		MaxAge:       600,
 	}
 }
 
-// CORS returns a middleware that handles CORS
+// CORS returns a middleware that handles CORS. Preflight (OPTIONS) requests
+// get the allowed methods and the requested headers that are allowed; with
+// AllowCredentials the request origin is echoed instead of "*".
 func CORS(config ...CORSConfig) MiddlewareFunc {
 	cfg := DefaultCORSConfig()
 	if len(config) > 0 {
 		cfg = config[0]
 	}
+	wildcard := contains(cfg.AllowOrigins, "*")
+	// The response depends on Origin unless every origin gets "*"
+	varyOrigin := !wildcard || cfg.AllowCredentials
 
 	return func(next HandlerFunc) HandlerFunc {
 		return func(c *Context) error {
+			if varyOrigin {
+				addVary(c.ResponseWriter.Header(), "Origin")
+			}
+
 			origin := c.Request.Header.Get("Origin")
 			if origin == "" {
 				return next(c)
 			}
 
 			// Check if origin is allowed
-			allowed := false
-			for _, o := range cfg.AllowOrigins {
-				if o == "*" || o == origin {
-					allowed = true
-					break
-				}
-			}
-			if !allowed {
+			if !wildcard && !contains(cfg.AllowOrigins, origin) {
 				return next(c)
 			}
 
-			c.SetHeader("Access-Control-Allow-Origin", origin)
-			c.SetHeader("Access-Control-Allow-Methods", join(cfg.AllowMethods))
-			c.SetHeader("Access-Control-Allow-Headers", join(cfg.AllowHeaders))
+			// Credentialed responses may not use the "*" origin
+			if wildcard && !cfg.AllowCredentials {
+				c.SetHeader("Access-Control-Allow-Origin", "*")
+			} else {
+				c.SetHeader("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				c.SetHeader("Access-Control-Allow-Credentials", "true")
+			}
 
 			// Handle preflight
 			if c.Request.Method == "OPTIONS" {
+				addVary(c.ResponseWriter.Header(), "Access-Control-Request-Method", "Access-Control-Request-Headers")
+				c.SetHeader("Access-Control-Allow-Methods", join(cfg.AllowMethods))
+				if headers := allowedRequestHeaders(cfg.AllowHeaders, c.Request.Header.Get("Access-Control-Request-Headers")); headers != "" {
+					c.SetHeader("Access-Control-Allow-Headers", headers)
+				}
+				if cfg.MaxAge > 0 {
+					c.SetHeader("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+				}
 				return c.NoContent()
 			}
 
@@ -93,6 +133,403 @@ func CORS(config ...CORSConfig) MiddlewareFunc {
 	}
 }
 
+// addVary appends values to the Vary header, skipping any already listed
+func addVary(h http.Header, values ...string) {
+	var existing []string
+	for _, line := range h.Values("Vary") {
+		for _, v := range strings.Split(line, ",") {
+			existing = append(existing, strings.TrimSpace(v))
+		}
+	}
+	for _, v := range values {
+		if !slices.ContainsFunc(existing, func(e string) bool { return e == "*" || strings.EqualFold(e, v) }) {
+			h.Add("Vary", v)
+			existing = append(existing, v)
+		}
+	}
+}
+
+// allowedRequestHeaders returns the headers from a preflight's
+// Access-Control-Request-Headers that are allowed, or the configured list
+// when none were requested
+func allowedRequestHeaders(allow []string, requested string) string {
+	if strings.TrimSpace(requested) == "" {
+		if contains(allow, "*") {
+			return ""
+		}
+		return join(allow)
+	}
+
+	var out []string
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		if contains(allow, "*") || slices.ContainsFunc(allow, func(a string) bool { return strings.EqualFold(a, h) }) {
+			out = append(out, h)
+		}
+	}
+	return join(out)
+}
+
+// SecureConfig defines the headers set by SecureHeaders. An empty value
+// (or 0 HSTSMaxAge) leaves that header out.
+type SecureConfig struct {
+	ContentTypeNosniff    string // X-Content-Type-Options
+	FrameOptions          string // X-Frame-Options
+	ReferrerPolicy        string // Referrer-Policy
+	HSTSMaxAge            int    // Strict-Transport-Security max-age in seconds, sent only over TLS
+	HSTSIncludeSubdomains bool
+	ContentSecurityPolicy string // Content-Security-Policy
+}
+
+// DefaultSecureConfig provides sensible defaults
+func DefaultSecureConfig() SecureConfig {
+	return SecureConfig{
+		ContentTypeNosniff:    "nosniff",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+	}
+}
+
+// SecureHeaders returns a middleware that sets common security headers.
+// Headers already present on the response are left untouched, and handlers
+// can still override any of them.
+func SecureHeaders(config ...SecureConfig) MiddlewareFunc {
+	cfg := DefaultSecureConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			h := c.ResponseWriter.Header()
+			setDefault(h, "X-Content-Type-Options", cfg.ContentTypeNosniff)
+			setDefault(h, "X-Frame-Options", cfg.FrameOptions)
+			setDefault(h, "Referrer-Policy", cfg.ReferrerPolicy)
+			setDefault(h, "Content-Security-Policy", cfg.ContentSecurityPolicy)
+			if c.Request.TLS != nil {
+				setDefault(h, "Strict-Transport-Security", hsts)
+			}
+			return next(c)
+		}
+	}
+}
+
+// setDefault sets a header unless value is empty or the header is already set
+func setDefault(h http.Header, key, value string) {
+	if value != "" && h.Get(key) == "" {
+		h.Set(key, value)
+	}
+}
+
+// DecompressConfig defines request decompression options
+type DecompressConfig struct {
+	MaxSize int64 // maximum decompressed body size in bytes
+}
+
+// DefaultDecompressConfig provides sensible defaults
+func DefaultDecompressConfig() DecompressConfig {
+	return DecompressConfig{MaxSize: 10 << 20}
+}
+
+// DecompressRequest returns a middleware that transparently decodes gzip and
+// deflate request bodies based on Content-Encoding, so BindJSON sees plain JSON.
+// Bodies that inflate beyond MaxSize fail to read, guarding against zip bombs.
+// Zero fields of config take their values from DefaultDecompressConfig.
+func DecompressRequest(config ...DecompressConfig) MiddlewareFunc {
+	cfg := DefaultDecompressConfig()
+	if len(config) > 0 {
+		cfg.MaxSize = cmp.Or(config[0].MaxSize, cfg.MaxSize)
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			encoding := strings.ToLower(strings.TrimSpace(c.Request.Header.Get("Content-Encoding")))
+			if encoding == "" || encoding == "identity" || c.Request.Body == nil {
+				return next(c)
+			}
+
+			var reader io.ReadCloser
+			var err error
+			switch encoding {
+			case "gzip", "x-gzip":
+				reader, err = gzip.NewReader(c.Request.Body)
+			case "deflate":
+				reader, err = zlib.NewReader(c.Request.Body)
+			default:
+				return NewHTTPError(http.StatusUnsupportedMediaType, "unsupported Content-Encoding", encoding)
+			}
+			if err != nil {
+				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s body", encoding), err.Error())
+			}
+
+			c.Request.Body = &limitedBody{reader: reader, original: c.Request.Body, remaining: cfg.MaxSize}
+			c.Request.Header.Del("Content-Encoding")
+			c.Request.Header.Del("Content-Length")
+			c.Request.ContentLength = -1
+			return next(c)
+		}
+	}
+}
+
+// CaptureBodyConfig defines CaptureBody options
+type CaptureBodyConfig struct {
+	MaxSize int64 // maximum body size in bytes
+}
+
+// DefaultCaptureBodyConfig provides sensible defaults
+func DefaultCaptureBodyConfig() CaptureBodyConfig {
+	return CaptureBodyConfig{MaxSize: 10 << 20}
+}
+
+// CaptureBody returns a middleware that buffers the request body before
+// the handler runs, so later middleware and the handler can both read it
+// through Context.Body, and BindJSON still works. Bodies over MaxSize are
+// rejected with 413 Request Entity Too Large.
+func CaptureBody(config ...CaptureBodyConfig) MiddlewareFunc {
+	cfg := DefaultCaptureBodyConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			if _, err := c.captureBody(cfg.MaxSize); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireJSONConfig defines RequireJSON options
+type RequireJSONConfig struct {
+	AllowMissing bool // let requests without a Content-Type through
+}
+
+// RequireJSON returns a middleware that rejects POST, PUT and PATCH requests
+// whose Content-Type isn't JSON with a 415 Unsupported Media Type error,
+// before the handler tries to decode the body. application/json and
+// "+json" types such as application/merge-patch+json are accepted, with
+// any parameters. Other methods pass through unchecked.
+func RequireJSON(config ...RequireJSONConfig) MiddlewareFunc {
+	var cfg RequireJSONConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			switch c.Request.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch:
+			default:
+				return next(c)
+			}
+
+			contentType := c.Request.Header.Get("Content-Type")
+			if contentType == "" {
+				if cfg.AllowMissing {
+					return next(c)
+				}
+				return NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+			}
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+				return NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be application/json", contentType)
+			}
+			return next(c)
+		}
+	}
+}
+
+// MaxInFlightConfig defines MaxInFlight options
+type MaxInFlightConfig struct {
+	QueueSize    int           // requests that may wait for a slot (0 = reject at once)
+	QueueTimeout time.Duration // how long a queued request waits before it is rejected
+	RetryAfter   time.Duration // Retry-After sent with the 503, rounded up to seconds (negative = none)
+}
+
+// DefaultMaxInFlightConfig provides sensible defaults
+func DefaultMaxInFlightConfig() MaxInFlightConfig {
+	return MaxInFlightConfig{
+		QueueTimeout: 5 * time.Second,
+		RetryAfter:   time.Second,
+	}
+}
+
+// MaxInFlight returns a middleware that lets at most n requests run at once.
+// A slot is held from before the handler until it returns. Further requests
+// wait in a queue of QueueSize, for up to QueueTimeout or until the client
+// goes away; when the queue is full or the wait times out they are rejected
+// with 503 Service Unavailable and a Retry-After header. Zero fields of
+// config take their values from DefaultMaxInFlightConfig.
+func MaxInFlight(n int, config ...MaxInFlightConfig) MiddlewareFunc {
+	def := DefaultMaxInFlightConfig()
+	cfg := def
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.QueueTimeout = cmp.Or(cfg.QueueTimeout, def.QueueTimeout)
+	cfg.RetryAfter = cmp.Or(cfg.RetryAfter, def.RetryAfter)
+	if n < 1 {
+		n = 1
+	}
+	slots := make(chan struct{}, n)
+	queue := make(chan struct{}, max(cfg.QueueSize, 0))
+	retryAfter := strconv.Itoa(int((cfg.RetryAfter + time.Second - 1) / time.Second))
+
+	reject := func(c *Context) error {
+		if cfg.RetryAfter > 0 {
+			c.SetHeader("Retry-After", retryAfter)
+		}
+		return NewHTTPError(http.StatusServiceUnavailable, "too many requests in flight")
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			select {
+			case slots <- struct{}{}:
+			default:
+				select {
+				case queue <- struct{}{}:
+				default:
+					return reject(c)
+				}
+				timer := time.NewTimer(cfg.QueueTimeout)
+				select {
+				case slots <- struct{}{}:
+					timer.Stop()
+					<-queue
+				case <-timer.C:
+					<-queue
+					return reject(c)
+				case <-c.Request.Context().Done():
+					timer.Stop()
+					<-queue
+					return reject(c)
+				}
+			}
+			defer func() { <-slots }()
+			return next(c)
+		}
+	}
+}
+
+// HMACConfig defines HMACVerify options
+type HMACConfig struct {
+	Secret  []byte           // shared key, required
+	Header  string           // header carrying the signature
+	Algo    func() hash.Hash // hash for the HMAC, e.g. sha1.New
+	MaxSize int64            // maximum body size in bytes
+}
+
+// DefaultHMACConfig provides sensible defaults. Secret must still be set.
+func DefaultHMACConfig() HMACConfig {
+	return HMACConfig{
+		Header:  "X-Signature",
+		Algo:    sha256.New,
+		MaxSize: 10 << 20,
+	}
+}
+
+// HMACVerify returns a middleware that authenticates requests signed with a
+// shared secret, such as webhooks triggering tools. The header must hold
+// the hex-encoded HMAC of the raw body, optionally prefixed like
+// "sha256=". Requests with a missing or wrong signature get a 401, bodies
+// over MaxSize a 413. The body is buffered as by Context.Body, so the
+// handler reads it as usual. Unset fields of config take their defaults.
+// It panics without a Secret, since an empty key lets anyone sign requests.
+func HMACVerify(config HMACConfig) MiddlewareFunc {
+	if len(config.Secret) == 0 {
+		panic("blaze: HMACVerify requires a Secret")
+	}
+	def := DefaultHMACConfig()
+	cfg := config
+	cfg.Header = cmp.Or(cfg.Header, def.Header)
+	cfg.MaxSize = cmp.Or(cfg.MaxSize, def.MaxSize)
+	if cfg.Algo == nil {
+		cfg.Algo = def.Algo
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			signature := c.Request.Header.Get(cfg.Header)
+			if signature == "" {
+				return NewHTTPError(http.StatusUnauthorized, "missing signature", cfg.Header)
+			}
+			if _, hexSum, ok := strings.Cut(signature, "="); ok {
+				signature = hexSum
+			}
+			want, err := hex.DecodeString(strings.TrimSpace(signature))
+			if err != nil {
+				return NewHTTPError(http.StatusUnauthorized, "invalid signature")
+			}
+
+			body, err := c.captureBody(cfg.MaxSize)
+			if err != nil {
+				return err
+			}
+			mac := hmac.New(cfg.Algo, cfg.Secret)
+			mac.Write(body)
+			if !hmac.Equal(mac.Sum(nil), want) {
+				return NewHTTPError(http.StatusUnauthorized, "invalid signature")
+			}
+			return next(c)
+		}
+	}
+}
+
+// errBodyTooLarge is returned when a decompressed body exceeds its limit
+var errBodyTooLarge = errors.New("request body too large")
+
+// limitedBody errors once more than remaining bytes have been read
+type limitedBody struct {
+	reader    io.ReadCloser
+	original  io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Probe for one more byte to distinguish EOF from overflow. A read
+		// may return neither data nor an error, so retry like bufio does.
+		var probe [1]byte
+		for range 100 {
+			n, err := b.reader.Read(probe[:])
+			if n > 0 {
+				return 0, errBodyTooLarge
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+		return 0, io.ErrNoProgress
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.reader.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	b.reader.Close()
+	return b.original.Close()
+}
+
 // join concatenates strings with comma separator
 func join(s []string) string {
 	if len(s) == 0 {
@@ -0,0 +1,672 @@
+package blaze
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestDecompressRequest_Gzip(t *testing.T) {
+	e := New()
+	e.Use(DecompressRequest())
+
+	var got struct {
+		Model string `json:"model"`
+	}
+	e.POST("/chat", func(c *Context) error {
+		if err := c.BindJSON(&got); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.NoContent()
+	})
+
+	body := gzipBytes(t, []byte(`{"model":"claude-3-5-sonnet"}`))
+	req := httptest.NewRequest("POST", "/chat", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.Model != "claude-3-5-sonnet" {
+		t.Fatalf("expected model to bind, got %q", got.Model)
+	}
+}
+
+func TestDecompressRequest_ZeroConfigUsesDefaults(t *testing.T) {
+	e := New()
+	e.Use(DecompressRequest(DecompressConfig{}))
+	e.POST("/chat", func(c *Context) error {
+		var body map[string]any
+		if err := c.BindJSON(&body); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.NoContent()
+	})
+
+	req := httptest.NewRequest("POST", "/chat", bytes.NewReader(gzipBytes(t, []byte(`{"model":"claude-3-5-sonnet"}`))))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 with the default MaxSize, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDecompressRequest_SizeLimit(t *testing.T) {
+	e := New()
+	e.Use(DecompressRequest(DecompressConfig{MaxSize: 1024}))
+
+	var payload struct {
+		Data string `json:"data"`
+	}
+	e.POST("/chat", func(c *Context) error {
+		if err := c.BindJSON(&payload); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.NoContent()
+	})
+
+	bomb := gzipBytes(t, []byte(`{"data":"`+strings.Repeat("a", 64*1024)+`"}`))
+	req := httptest.NewRequest("POST", "/chat", bytes.NewReader(bomb))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "too large") {
+		t.Fatalf("expected size error, got %q", w.Body.String())
+	}
+}
+
+func TestDecompressRequest_InvalidGzip(t *testing.T) {
+	e := New()
+	e.Use(DecompressRequest())
+	e.POST("/chat", func(c *Context) error { return c.NoContent() })
+
+	req := httptest.NewRequest("POST", "/chat", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestDecompressRequest_UnsupportedEncoding(t *testing.T) {
+	e := New()
+	e.Use(DecompressRequest())
+	e.POST("/chat", func(c *Context) error { return c.NoContent() })
+
+	req := httptest.NewRequest("POST", "/chat", strings.NewReader("{}"))
+	req.Header.Set("Content-Encoding", "br")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"detail":"br"`) {
+		t.Errorf("expected a JSON error with the encoding, got %q", w.Body.String())
+	}
+}
+
+// stallReader returns (0, nil) stalls times before each read of r
+type stallReader struct {
+	r      io.Reader
+	stalls int
+	left   int
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	if s.left > 0 {
+		s.left--
+		return 0, nil
+	}
+	s.left = s.stalls
+	return s.r.Read(p)
+}
+
+func TestLimitedBody_ProbeRetriesEmptyReads(t *testing.T) {
+	b := &limitedBody{
+		reader:    io.NopCloser(&stallReader{r: strings.NewReader("abcd"), stalls: 2}),
+		original:  io.NopCloser(strings.NewReader("")),
+		remaining: 3,
+	}
+	if _, err := io.ReadAll(b); !errors.Is(err, errBodyTooLarge) {
+		t.Errorf("expected the body to be too large, got %v", err)
+	}
+
+	b = &limitedBody{
+		reader:    io.NopCloser(&stallReader{r: strings.NewReader("abc"), stalls: 2}),
+		original:  io.NopCloser(strings.NewReader("")),
+		remaining: 3,
+	}
+	if got, err := io.ReadAll(b); err != nil || string(got) != "abc" {
+		t.Errorf("expected the whole body, got %q, %v", got, err)
+	}
+}
+
+func TestRequireJSON(t *testing.T) {
+	e := New()
+	e.Use(RequireJSON())
+	e.POST("/chat", func(c *Context) error { return c.String(200, "ok") })
+	e.GET("/chat", func(c *Context) error { return c.String(200, "ok") })
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"json", "POST", "application/json", `{}`, 200},
+		{"json with charset", "POST", "application/json; charset=utf-8", `{}`, 200},
+		{"json suffix", "POST", "application/merge-patch+json", `{}`, 200},
+		{"form", "POST", "application/x-www-form-urlencoded", "a=1", 415},
+		{"missing", "POST", "", `{}`, 415},
+		{"empty GET", "GET", "", "", 200},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/chat", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, w.Code)
+		}
+		if tt.status == 415 && !strings.Contains(w.Body.String(), `"code":415`) {
+			t.Errorf("%s: expected a JSON error, got %s", tt.name, w.Body.String())
+		}
+	}
+}
+
+func TestRequireJSON_AllowMissing(t *testing.T) {
+	e := New()
+	e.Use(RequireJSON(RequireJSONConfig{AllowMissing: true}))
+	e.POST("/chat", func(c *Context) error { return c.String(200, "ok") })
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("POST", "/chat", strings.NewReader(`{}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 without Content-Type, got %d", w.Code)
+	}
+}
+
+// corsEngine registers GET and OPTIONS /api behind CORS(cfg)
+func corsEngine(cfg CORSConfig) *Engine {
+	e := New()
+	e.Use(CORS(cfg))
+	e.GET("/api", func(c *Context) error { return c.String(200, "ok") })
+	e.OPTIONS("/api", func(c *Context) error { return c.NoContent() })
+	return e
+}
+
+func TestCORS_PreflightRequestHeaders(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowHeaders = []string{"Content-Type", "X-Api-Key"}
+	e := corsEngine(cfg)
+
+	req := httptest.NewRequest("OPTIONS", "/api", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "x-api-key, content-type, x-secret")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "x-api-key, content-type" {
+		t.Errorf("expected allowed subset of requested headers, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected * origin without credentials, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected Max-Age 600, got %q", got)
+	}
+	if !strings.Contains(w.Header().Get("Access-Control-Allow-Methods"), "POST") {
+		t.Errorf("expected POST in allowed methods, got %q", w.Header().Get("Access-Control-Allow-Methods"))
+	}
+
+	// "*" reflects any requested header
+	cfg.AllowHeaders = []string{"*"}
+	w = httptest.NewRecorder()
+	corsEngine(cfg).ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "x-api-key, content-type, x-secret" {
+		t.Errorf("expected all requested headers with *, got %q", got)
+	}
+}
+
+func TestCORS_Credentials(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowCredentials = true
+	e := corsEngine(cfg)
+
+	req := httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Cookie", "session=1")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if w.Body.String() != "ok" {
+		t.Fatalf("expected handler to run, got %q", w.Body.String())
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("expected echoed origin with credentials, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("expected Allow-Credentials true, got %q", got)
+	}
+
+	// Disallowed origins get no CORS headers
+	cfg.AllowOrigins = []string{"https://other.example.com"}
+	w = httptest.NewRecorder()
+	corsEngine(cfg).ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS headers for disallowed origin, got %q", got)
+	}
+}
+
+func TestCORS_Vary(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowOrigins = []string{"https://app.example.com"}
+	e := New()
+	e.Use(CORS(cfg))
+	e.GET("/api", func(c *Context) error {
+		c.ResponseWriter.Header().Add("Vary", "Accept-Encoding")
+		return c.String(200, "ok")
+	})
+	e.OPTIONS("/api", func(c *Context) error { return c.NoContent() })
+
+	req := httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Vary", "ignored")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if got := w.Header().Values("Vary"); len(got) != 2 || got[0] != "Origin" || got[1] != "Accept-Encoding" {
+		t.Errorf("expected Vary: Origin kept alongside handler value, got %q", got)
+	}
+
+	// Preflights also vary on the requested method and headers, without duplicates
+	req = httptest.NewRequest("OPTIONS", "/api", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w = httptest.NewRecorder()
+	w.Header().Set("Vary", "origin")
+	e.ServeHTTP(w, req)
+
+	vary := strings.Join(w.Header().Values("Vary"), ", ")
+	if vary != "origin, Access-Control-Request-Method, Access-Control-Request-Headers" {
+		t.Errorf("unexpected preflight Vary %q", vary)
+	}
+
+	// A wildcard without credentials always sends "*" and doesn't vary on Origin
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	corsEngine(DefaultCORSConfig()).ServeHTTP(w, req)
+	if got := w.Header().Values("Vary"); len(got) != 0 {
+		t.Errorf("expected no Vary for wildcard origin, got %q", got)
+	}
+}
+
+func TestSecureHeaders_Defaults(t *testing.T) {
+	e := New()
+	e.Use(SecureHeaders())
+	e.GET("/", func(c *Context) error { return c.String(200, "ok") })
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	want := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Strict-Transport-Security": "",
+		"Content-Security-Policy":   "",
+	}
+	for key, v := range want {
+		if got := w.Header().Get(key); got != v {
+			t.Errorf("%s: expected %q, got %q", key, v, got)
+		}
+	}
+}
+
+func TestSecureHeaders_HSTSOnlyOverTLS(t *testing.T) {
+	e := New()
+	e.Use(SecureHeaders(SecureConfig{HSTSMaxAge: 3600, ContentSecurityPolicy: "default-src 'self'"}))
+	e.GET("/", func(c *Context) error { return c.String(200, "ok") })
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "https://example.com/", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=3600" {
+		t.Errorf("expected HSTS over TLS, got %q", got)
+	}
+	if got := w.Header().Get("Content-Security-Policy"); got != "default-src 'self'" {
+		t.Errorf("expected CSP, got %q", got)
+	}
+	if got := w.Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("expected unset X-Frame-Options with a custom config, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("expected no HSTS over plain HTTP, got %q", got)
+	}
+}
+
+func TestSecureHeaders_NoClobber(t *testing.T) {
+	e := New()
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			c.SetHeader("X-Frame-Options", "SAMEORIGIN")
+			return next(c)
+		}
+	}, SecureHeaders())
+	e.GET("/embed", func(c *Context) error {
+		c.SetHeader("Referrer-Policy", "no-referrer")
+		return c.String(200, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/embed", nil))
+	if got := w.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+		t.Errorf("expected earlier X-Frame-Options to be kept, got %q", got)
+	}
+	if got := w.Header().Get("Referrer-Policy"); got != "no-referrer" {
+		t.Errorf("expected handler Referrer-Policy to win, got %q", got)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+	e := New()
+	e.Use(MaxInFlight(2, MaxInFlightConfig{RetryAfter: 3 * time.Second}))
+	e.GET("/slow", func(c *Context) error {
+		entered <- struct{}{}
+		<-release
+		return c.String(200, "ok")
+	})
+	e.GET("/fast", func(c *Context) error { return c.String(200, "ok") })
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			e.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+			if w.Code != 200 {
+				t.Errorf("expected 200 for an admitted request, got %d", w.Code)
+			}
+		}()
+	}
+	<-entered
+	<-entered
+
+	// The third concurrent request is over the limit
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	if w.Code != 503 || w.Header().Get("Retry-After") != "3" {
+		t.Fatalf("expected 503 with Retry-After 3, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Slots are released once the handlers return
+	close(release)
+	wg.Wait()
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	if w.Code != 200 {
+		t.Errorf("expected 200 after slots freed up, got %d", w.Code)
+	}
+}
+
+func TestMaxInFlight_Queue(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{}, 1)
+	e := New()
+	e.Use(MaxInFlight(1, MaxInFlightConfig{QueueSize: 1, QueueTimeout: time.Second, RetryAfter: time.Second}))
+	e.GET("/slow", func(c *Context) error {
+		select {
+		case entered <- struct{}{}:
+			<-release
+		default:
+		}
+		return c.String(200, "ok")
+	})
+
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	serve := func(i int) {
+		defer wg.Done()
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+		codes[i] = w.Code
+	}
+	wg.Add(1)
+	go serve(0)
+	<-entered
+
+	// The second request waits in the queue
+	wg.Add(1)
+	go serve(1)
+	time.Sleep(50 * time.Millisecond)
+
+	// With the queue full, the third is rejected
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	if w.Code != 503 {
+		t.Errorf("expected 503 with a full queue, got %d", w.Code)
+	}
+
+	close(release)
+	wg.Wait()
+	if codes[0] != 200 || codes[1] != 200 {
+		t.Errorf("expected the running and queued requests to succeed, got %v", codes)
+	}
+}
+
+func TestMaxInFlight_QueuedRequestGetsSlot(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{}, 1)
+	e := New()
+	e.Use(MaxInFlight(1, MaxInFlightConfig{QueueSize: 1}))
+	e.GET("/slow", func(c *Context) error {
+		entered <- struct{}{}
+		<-release
+		return c.String(200, "ok")
+	})
+	e.GET("/fast", func(c *Context) error { return c.String(200, "ok") })
+
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow", nil))
+		close(done)
+	}()
+	<-entered
+
+	// Queued with the default QueueTimeout, the request runs once the slot frees
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	if w.Code != 200 {
+		t.Errorf("expected the queued request to get a slot, got %d", w.Code)
+	}
+	<-done
+}
+
+func TestMaxInFlight_QueueTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	entered := make(chan struct{})
+	e := New()
+	e.Use(MaxInFlight(1, MaxInFlightConfig{QueueSize: 1, QueueTimeout: 20 * time.Millisecond}))
+	e.GET("/slow", func(c *Context) error {
+		close(entered)
+		<-release
+		return c.String(200, "ok")
+	})
+	e.GET("/fast", func(c *Context) error { return c.String(200, "ok") })
+
+	go e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow", nil))
+	<-entered
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	if w.Code != 503 {
+		t.Errorf("expected 503 after the queue timeout, got %d", w.Code)
+	}
+}
+
+func TestHMACVerify(t *testing.T) {
+	secret := []byte("s3cret")
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	e := New()
+	e.Use(HMACVerify(HMACConfig{Secret: secret, Header: "X-Hub-Signature-256"}))
+	e.POST("/exec", func(c *Context) error {
+		body, _ := io.ReadAll(c.Request.Body)
+		return c.String(200, string(body))
+	})
+
+	body := `{"tool":"memory"}`
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		status    int
+	}{
+		{"valid", body, sign(body), 200},
+		{"valid with prefix", body, "sha256=" + sign(body), 200},
+		{"tampered body", `{"tool":"shell"}`, sign(body), 401},
+		{"missing header", body, "", 401},
+		{"not hex", body, "xyz", 401},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/exec", strings.NewReader(tt.body))
+		if tt.signature != "" {
+			req.Header.Set("X-Hub-Signature-256", tt.signature)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, w.Code)
+		}
+		// The handler still reads the full body
+		if tt.status == 200 && w.Body.String() != tt.body {
+			t.Errorf("%s: expected handler to read %q, got %q", tt.name, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestHMACVerify_MaxSize(t *testing.T) {
+	e := New()
+	e.Use(HMACVerify(HMACConfig{Secret: []byte("k"), MaxSize: 4}))
+	e.POST("/exec", func(c *Context) error { return c.String(200, "ok") })
+
+	req := httptest.NewRequest("POST", "/exec", strings.NewReader("too long"))
+	req.Header.Set("X-Signature", "00")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+	if w.Code != 413 {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
+
+func TestCaptureBody(t *testing.T) {
+	var seen string
+	e := New()
+	e.Use(CaptureBody(CaptureBodyConfig{MaxSize: 64}))
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			// Reading the body here must not break binding below
+			io.ReadAll(c.Request.Body)
+			body, err := c.Body()
+			if err != nil {
+				return err
+			}
+			seen = string(body)
+			return next(c)
+		}
+	})
+	e.POST("/chat", func(c *Context) error {
+		var req struct {
+			Model string `json:"model"`
+		}
+		if err := c.BindJSON(&req); err != nil {
+			return c.String(400, err.Error())
+		}
+		return c.String(200, req.Model)
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("POST", "/chat", strings.NewReader(`{"model":"gpt-4"}`)))
+	if w.Code != 200 || w.Body.String() != "gpt-4" {
+		t.Errorf("expected handler to bind the body, got %d %q", w.Code, w.Body.String())
+	}
+	if seen != `{"model":"gpt-4"}` {
+		t.Errorf("expected middleware to see the body, got %q", seen)
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("POST", "/chat", strings.NewReader(strings.Repeat("x", 65))))
+	if w.Code != 413 {
+		t.Errorf("expected 413 for a body over the cap, got %d", w.Code)
+	}
+}
+
+func TestHMACVerify_RequiresSecret(t *testing.T) {
+	for _, secret := range [][]byte{nil, {}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for secret %q", secret)
+				}
+			}()
+			HMACVerify(HMACConfig{Secret: secret})
+		}()
+	}
+}
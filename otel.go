@@ -0,0 +1,67 @@
+//go:build otel
+
+package blaze
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTelMiddleware returns a middleware that starts an OpenTelemetry server
+// span for each request, continuing any trace from incoming W3C traceparent
+// headers. The span uses the globally registered TracerProvider and carries
+// HTTP semantic-convention attributes and the response status.
+//
+// This file is only compiled with the "otel" build tag, so the core
+// framework stays free of the OpenTelemetry dependency:
+//
+//	go build -tags otel
+func OTelMiddleware(tracerName string) MiddlewareFunc {
+	tracer := otel.Tracer(tracerName)
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) error {
+			req := c.Request
+			ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Route()
+			if route == "" {
+				route = req.URL.Path
+			}
+
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", route),
+					attribute.String("url.path", req.URL.Path),
+					attribute.String("server.address", req.Host),
+					attribute.String("user_agent.original", req.UserAgent()),
+				),
+			)
+			defer span.End()
+
+			c.Request = req.WithContext(ctx)
+			sw := &statusWriter{ResponseWriter: c.ResponseWriter}
+			c.ResponseWriter = sw
+			err := next(c)
+			c.ResponseWriter = sw.ResponseWriter
+
+			status := responseStatus(sw, err)
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if err != nil {
+				span.RecordError(err)
+			}
+			if status >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
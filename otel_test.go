@@ -0,0 +1,94 @@
+//go:build otel
+
+package blaze
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func withSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func spanAttrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestOTelMiddleware_ServerSpan(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	e := New()
+	e.Use(OTelMiddleware("blaze-test"))
+	e.GET("/users/:id", func(c *Context) error {
+		if !trace.SpanFromContext(c.Request.Context()).SpanContext().IsValid() {
+			t.Error("expected span in request context")
+		}
+		return c.String(200, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "GET /users/:id" {
+		t.Fatalf("unexpected span name %q", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("expected server span, got %v", span.SpanKind())
+	}
+	if span.Parent().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected trace to continue from traceparent, got parent %v", span.Parent().TraceID())
+	}
+
+	attrs := spanAttrs(span)
+	if attrs["http.request.method"].AsString() != "GET" {
+		t.Fatalf("missing method attribute: %v", attrs)
+	}
+	if attrs["http.route"].AsString() != "/users/:id" {
+		t.Fatalf("missing route attribute: %v", attrs)
+	}
+	if attrs["http.response.status_code"].AsInt64() != 200 {
+		t.Fatalf("missing status attribute: %v", attrs)
+	}
+}
+
+func TestOTelMiddleware_ErrorStatus(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	e := New()
+	e.Use(OTelMiddleware("blaze-test"))
+	e.GET("/boom", func(c *Context) error { return errors.New("boom") })
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", span.Status())
+	}
+	if spanAttrs(span)["http.response.status_code"].AsInt64() != 500 {
+		t.Fatal("expected 500 status attribute")
+	}
+}
@@ -2,6 +2,7 @@ package blaze
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -9,14 +10,24 @@ import (
 type node struct {
 	path     string      // path segment (compressed)
 	handler  HandlerFunc // handler if this node is an endpoint
+	route    string      // registered route pattern if this node is an endpoint
 	children []*node     // child nodes (sorted by first char for binary search potential)
 	param    string      // parameter name if this is a :param node
 	wildcard bool        // true if this is a *wildcard node
+	fallback bool        // true if this wildcard is a group's NotFound handler
+}
+
+// routeMethods are the methods a group's NotFound handler is registered for
+var routeMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
+	http.MethodPatch, http.MethodOptions, http.MethodHead,
 }
 
 // Router is a high-performance radix tree based router
 type Router struct {
-	trees map[string]*node // per-method trees for O(1) method lookup
+	trees        map[string]*node // per-method trees for O(1) method lookup
+	errorHandler ErrorHandler     // called when a handler returns an error
+	strictJSON   bool             // BindJSON rejects unknown fields
 }
 
 func newRouter() *Router {
@@ -35,15 +46,44 @@ func (r *Router) handle(method, path string, handler HandlerFunc) {
 
 // insert adds a path to the radix tree
 func (r *Router) insert(root *node, path string, handler HandlerFunc) {
+	route := path
 	path = strings.TrimPrefix(path, "/")
 	if path == "" {
 		root.handler = handler
+		root.route = route
 		return
 	}
 
-	segments := splitPath(path)
-	current := root
+	current := r.walk(root, splitPath(path))
+	current.handler = handler
+	current.route = route
+}
 
+// handleFallback registers handler for paths below prefix that no other
+// route matches, as a wildcard that yields to any other match
+func (r *Router) handleFallback(method, prefix string, handler HandlerFunc) {
+	if r.trees[method] == nil {
+		r.trees[method] = &node{}
+	}
+	parent := r.walk(r.trees[method], splitPath(prefix))
+
+	var fallback *node
+	for _, child := range parent.children {
+		if child.fallback {
+			fallback = child
+		}
+	}
+	if fallback == nil {
+		fallback = &node{path: "*", wildcard: true, fallback: true}
+		parent.children = append(parent.children, fallback)
+	}
+	fallback.handler = handler
+	fallback.route = strings.TrimSuffix(prefix, "/") + "/*"
+}
+
+// walk returns the node for segments below root, creating missing nodes
+func (r *Router) walk(root *node, segments []string) *node {
+	current := root
 	for _, seg := range segments {
 		child := r.findChild(current, seg)
 		if child == nil {
@@ -62,12 +102,15 @@ func (r *Router) insert(root *node, path string, handler HandlerFunc) {
 		}
 		current = child
 	}
-	current.handler = handler
+	return current
 }
 
 // findChild finds a matching child node
 func (r *Router) findChild(n *node, seg string) *node {
 	for _, child := range n.children {
+		if child.fallback {
+			continue
+		}
 		if child.path == seg {
 			return child
 		}
@@ -81,6 +124,16 @@ func (r *Router) findChild(n *node, seg string) *node {
 
 // lookup finds a handler and extracts params
 func (r *Router) lookup(method, path string) (HandlerFunc, map[string]string) {
+	n, params := r.find(method, path)
+	if n == nil {
+		return nil, nil
+	}
+	return n.handler, params
+}
+
+// find returns the endpoint node matching path and the extracted params.
+// path may be percent-encoded; segments and params are matched decoded.
+func (r *Router) find(method, path string) (*node, map[string]string) {
 	root := r.trees[method]
 	if root == nil {
 		return nil, nil
@@ -88,51 +141,67 @@ func (r *Router) lookup(method, path string) (HandlerFunc, map[string]string) {
 
 	path = strings.TrimPrefix(path, "/")
 	if path == "" {
-		return root.handler, map[string]string{}
+		return root, map[string]string{}
 	}
 
 	segments := splitPath(path)
+	for i, seg := range segments {
+		segments[i] = unescapeSegment(seg)
+	}
 	params := make(map[string]string)
-	current := root
+	n := r.match(root, segments, params)
+	if n == nil {
+		return nil, nil
+	}
+	return n, params
+}
 
-	for i, seg := range segments {
-		child := r.matchChild(current, seg, params)
-		if child == nil {
-			return nil, nil
-		}
-		// Wildcard captures rest of path
-		if child.wildcard {
-			params[child.param] = strings.Join(segments[i:], "/")
-			return child.handler, params
+// match returns the endpoint node below n that matches segments, recording
+// params along the way. Static children are tried first, then params, then
+// the wildcard, backtracking whenever a subtree can't match the rest of the
+// path. A wildcard thus only catches paths no more specific route matches.
+func (r *Router) match(n *node, segments []string, params map[string]string) *node {
+	if len(segments) == 0 {
+		if n.handler == nil {
+			return nil
 		}
-		current = child
+		return n
 	}
+	seg, rest := segments[0], segments[1:]
 
-	return current.handler, params
-}
-
-// matchChild finds a child that matches the segment
-func (r *Router) matchChild(n *node, seg string, params map[string]string) *node {
 	// First try exact match (fastest)
 	for _, child := range n.children {
 		if child.path == seg {
-			return child
+			if found := r.match(child, rest, params); found != nil {
+				return found
+			}
 		}
 	}
 	// Then try param match
 	for _, child := range n.children {
 		if child.path == ":" {
 			params[child.param] = seg
-			return child
+			if found := r.match(child, rest, params); found != nil {
+				return found
+			}
+			delete(params, child.param)
 		}
 	}
-	// Finally try wildcard
+	// Finally the wildcard captures the rest of the path, and failing that a
+	// group's NotFound handler
+	var fallback *node
 	for _, child := range n.children {
-		if child.wildcard {
-			return child
+		if !child.wildcard || child.handler == nil {
+			continue
+		}
+		if child.fallback {
+			fallback = child
+			continue
 		}
+		params[child.param] = strings.Join(segments, "/")
+		return child
 	}
-	return nil
+	return fallback
 }
 
 // splitPath splits path into segments
@@ -144,10 +213,24 @@ func splitPath(path string) []string {
 	return strings.Split(path, "/")
 }
 
+// unescapeSegment decodes the percent-encoding of a path segment. Segments
+// are split before decoding, so an escaped slash (%2F) stays within its
+// segment. Malformed escapes are left as they are.
+func unescapeSegment(seg string) string {
+	if !strings.Contains(seg, "%") {
+		return seg
+	}
+	if unescaped, err := url.PathUnescape(seg); err == nil {
+		return unescaped
+	}
+	return seg
+}
+
 // ServeHTTP implements http.Handler
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	handler, params := r.lookup(req.Method, req.URL.Path)
-	if handler == nil {
+	// Match the escaped path, so %2F isn't taken for a separator
+	n, params := r.find(req.Method, req.URL.EscapedPath())
+	if n == nil || n.handler == nil {
 		http.NotFound(w, req)
 		return
 	}
@@ -156,9 +239,15 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		ResponseWriter: w,
 		Request:        req,
 		params:         params,
+		route:          n.route,
+		strictJSON:     r.strictJSON,
 	}
 
-	if err := handler(ctx); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := n.handler(ctx); err != nil {
+		if r.errorHandler != nil {
+			r.errorHandler(ctx, err)
+			return
+		}
+		writeError(w, err)
 	}
 }
@@ -2,8 +2,10 @@ package blaze
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -103,6 +105,147 @@ func TestRouter_Wildcard(t *testing.T) {
 	}
 }
 
+func TestRouter_WildcardFallback(t *testing.T) {
+	r := newRouter()
+	h := func(c *Context) error { return nil }
+	r.handle("GET", "/files/*filepath", h)
+	r.handle("GET", "/files/special/thing", h)
+	r.handle("GET", "/files/:dir/readme", h)
+
+	tests := []struct {
+		path   string
+		route  string
+		params map[string]string
+	}{
+		{"/files/special/thing", "/files/special/thing", map[string]string{}},
+		{"/files/anything/else", "/files/*filepath", map[string]string{"filepath": "anything/else"}},
+		// The static subtree doesn't match, so the param route gets the path
+		{"/files/special/readme", "/files/:dir/readme", map[string]string{"dir": "special"}},
+		// Neither does the param subtree, which mustn't leave its param behind
+		{"/files/special/thing/more", "/files/*filepath", map[string]string{"filepath": "special/thing/more"}},
+		{"/files/special", "/files/*filepath", map[string]string{"filepath": "special"}},
+	}
+	for _, tt := range tests {
+		n, params := r.find("GET", tt.path)
+		if n == nil {
+			t.Errorf("%s: no route found", tt.path)
+			continue
+		}
+		if n.route != tt.route {
+			t.Errorf("%s: expected route %s, got %s", tt.path, tt.route, n.route)
+		}
+		if !maps.Equal(params, tt.params) {
+			t.Errorf("%s: expected params %v, got %v", tt.path, tt.params, params)
+		}
+	}
+}
+
+func TestRouter_EscapedPath(t *testing.T) {
+	e := New()
+	e.GET("/users/:name", func(c *Context) error {
+		return c.String(200, c.Param("name"))
+	})
+	e.GET("/users/:name/posts", func(c *Context) error {
+		return c.String(200, "posts of "+c.Param("name"))
+	})
+	e.GET("/files/*filepath", func(c *Context) error {
+		return c.String(200, c.Param("filepath"))
+	})
+	e.GET("/static/a b", func(c *Context) error {
+		return c.String(200, "literal")
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/john%20doe", "john doe"},
+		// An escaped slash stays within its segment
+		{"/users/a%2Fb", "a/b"},
+		{"/users/a%2Fb/posts", "posts of a/b"},
+		{"/files/dir/my%20file.txt", "dir/my file.txt"},
+		{"/static/a%20b", "literal"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != tt.want {
+			t.Errorf("%s: expected 200 %q, got %d %q", tt.path, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestGroup_NotFound(t *testing.T) {
+	e := New()
+	api := e.Group("/api")
+	api.GET("/users/:id", func(c *Context) error {
+		return c.String(200, "user "+c.Param("id"))
+	})
+	api.GET("/files/*filepath", func(c *Context) error {
+		return c.String(200, "file "+c.Param("filepath"))
+	})
+	api.NotFound(func(c *Context) error {
+		return c.JSON(404, map[string]string{"error": "no such endpoint"})
+	})
+	e.GET("/other/ok", func(c *Context) error { return c.String(200, "ok") })
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		want   string
+	}{
+		{"GET", "/api/users/7", 200, "user 7"},
+		{"GET", "/api/files/a/b", 200, "file a/b"},
+		{"GET", "/api/nope", 404, `{"error":"no such endpoint"}`},
+		{"GET", "/api/users/7/more", 404, `{"error":"no such endpoint"}`},
+		{"DELETE", "/api/users/7", 404, `{"error":"no such endpoint"}`},
+		{"GET", "/other/nope", 404, "404 page not found"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.status || strings.TrimSpace(rec.Body.String()) != tt.want {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.status, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestEngine_Mount(t *testing.T) {
+	admin := New()
+	admin.GET("/", func(c *Context) error { return c.String(200, "admin home") })
+	admin.GET("/users/:id", func(c *Context) error {
+		return c.String(200, "admin user "+c.Param("id")+" at "+c.Request.URL.Path)
+	})
+
+	e := New()
+	e.Mount("/admin", admin)
+	e.Mount("/raw/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("raw " + r.URL.Path))
+	}))
+	e.GET("/admin/status", func(c *Context) error { return c.String(200, "main status") })
+
+	tests := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/admin", 200, "admin home"},
+		{"/admin/users/7", 200, "admin user 7 at /users/7"},
+		{"/admin/status", 200, "main status"},
+		{"/admin/nope", 404, "404 page not found"},
+		{"/raw/a/b", 200, "raw /a/b"},
+		{"/other", 404, "404 page not found"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status || strings.TrimSpace(rec.Body.String()) != tt.want {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.path, tt.status, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
 func TestRouter_Methods(t *testing.T) {
 	r := newRouter()
 	r.handle("GET", "/resource", func(c *Context) error { return nil })
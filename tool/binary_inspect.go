@@ -0,0 +1,103 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	_ "image/gif"  // registers GIF for image.DecodeConfig
+	_ "image/jpeg" // registers JPEG for image.DecodeConfig
+	_ "image/png"  // registers PNG for image.DecodeConfig
+	"net/http"
+	"strings"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// binaryInspectSchema is the input schema for binary_inspect
+var binaryInspectSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"data": map[string]any{
+			"type":        "string",
+			"description": "Base64 content, or a data URI such as 'data:image/png;base64,iVBOR...'",
+		},
+	},
+	"required": []string{"data"},
+}
+
+// NewBinaryInspectTool creates a tool that describes base64 content without
+// returning it. It can:
+// - Decode plain base64 (standard or URL alphabet, padded or not) and data URIs
+// - Detect the MIME type by content sniffing
+// - Report the width and height of PNG, JPEG and GIF images from their headers
+func NewBinaryInspectTool() adapter.Tool {
+	return adapter.NewTool(
+		"binary_inspect",
+		"Inspect base64 data or a data URI: returns the detected MIME type, size in bytes, and for PNG/JPEG/GIF images the width and height. The decoded bytes are not returned.",
+		binaryInspectSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Data string `json:"data"`
+			}
+			if err := BindInput(input, &data, binaryInspectSchema); err != nil {
+				return nil, err
+			}
+
+			payload, declared, err := splitDataURI(strings.TrimSpace(data.Data))
+			if err != nil {
+				return nil, err
+			}
+			raw, err := decodeBase64(payload)
+			if err != nil {
+				return nil, err
+			}
+
+			result := map[string]any{
+				"mime": http.DetectContentType(raw),
+				"size": len(raw),
+			}
+			if declared != "" {
+				result["declared_mime"] = declared
+			}
+			if cfg, format, err := image.DecodeConfig(bytes.NewReader(raw)); err == nil {
+				result["format"] = format
+				result["width"] = cfg.Width
+				result["height"] = cfg.Height
+			}
+			return result, nil
+		},
+	)
+}
+
+// splitDataURI returns the base64 payload of s and, when s is a data URI,
+// its declared media type. Other strings are returned unchanged.
+func splitDataURI(s string) (payload, mediaType string, err error) {
+	if !strings.HasPrefix(strings.ToLower(s), "data:") {
+		return s, "", nil
+	}
+	header, payload, ok := strings.Cut(s[len("data:"):], ",")
+	if !ok {
+		return "", "", InvalidInput("invalid data URI: missing ','")
+	}
+	params := strings.Split(header, ";")
+	if !strings.EqualFold(params[len(params)-1], "base64") {
+		return "", "", InvalidInput("data URI is not base64-encoded")
+	}
+	return payload, strings.TrimSpace(params[0]), nil
+}
+
+// decodeBase64 decodes s in the standard or URL alphabet, with or without
+// padding. Whitespace such as line breaks is ignored.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		s = strings.NewReplacer("-", "+", "_", "/").Replace(s)
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, InvalidInput("invalid base64: %w", err)
+	}
+	return raw, nil
+}
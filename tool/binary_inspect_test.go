@@ -0,0 +1,71 @@
+package tool
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// tinyPNG is a 3x2 grayscale PNG
+const tinyPNG = "iVBORw0KGgoAAAANSUhEUgAAAAMAAAACCAAAAAC4HznGAAAAFUlEQVR4nAAIAPf/AgAAAAIAAAADAAAgAAU+b2tZAAAAAElFTkSuQmCC"
+
+// runBinaryInspect calls the binary_inspect tool with data
+func runBinaryInspect(t *testing.T, data string) (map[string]any, error) {
+	t.Helper()
+	raw, _ := json.Marshal(map[string]any{"data": data})
+	out, err := NewBinaryInspectTool().Handler(raw)
+	if err != nil {
+		return nil, err
+	}
+	return out.(map[string]any), nil
+}
+
+func TestBinaryInspect_PNG(t *testing.T) {
+	got, err := runBinaryInspect(t, tinyPNG)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["mime"] != "image/png" || got["format"] != "png" {
+		t.Errorf("expected image/png, got %v", got)
+	}
+	if got["width"] != 3 || got["height"] != 2 {
+		t.Errorf("expected 3x2, got %vx%v", got["width"], got["height"])
+	}
+	if got["size"] != 78 {
+		t.Errorf("expected 78 bytes, got %v", got["size"])
+	}
+}
+
+func TestBinaryInspect_DataURI(t *testing.T) {
+	gif := "data:image/gif;base64,R0lGODlhAQABAIAAAAAAAAAAACwAAAAAAQABAAACAkQBADs="
+	got, err := runBinaryInspect(t, gif)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["mime"] != "image/gif" || got["declared_mime"] != "image/gif" {
+		t.Errorf("expected image/gif, got %v", got)
+	}
+	if got["width"] != 1 || got["height"] != 1 {
+		t.Errorf("expected 1x1, got %vx%v", got["width"], got["height"])
+	}
+
+	// Non-images have no dimensions; unpadded URL-safe base64 is accepted
+	text, err := runBinaryInspect(t, "aGVsbG8_IHdvcmxk")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(text["mime"].(string), "text/plain") || text["size"] != 12 {
+		t.Errorf("expected 12 bytes of text, got %v", text)
+	}
+	if _, ok := text["width"]; ok {
+		t.Errorf("expected no width for text, got %v", text)
+	}
+}
+
+func TestBinaryInspect_Invalid(t *testing.T) {
+	for _, data := range []string{"not base64!", "data:text/plain,hello", "data:image/png;base64"} {
+		if _, err := runBinaryInspect(t, data); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
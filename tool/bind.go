@@ -0,0 +1,110 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// BindInput unmarshals a tool's raw input into v. When the tool's JSON Schema
+// is passed, it also:
+// - Reports the first missing required field by name
+// - Coerces numeric strings ("5") to numbers for integer/number properties
+func BindInput(raw json.RawMessage, v any, schema ...any) error {
+	if len(schema) == 0 || schema[0] == nil {
+		if err := json.Unmarshal(raw, v); err != nil {
+			return InvalidInput("invalid input: %w", err)
+		}
+		return nil
+	}
+
+	// Numbers stay json.Number, so they reach v exactly as sent
+	var fields map[string]any
+	if err := unmarshalNumbers(raw, &fields); err != nil {
+		return InvalidInput("invalid input: %w", err)
+	}
+	if fields == nil {
+		fields = map[string]any{}
+	}
+
+	props, required, err := schemaFields(schema[0])
+	if err != nil {
+		return err
+	}
+
+	for _, name := range required {
+		if val, ok := fields[name]; !ok || val == nil {
+			return InvalidInput("missing required field '%s'", name)
+		}
+	}
+
+	for name, val := range fields {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		prop, _ := props[name].(map[string]any)
+		switch prop["type"] {
+		case "integer":
+			n, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+			if err != nil {
+				return InvalidInput("field '%s' must be an integer, got %q", name, str)
+			}
+			fields[name] = n
+		case "number":
+			f, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+			if err != nil {
+				return InvalidInput("field '%s' must be a number, got %q", name, str)
+			}
+			fields[name] = f
+		}
+	}
+
+	coerced, _ := json.Marshal(fields)
+	if err := json.Unmarshal(coerced, v); err != nil {
+		return InvalidInput("invalid input: %w", err)
+	}
+	return nil
+}
+
+// BindInputNumbers is like BindInput without a schema, but numbers decoded
+// into any-typed fields arrive as json.Number instead of float64, so large
+// integers keep every digit. blaze.JSONInt64 and blaze.JSONFloat64 read them.
+func BindInputNumbers(raw json.RawMessage, v any) error {
+	if err := unmarshalNumbers(raw, v); err != nil {
+		return InvalidInput("invalid input: %w", err)
+	}
+	return nil
+}
+
+// unmarshalNumbers is json.Unmarshal decoding numbers as json.Number
+func unmarshalNumbers(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected content after the value")
+	}
+	return nil
+}
+
+// schemaFields extracts the properties and required list from a JSON Schema
+// given as any JSON-serializable value
+func schemaFields(schema any) (map[string]any, []string, error) {
+	schemaBytes, err := json.Marshal(schema)
+	if err != nil {
+		return nil, nil, Internal("invalid schema: %w", err)
+	}
+	var s struct {
+		Properties map[string]any `json:"properties"`
+		Required   []string       `json:"required"`
+	}
+	if err := json.Unmarshal(schemaBytes, &s); err != nil {
+		return nil, nil, Internal("invalid schema: %w", err)
+	}
+	return s.Properties, s.Required, nil
+}
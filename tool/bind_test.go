@@ -0,0 +1,76 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var bindSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"query":       map[string]any{"type": "string"},
+		"max_results": map[string]any{"type": "integer"},
+		"threshold":   map[string]any{"type": "number"},
+	},
+	"required": []string{"query"},
+}
+
+type bindTarget struct {
+	Query      string  `json:"query"`
+	MaxResults int     `json:"max_results"`
+	Threshold  float64 `json:"threshold"`
+}
+
+func TestBindInput_MissingRequired(t *testing.T) {
+	var data bindTarget
+	err := BindInput(json.RawMessage(`{"max_results": 3}`), &data, bindSchema)
+	if err == nil || !strings.Contains(err.Error(), "missing required field 'query'") {
+		t.Fatalf("expected missing field error, got %v", err)
+	}
+	var te *Error
+	if !errors.As(err, &te) || te.Kind != KindInvalidInput {
+		t.Errorf("expected an %s error, got %#v", KindInvalidInput, err)
+	}
+}
+
+func TestBindInput_CoercesNumericStrings(t *testing.T) {
+	var data bindTarget
+	err := BindInput(json.RawMessage(`{"query": "go", "max_results": "7", "threshold": "0.5"}`), &data, bindSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.MaxResults != 7 || data.Threshold != 0.5 {
+		t.Fatalf("expected coerced numbers, got %+v", data)
+	}
+
+	err = BindInput(json.RawMessage(`{"query": "go", "max_results": "seven"}`), &data, bindSchema)
+	if err == nil || !strings.Contains(err.Error(), "must be an integer") {
+		t.Fatalf("expected integer error, got %v", err)
+	}
+}
+
+func TestBindInput_WithoutSchema(t *testing.T) {
+	var data bindTarget
+	if err := BindInput(json.RawMessage(`{"query": "go"}`), &data); err != nil || data.Query != "go" {
+		t.Fatalf("expected plain unmarshal, got %+v, %v", data, err)
+	}
+	if err := BindInput(json.RawMessage(`{`), &data); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+func TestBindInput_KeepsLargeIntegers(t *testing.T) {
+	var data struct {
+		Query string `json:"query"`
+		ID    int64  `json:"id"`
+	}
+	err := BindInput(json.RawMessage(`{"query": "go", "id": 9007199254740993}`), &data, bindSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.ID != 9007199254740993 {
+		t.Errorf("expected the integer unchanged, got %d", data.ID)
+	}
+}
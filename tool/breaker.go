@@ -0,0 +1,170 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped, for requests to a host whose circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitBreakerConfig configures a CircuitBreaker
+type CircuitBreakerConfig struct {
+	Threshold int           // consecutive failures that open the circuit (0 = never open)
+	Cooldown  time.Duration // how long an open circuit rejects requests before probing
+}
+
+// DefaultCircuitBreakerConfig provides sensible defaults
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+	}
+}
+
+// CircuitBreaker tracks failures per host. After Threshold consecutive
+// failures (transport errors, 429 and 5xx responses) a host's circuit
+// opens: requests to it fail at once with ErrCircuitOpen for Cooldown.
+// Then it half-opens and lets a single probe through; the probe's outcome
+// closes the circuit or opens it for another Cooldown.
+type CircuitBreaker struct {
+	mu    sync.Mutex
+	cfg   CircuitBreakerConfig
+	hosts map[string]*breakerState
+	now   func() time.Time
+}
+
+// breakerState is the circuit of one host
+type breakerState struct {
+	failures  int       // consecutive failures
+	openUntil time.Time // end of the cooldown once open
+	probing   bool      // a half-open probe is in flight
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with all circuits closed
+func NewCircuitBreaker(config ...CircuitBreakerConfig) *CircuitBreaker {
+	cfg := DefaultCircuitBreakerConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return &CircuitBreaker{cfg: cfg, hosts: make(map[string]*breakerState), now: time.Now}
+}
+
+// webBreaker is shared by the web tools' HTTP clients
+var webBreaker = NewCircuitBreaker()
+
+// WebCircuitBreaker returns the breaker used by web_fetch, web_read and
+// web_search
+func WebCircuitBreaker() *CircuitBreaker {
+	return webBreaker
+}
+
+// SetConfig replaces the breaker's thresholds and closes all circuits
+func (b *CircuitBreaker) SetConfig(config CircuitBreakerConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = config
+	b.hosts = make(map[string]*breakerState)
+}
+
+// State returns "closed", "open" or "half-open" for host
+func (b *CircuitBreaker) State(host string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.hosts[strings.ToLower(host)]
+	switch {
+	case s == nil || !b.tripped(s):
+		return "closed"
+	case s.probing || !b.now().Before(s.openUntil):
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// Transport wraps next, which defaults to http.DefaultTransport, so that
+// requests pass through the breaker
+func (b *CircuitBreaker) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &breakerTransport{breaker: b, next: next}
+}
+
+// allow reports whether a request to host may go out, claiming the probe
+// when the circuit is half-open
+func (b *CircuitBreaker) allow(host string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.hosts[host]
+	if s == nil || !b.tripped(s) {
+		return nil
+	}
+	if now := b.now(); now.Before(s.openUntil) || s.probing {
+		wait := max(s.openUntil.Sub(now), 0).Round(time.Second)
+		return fmt.Errorf("%w for %s after %d consecutive failures, retry in %s", ErrCircuitOpen, host, s.failures, wait)
+	}
+	s.probing = true
+	return nil
+}
+
+// record notes the outcome of a request to host
+func (b *CircuitBreaker) record(host string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		delete(b.hosts, host)
+		return
+	}
+	s := b.hosts[host]
+	if s == nil {
+		s = &breakerState{}
+		b.hosts[host] = s
+	}
+	s.probing = false
+	s.failures++
+	if b.tripped(s) {
+		s.openUntil = b.now().Add(b.cfg.Cooldown)
+	}
+}
+
+// release gives up a probe claimed by allow without recording an outcome
+func (b *CircuitBreaker) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s := b.hosts[host]; s != nil {
+		s.probing = false
+	}
+}
+
+// tripped reports whether s has failed often enough to be open. Callers
+// must hold the lock.
+func (b *CircuitBreaker) tripped(s *breakerState) bool {
+	return b.cfg.Threshold > 0 && s.failures >= b.cfg.Threshold
+}
+
+// breakerTransport is an http.RoundTripper guarded by a CircuitBreaker
+type breakerTransport struct {
+	breaker *CircuitBreaker
+	next    http.RoundTripper
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Host)
+	if err := t.breaker.allow(host); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil && req.Context().Err() != nil {
+		// Cancelled by the caller, which says nothing about the host
+		t.breaker.release(host)
+		return resp, err
+	}
+	t.breaker.record(host, err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500)
+	return resp, err
+}
@@ -0,0 +1,101 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+func TestCircuitBreaker_OpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	b := WebCircuitBreaker()
+	b.SetConfig(CircuitBreakerConfig{Threshold: 3, Cooldown: time.Minute})
+	defer b.SetConfig(DefaultCircuitBreakerConfig())
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	defer func() { b.now = time.Now }()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	fetch := NewWebFetchTool()
+	input, _ := json.Marshal(map[string]string{"url": srv.URL})
+
+	// Failing responses are returned as usual until the threshold
+	for range 3 {
+		if _, err := fetch.Handler(input); err != nil {
+			t.Fatalf("expected the 503 to be returned, got %v", err)
+		}
+	}
+	if state := b.State(host); state != "open" {
+		t.Fatalf("expected open circuit after 3 failures, got %s", state)
+	}
+
+	// While open, requests fail without reaching the server
+	_, err := fetch.Handler(input)
+	if !errors.Is(err, ErrCircuitOpen) || adapter.ErrorKindOf(err) != KindUpstream {
+		t.Fatalf("expected a circuit open upstream error, got %v", err)
+	}
+	if hits.Load() != 3 {
+		t.Errorf("expected 3 requests to reach the server, got %d", hits.Load())
+	}
+
+	// After the cooldown a probe goes through and closes the circuit
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	if state := b.State(host); state != "half-open" {
+		t.Fatalf("expected half-open circuit after the cooldown, got %s", state)
+	}
+	out, err := fetch.Handler(input)
+	if err != nil || out.(map[string]any)["body"] != "ok" {
+		t.Fatalf("expected the probe to succeed, got %v, %v", out, err)
+	}
+	if state := b.State(host); state != "closed" {
+		t.Errorf("expected closed circuit after a successful probe, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(CircuitBreakerConfig{Threshold: 2, Cooldown: time.Second})
+	b.now = func() time.Time { return now }
+
+	b.record("example.com", false)
+	b.record("example.com", false)
+	if err := b.allow("example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := b.allow("example.com"); err != nil {
+		t.Fatalf("expected a probe after the cooldown, got %v", err)
+	}
+	// Only one probe at a time
+	if err := b.allow("example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a second request to wait for the probe, got %v", err)
+	}
+
+	b.record("example.com", false)
+	if state := b.State("example.com"); state != "open" {
+		t.Errorf("expected a failed probe to reopen the circuit, got %s", state)
+	}
+	if err := b.allow("other.com"); err != nil {
+		t.Errorf("expected other hosts to be unaffected, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package tool
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// latin1 encodes s (which must only contain runes below U+0100) as ISO-8859-1
+func latin1(s string) []byte {
+	var out []byte
+	for _, r := range s {
+		out = append(out, byte(r))
+	}
+	return out
+}
+
+func TestWebRead_Latin1Page(t *testing.T) {
+	page := "<html><head><title>Café crème</title></head><body><article><p>Déjà vu à Zürich, señor.</p></article></body></html>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+		w.Write(latin1(page))
+	}))
+	defer srv.Close()
+
+	input, _ := json.Marshal(map[string]string{"url": srv.URL})
+	out, err := NewWebReadTool().Handler(input)
+	if err != nil {
+		t.Fatalf("web_read failed: %v", err)
+	}
+	result := out.(map[string]any)
+
+	if result["title"] != "Café crème" {
+		t.Errorf("expected UTF-8 title, got %q", result["title"])
+	}
+	if content := result["content"].(string); !strings.Contains(content, "Déjà vu à Zürich, señor.") {
+		t.Errorf("expected UTF-8 content, got %q", content)
+	}
+	if result["charset"] != "windows-1252" {
+		t.Errorf("expected windows-1252 charset, got %v", result["charset"])
+	}
+}
+
+func TestWebRead_ShiftJISPage(t *testing.T) {
+	page, err := os.ReadFile("testdata/shift_jis.html")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(page)
+	}))
+	defer srv.Close()
+
+	input, _ := json.Marshal(map[string]string{"url": srv.URL})
+	out, err := NewWebReadTool().Handler(input)
+	if err != nil {
+		t.Fatalf("web_read failed: %v", err)
+	}
+	result := out.(map[string]any)
+
+	if result["title"] != "東京の天気" {
+		t.Errorf("expected UTF-8 title, got %q", result["title"])
+	}
+	if content := result["content"].(string); !strings.Contains(content, "今日は晴れ、最高気温は２５度です。ｶﾀｶﾅ ～ ①") {
+		t.Errorf("expected UTF-8 content, got %q", content)
+	}
+	if result["charset"] != "shift_jis" {
+		t.Errorf("expected shift_jis charset, got %v", result["charset"])
+	}
+}
+
+func TestWebFetch_MetaCharset(t *testing.T) {
+	page := `<html><head><meta http-equiv="Content-Type" content="text/html; charset=windows-1251"></head><body>` +
+		"\xcf\xf0\xe8\xe2\xe5\xf2</body></html>" // "Привет" in Windows-1251
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	input, _ := json.Marshal(map[string]string{"url": srv.URL})
+	out, err := NewWebFetchTool().Handler(input)
+	if err != nil {
+		t.Fatalf("web_fetch failed: %v", err)
+	}
+	result := out.(map[string]any)
+	if body := result["body"].(string); !strings.Contains(body, "Привет") {
+		t.Errorf("expected decoded Cyrillic body, got %q", body)
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		want        string
+		charset     string
+	}{
+		{"utf-8 default", []byte("héllo"), "text/html", "héllo", "utf-8"},
+		{"utf-8 bom", []byte("\xef\xbb\xbfhi"), "", "hi", "utf-8"},
+		{"utf-16le bom", []byte{0xff, 0xfe, 'h', 0, 0xe9, 0}, "", "hé", "utf-16le"},
+		{"bom beats header", []byte("\xef\xbb\xbfé"), "text/html; charset=iso-8859-1", "é", "utf-8"},
+		{"header beats meta", latin1(`<meta charset="koi8-r">é`), "text/html; charset=latin1", `<meta charset="koi8-r">é`, "windows-1252"},
+		{"meta charset", []byte(`<meta charset="KOI8-R">` + "\xf0\xd2\xc9\xd7\xc5\xd4"), "", `<meta charset="KOI8-R">Привет`, "koi8-r"},
+		{"unknown falls back", []byte("plain"), "text/html; charset=x-unknown", "plain", "utf-8"},
+		{"shift_jis", []byte("\x93\xfa\x96\x7b a\xb1"), "text/plain; charset=Shift_JIS", "日本 aｱ", "shift_jis"},
+		{"shift_jis invalid", []byte("\x81 a\xa0\x85\x40\x88"), "text/plain; charset=sjis", "\ufffd a\ufffd\ufffd@\ufffd", "shift_jis"},
+		{"windows-1252 extras", []byte{0x80, 0x93, 'x', 0x94}, "text/plain; charset=windows-1252", "€“x”", "windows-1252"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, charset := decodeBody(tt.body, tt.contentType)
+			if got != tt.want || charset != tt.charset {
+				t.Errorf("decodeBody() = %q, %q; want %q, %q", got, charset, tt.want, tt.charset)
+			}
+		})
+	}
+}
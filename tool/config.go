@@ -0,0 +1,77 @@
+package tool
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// redacted replaces the value of secret config keys
+const redacted = "***"
+
+// configSchema is the input schema for config
+var configSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"keys": map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "Config keys to look up. Omit to list the available keys",
+		},
+	},
+}
+
+// NewConfigTool creates a tool that exposes a fixed set of configuration
+// values, e.g. a deployment region or feature flags. Only the entries of
+// values are visible; the process environment is never read. Keys listed
+// in secrets can be seen to exist, but their values read as "***".
+//
+// values is copied, so later changes to the map are not seen by the tool.
+func NewConfigTool(values map[string]string, secrets []string) adapter.Tool {
+	values = maps.Clone(values)
+	secret := map[string]bool{}
+	for _, k := range secrets {
+		secret[k] = true
+	}
+
+	return adapter.NewTool(
+		"config",
+		"Read application configuration values by key. Omit keys to list the available keys. Secret values are shown as ***.",
+		configSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Keys []string `json:"keys"`
+			}
+			if err := BindInput(input, &data, configSchema); err != nil {
+				return nil, err
+			}
+
+			if len(data.Keys) == 0 {
+				return map[string]any{
+					"keys": slices.Sorted(maps.Keys(values)),
+				}, nil
+			}
+
+			found := map[string]string{}
+			notFound := []string{}
+			for _, k := range data.Keys {
+				v, ok := values[k]
+				switch {
+				case !ok:
+					notFound = append(notFound, k)
+				case secret[k]:
+					found[k] = redacted
+				default:
+					found[k] = v
+				}
+			}
+
+			return map[string]any{
+				"values":    found,
+				"not_found": notFound,
+			}, nil
+		},
+	)
+}
@@ -0,0 +1,35 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_Lookup(t *testing.T) {
+	tool := NewConfigTool(map[string]string{
+		"region":       "eu-west-1",
+		"database_url": "postgres://user:hunter2@db/app",
+	}, []string{"database_url"})
+
+	got := runTool(t, tool, map[string]any{"keys": []string{"region", "database_url", "HOME"}})
+
+	wantValues := map[string]string{"region": "eu-west-1", "database_url": "***"}
+	if !reflect.DeepEqual(got["values"], wantValues) {
+		t.Errorf("expected %v, got %v", wantValues, got["values"])
+	}
+	// Unknown keys are reported, and the environment is never consulted
+	if !reflect.DeepEqual(got["not_found"], []string{"HOME"}) {
+		t.Errorf("expected HOME not found, got %v", got["not_found"])
+	}
+}
+
+func TestConfig_ListKeys(t *testing.T) {
+	values := map[string]string{"b": "2", "a": "1"}
+	tool := NewConfigTool(values, nil)
+	values["c"] = "3"
+
+	got := runTool(t, tool, map[string]any{})
+	if !reflect.DeepEqual(got["keys"], []string{"a", "b"}) {
+		t.Errorf("expected keys [a b], got %v", got["keys"])
+	}
+}
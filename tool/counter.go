@@ -0,0 +1,88 @@
+package tool
+
+import (
+	"encoding/json"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// NewCounterTool creates a tool for bounded counters, e.g. to track rates or
+// budgets across tool calls. Counters live in the same store as the memory
+// tool, so "memory get" can read them.
+// Supports:
+// - incr/decr by a configurable step
+// - Optional min/max clamps, reporting when a value was clamped
+// - reset to a value (default 0)
+func NewCounterTool() adapter.Tool {
+	t := adapter.NewTool(
+		"counter",
+		"Increment, decrement, read, or reset a named counter. Optional min/max bounds clamp the value and report when clamping happened. Use this to track rates, attempts, or budgets across tool calls.",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{
+					"type":        "string",
+					"enum":        []string{"incr", "decr", "get", "reset"},
+					"description": "Action: 'incr'/'decr' by step, 'get' the current value, 'reset' to value",
+				},
+				"key": map[string]any{
+					"type":        "string",
+					"description": "Counter name",
+				},
+				"step": map[string]any{
+					"type":        "integer",
+					"description": "Amount to add or subtract (default: 1)",
+				},
+				"min": map[string]any{
+					"type":        "integer",
+					"description": "Lower bound; results below it are clamped",
+				},
+				"max": map[string]any{
+					"type":        "integer",
+					"description": "Upper bound; results above it are clamped",
+				},
+				"value": map[string]any{
+					"type":        "integer",
+					"description": "Value for reset (default: 0)",
+				},
+			},
+			"required": []string{"action", "key"},
+		},
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Action string `json:"action"`
+				Key    string `json:"key"`
+				Step   *int   `json:"step"`
+				Min    *int   `json:"min"`
+				Max    *int   `json:"max"`
+				Value  int    `json:"value"`
+			}
+			if err := json.Unmarshal(input, &data); err != nil {
+				return nil, InvalidInput("invalid input: %w", err)
+			}
+
+			if data.Key == "" {
+				return nil, InvalidInput("key is required")
+			}
+			step := 1
+			if data.Step != nil {
+				step = *data.Step
+			}
+
+			switch data.Action {
+			case "incr":
+				return globalMemory.IncrBounded(data.Key, step, data.Min, data.Max)
+			case "decr":
+				return globalMemory.IncrBounded(data.Key, -step, data.Min, data.Max)
+			case "get":
+				return globalMemory.Counter(data.Key)
+			case "reset":
+				return globalMemory.ResetCounter(data.Key, data.Value)
+			default:
+				return nil, InvalidInput("unknown action: %s", data.Action)
+			}
+		},
+	)
+	t.SideEffect = true // writes to the shared store
+	return t
+}
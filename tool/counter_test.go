@@ -0,0 +1,68 @@
+package tool
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestCounter_Step(t *testing.T) {
+	key := "counter_test_step"
+	defer Memory().Delete(key)
+
+	runTool(t, NewCounterTool(), map[string]any{"action": "incr", "key": key, "step": 5})
+	got := runTool(t, NewCounterTool(), map[string]any{"action": "incr", "key": key, "step": 5})
+	if got["previous"] != 5 || got["current"] != 10 || got["clamped"] != false {
+		t.Errorf("expected 5 -> 10 unclamped, got %v", got)
+	}
+
+	got = runTool(t, NewCounterTool(), map[string]any{"action": "decr", "key": key, "step": 3})
+	if got["current"] != 7 {
+		t.Errorf("expected 7 after decr by 3, got %v", got["current"])
+	}
+	if got := runTool(t, NewCounterTool(), map[string]any{"action": "get", "key": key}); got["current"] != 7 {
+		t.Errorf("expected get to return 7, got %v", got["current"])
+	}
+}
+
+func TestCounter_ClampAtMax(t *testing.T) {
+	key := "counter_test_clamp"
+	defer Memory().Delete(key)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			raw, _ := json.Marshal(map[string]any{"action": "incr", "key": key, "max": 10})
+			NewCounterTool().Handler(raw)
+		}()
+	}
+	wg.Wait()
+
+	got := runTool(t, NewCounterTool(), map[string]any{"action": "incr", "key": key, "max": 10})
+	if got["current"] != 10 || got["clamped"] != true {
+		t.Errorf("expected clamped at 10, got %v", got)
+	}
+
+	got = runTool(t, NewCounterTool(), map[string]any{"action": "decr", "key": key, "step": 50, "min": 0})
+	if got["current"] != 0 || got["clamped"] != true {
+		t.Errorf("expected clamped at 0, got %v", got)
+	}
+}
+
+func TestCounter_Reset(t *testing.T) {
+	key := "counter_test_reset"
+	defer Memory().Delete(key)
+
+	runTool(t, NewCounterTool(), map[string]any{"action": "incr", "key": key, "step": 42})
+	got := runTool(t, NewCounterTool(), map[string]any{"action": "reset", "key": key})
+	if got["previous"] != 42 || got["current"] != 0 {
+		t.Errorf("expected 42 -> 0, got %v", got)
+	}
+
+	got = runTool(t, NewCounterTool(), map[string]any{"action": "reset", "key": key, "value": 3})
+	if got["current"] != 3 {
+		t.Errorf("expected reset to 3, got %v", got["current"])
+	}
+}
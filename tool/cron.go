@@ -0,0 +1,483 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// cronSchema is the input schema for cron
+var cronSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"action": map[string]any{
+			"type":        "string",
+			"enum":        []string{"next", "describe"},
+			"description": "Action: 'next' lists the next fire times after from, 'describe' explains the schedule in words",
+		},
+		"expression": map[string]any{
+			"type":        "string",
+			"description": "Standard 5-field cron expression: minute hour day-of-month month day-of-week (e.g., '0 3 * * *', '*/15 9-17 * * MON-FRI'), or @hourly, @daily, @weekly, @monthly, @yearly",
+		},
+		"from": map[string]any{
+			"type":        "string",
+			"description": "Base time for next, YYYY-MM-DD or RFC 3339 (default: now)",
+		},
+		"count": map[string]any{
+			"type":        "integer",
+			"description": "Number of fire times for next (default: 5, max: 100)",
+		},
+		"timezone": map[string]any{
+			"type":        "string",
+			"description": "Timezone the schedule runs in (e.g., 'America/New_York'). Default: UTC",
+		},
+	},
+	"required": []string{"action", "expression"},
+}
+
+// NewCronTool creates a tool for understanding cron schedules.
+// It can:
+// - List the next fire times of an expression in any timezone
+// - Describe an expression in words ("every day at 3:00 AM")
+func NewCronTool() adapter.Tool {
+	return adapter.NewTool(
+		"cron",
+		"Work with cron expressions. Get the next times a schedule fires, or a human-readable description of it. Use this to check or explain scheduled jobs.",
+		cronSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Action     string `json:"action"`
+				Expression string `json:"expression"`
+				From       string `json:"from"`
+				Count      int    `json:"count"`
+				Timezone   string `json:"timezone"`
+			}
+			if err := BindInput(input, &data, cronSchema); err != nil {
+				return nil, err
+			}
+
+			expr, err := parseCron(data.Expression)
+			if err != nil {
+				return nil, err
+			}
+
+			switch data.Action {
+			case "next":
+				if data.Timezone == "" {
+					data.Timezone = "UTC"
+				}
+				loc, err := time.LoadLocation(data.Timezone)
+				if err != nil {
+					return nil, InvalidInput("invalid timezone '%s': %w", data.Timezone, err)
+				}
+				from := time.Now().In(loc)
+				if data.From != "" {
+					if from, _, err = parseDateArg(data.From, loc); err != nil {
+						return nil, err
+					}
+				}
+				if data.Count <= 0 {
+					data.Count = 5
+				}
+				if data.Count > 100 {
+					data.Count = 100
+				}
+
+				times, err := expr.nextN(from.In(loc), data.Count)
+				if err != nil {
+					return nil, err
+				}
+				next := make([]string, len(times))
+				for i, t := range times {
+					next[i] = t.Format(time.RFC3339)
+				}
+				return map[string]any{
+					"expression": data.Expression,
+					"timezone":   loc.String(),
+					"next":       next,
+					"count":      len(next),
+				}, nil
+
+			case "describe":
+				return map[string]any{
+					"expression":  data.Expression,
+					"description": expr.describe(),
+				}, nil
+
+			default:
+				return nil, InvalidInput("unknown action: %s", data.Action)
+			}
+		},
+	)
+}
+
+// cronField describes the allowed values of one cron field
+type cronField struct {
+	name     string
+	unit     string
+	min, max int
+	names    []string // names for values min..max, accepted as 3-letter prefixes
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", unit: "minute", min: 0, max: 59},
+	{name: "hour", unit: "hour", min: 0, max: 23},
+	{name: "day-of-month", unit: "day", min: 1, max: 31},
+	{name: "month", unit: "month", min: 1, max: 12, names: []string{
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	}},
+	{name: "day-of-week", unit: "day", min: 0, max: 7, names: []string{
+		"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday",
+	}},
+}
+
+// cronMacros maps the @ shorthands to their expressions
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronRange is one comma-separated item of a field: lo-hi/step, where star
+// marks "*" or "*/step"
+type cronRange struct {
+	lo, hi, step int
+	star         bool
+}
+
+// cronExpr is a parsed cron expression
+type cronExpr struct {
+	ranges [5][]cronRange
+	sets   [5]uint64 // bit v is set when value v matches
+}
+
+const (
+	cronMinute = iota
+	cronHour
+	cronDom
+	cronMonth
+	cronDow
+)
+
+// maxClockTimes caps how many times of day describe lists before falling
+// back to describing the minute and hour fields separately
+const maxClockTimes = 6
+
+// cronSearchYears bounds how far next looks ahead, so impossible schedules
+// such as "0 0 30 2 *" fail instead of looping forever
+const cronSearchYears = 5
+
+// parseCron parses a 5-field cron expression or an @ shorthand
+func parseCron(s string) (*cronExpr, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "@") {
+		expanded, ok := cronMacros[strings.ToLower(s)]
+		if !ok {
+			return nil, InvalidInput("unknown cron shorthand '%s'", s)
+		}
+		s = expanded
+	}
+
+	parts := strings.Fields(s)
+	if len(parts) != 5 {
+		return nil, InvalidInput("invalid cron expression '%s': expected 5 fields (minute hour day-of-month month day-of-week), got %d", s, len(parts))
+	}
+
+	expr := &cronExpr{}
+	for i, part := range parts {
+		ranges, set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		expr.ranges[i], expr.sets[i] = ranges, set
+	}
+	// 7 is an alias for Sunday
+	if expr.sets[cronDow]&(1<<7) != 0 {
+		expr.sets[cronDow] |= 1
+	}
+	return expr, nil
+}
+
+// parseCronField parses one field into its ranges and value set
+func parseCronField(s string, f cronField) ([]cronRange, uint64, error) {
+	var ranges []cronRange
+	var set uint64
+
+	for item := range strings.SplitSeq(s, ",") {
+		r := cronRange{step: 1}
+		spec := item
+		if base, step, ok := strings.Cut(item, "/"); ok {
+			n, err := strconv.Atoi(step)
+			if err != nil || n <= 0 {
+				return nil, 0, InvalidInput("%s field: invalid step '%s' in '%s'", f.name, step, item)
+			}
+			r.step, spec = n, base
+		}
+
+		switch {
+		case spec == "*":
+			r.lo, r.hi, r.star = f.min, f.max, true
+		case strings.Contains(spec, "-"):
+			lo, hi, _ := strings.Cut(spec, "-")
+			var err error
+			if r.lo, err = cronValue(lo, f); err != nil {
+				return nil, 0, err
+			}
+			if r.hi, err = cronValue(hi, f); err != nil {
+				return nil, 0, err
+			}
+			if r.lo > r.hi {
+				return nil, 0, InvalidInput("%s field: range '%s' starts after it ends", f.name, spec)
+			}
+		default:
+			v, err := cronValue(spec, f)
+			if err != nil {
+				return nil, 0, err
+			}
+			r.lo, r.hi = v, v
+			if r.step > 1 {
+				r.hi = f.max // "a/n" means every n from a
+			}
+		}
+
+		for v := r.lo; v <= r.hi; v += r.step {
+			set |= 1 << v
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, set, nil
+}
+
+// cronValue parses a number or name and checks it against the field's range
+func cronValue(s string, f cronField) (int, error) {
+	if s == "" {
+		return 0, InvalidInput("%s field: missing value", f.name)
+	}
+	if v, err := strconv.Atoi(s); err == nil {
+		if v < f.min || v > f.max {
+			return 0, InvalidInput("%s field: value %d out of range %d-%d", f.name, v, f.min, f.max)
+		}
+		return v, nil
+	}
+	for i, name := range f.names {
+		if strings.EqualFold(s, name[:3]) {
+			return f.min + i, nil
+		}
+	}
+	return 0, InvalidInput("%s field: invalid value '%s'", f.name, s)
+}
+
+// dayStars reports whether the day-of-month and day-of-week fields start
+// with "*", which is how cron decides between its two day rules
+func (e *cronExpr) dayStars() (dom, dow bool) {
+	return e.ranges[cronDom][0].star, e.ranges[cronDow][0].star
+}
+
+// matchesDay applies cron's day rule: when both day-of-month and day-of-week
+// are restricted, a day matching either one fires
+func (e *cronExpr) matchesDay(t time.Time) bool {
+	dom := e.sets[cronDom]&(1<<t.Day()) != 0
+	dow := e.sets[cronDow]&(1<<int(t.Weekday())) != 0
+	domStar, dowStar := e.dayStars()
+	switch {
+	case domStar && dowStar:
+		return true
+	case domStar:
+		return dow
+	case dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first fire time strictly after t, in t's location
+func (e *cronExpr) next(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case e.sets[cronMonth]&(1<<int(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !e.matchesDay(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case e.sets[cronHour]&(1<<t.Hour()) == 0:
+			// Add rather than time.Date, which can map a skipped DST hour back
+			next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case e.sets[cronMinute]&(1<<t.Minute()) == 0:
+			next = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+		if !next.After(t) {
+			// A midnight that doesn't exist in loc normalized backwards
+			next = t.Add(time.Hour)
+		}
+		t = next
+	}
+	return time.Time{}, InvalidInput("schedule never fires within %d years", cronSearchYears)
+}
+
+// nextN returns the next n fire times after t
+func (e *cronExpr) nextN(t time.Time, n int) ([]time.Time, error) {
+	times := make([]time.Time, 0, n)
+	for range n {
+		next, err := e.next(t)
+		if err != nil {
+			if len(times) > 0 {
+				break
+			}
+			return nil, err
+		}
+		times = append(times, next)
+		t = next
+	}
+	return times, nil
+}
+
+// describe explains the schedule in words, e.g. "every day at 3:00 AM"
+func (e *cronExpr) describe() string {
+	at, clock := e.describeTime()
+	var parts []string
+
+	domStar, dowStar := e.dayStars()
+	if domStar || dowStar {
+		if !e.every(cronDom) {
+			parts = append(parts, e.describeDom())
+		}
+		if !e.every(cronDow) {
+			parts = append(parts, "on "+e.describeField(cronDow))
+		}
+	} else {
+		parts = append(parts, e.describeDom()+" or on "+e.describeField(cronDow))
+	}
+	if !e.every(cronMonth) {
+		parts = append(parts, "in "+e.describeField(cronMonth))
+	}
+
+	if len(parts) == 0 && clock {
+		return "every day " + at
+	}
+	return strings.Join(append([]string{at}, parts...), " ")
+}
+
+// every reports whether field i is a plain "*"
+func (e *cronExpr) every(i int) bool {
+	rs := e.ranges[i]
+	return len(rs) == 1 && rs[0].star && rs[0].step == 1
+}
+
+// describeDom explains the day-of-month field
+func (e *cronExpr) describeDom() string {
+	days := e.describeField(cronDom)
+	if strings.HasPrefix(days, "every ") {
+		return days + " of the month"
+	}
+	return "on day " + days + " of the month"
+}
+
+// describeTime explains the minute and hour fields. It reports whether the
+// schedule fires at fixed clock times.
+func (e *cronExpr) describeTime() (string, bool) {
+	minutes, hours := e.ranges[cronMinute], e.ranges[cronHour]
+	single := func(rs []cronRange) bool { return len(rs) == 1 && !rs[0].star && rs[0].lo == rs[0].hi }
+	singles := func(rs []cronRange) bool {
+		for _, r := range rs {
+			if r.star || r.lo != r.hi {
+				return false
+			}
+		}
+		return true
+	}
+	everyHour := len(hours) == 1 && hours[0].star
+
+	switch {
+	case singles(minutes) && singles(hours) && len(minutes)*len(hours) <= maxClockTimes:
+		var times []string
+		for _, h := range hours {
+			for _, m := range minutes {
+				times = append(times, clockTime(h.lo, m.lo))
+			}
+		}
+		return "at " + joinWords(times), true
+	case len(minutes) == 1 && minutes[0].star && everyHour:
+		if minutes[0].step == 1 {
+			return "every minute", false
+		}
+		return fmt.Sprintf("every %d minutes", minutes[0].step), false
+	case single(minutes) && minutes[0].lo == 0 && everyHour:
+		if hours[0].step == 1 {
+			return "every hour", false
+		}
+		return fmt.Sprintf("every %d hours", hours[0].step), false
+	}
+
+	desc, link := "at minute "+e.describeField(cronMinute), " past "
+	if len(minutes) == 1 && minutes[0].star {
+		desc, link = e.describeField(cronMinute), " during "
+	}
+	hourDesc := e.describeField(cronHour)
+	switch {
+	case e.every(cronHour):
+		return desc + " of every hour", false
+	case strings.HasPrefix(hourDesc, "every "):
+		return desc + " " + hourDesc, false
+	case single(hours):
+		return desc + link + "hour " + hourDesc, false
+	default:
+		return desc + link + "hours " + hourDesc, false
+	}
+}
+
+// describeField explains the items of field i, e.g. "Monday through Friday"
+func (e *cronExpr) describeField(i int) string {
+	f := cronFields[i]
+	name := func(v int) string {
+		if f.names != nil {
+			return f.names[v-f.min]
+		}
+		return strconv.Itoa(v)
+	}
+
+	items := make([]string, len(e.ranges[i]))
+	for j, r := range e.ranges[i] {
+		switch {
+		case r.star && r.step == 1:
+			items[j] = "every " + f.unit
+		case r.star:
+			items[j] = fmt.Sprintf("every %d %ss", r.step, f.unit)
+		case r.lo == r.hi:
+			items[j] = name(r.lo)
+		case r.step == 1:
+			items[j] = name(r.lo) + " through " + name(r.hi)
+		default:
+			items[j] = fmt.Sprintf("every %d %ss from %s through %s", r.step, f.unit, name(r.lo), name(r.hi))
+		}
+	}
+	return joinWords(items)
+}
+
+// clockTime formats an hour and minute as "3:05 PM"
+func clockTime(hour, minute int) string {
+	return time.Date(2000, 1, 1, hour, minute, 0, 0, time.UTC).Format("3:04 PM")
+}
+
+// joinWords joins items as "a", "a and b" or "a, b and c"
+func joinWords(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
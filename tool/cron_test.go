@@ -0,0 +1,83 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCron_NextDaily(t *testing.T) {
+	got := runTool(t, NewCronTool(), map[string]any{
+		"action": "next", "expression": "0 3 * * *", "from": "2024-03-09T12:00:00Z", "count": 3,
+	})
+	want := []string{"2024-03-10T03:00:00Z", "2024-03-11T03:00:00Z", "2024-03-12T03:00:00Z"}
+	if !reflect.DeepEqual(got["next"], want) {
+		t.Errorf("expected %v, got %v", want, got["next"])
+	}
+
+	// The schedule runs on the wall clock of the timezone, across the DST change
+	got = runTool(t, NewCronTool(), map[string]any{
+		"action": "next", "expression": "0 3 * * *", "from": "2024-03-09T12:00:00-05:00", "count": 2, "timezone": "America/New_York",
+	})
+	want = []string{"2024-03-10T03:00:00-04:00", "2024-03-11T03:00:00-04:00"}
+	if !reflect.DeepEqual(got["next"], want) {
+		t.Errorf("expected %v, got %v", want, got["next"])
+	}
+
+	if got := runTool(t, NewCronTool(), map[string]any{"action": "describe", "expression": "0 3 * * *"}); got["description"] != "every day at 3:00 AM" {
+		t.Errorf("expected daily description, got %v", got["description"])
+	}
+}
+
+func TestCron_NextWeekdaysAndDayRule(t *testing.T) {
+	got := runTool(t, NewCronTool(), map[string]any{
+		"action": "next", "expression": "30 9 * * MON-FRI", "from": "2024-03-08T10:00:00Z", "count": 2,
+	})
+	if want := []string{"2024-03-11T09:30:00Z", "2024-03-12T09:30:00Z"}; !reflect.DeepEqual(got["next"], want) {
+		t.Errorf("expected to skip the weekend, got %v", got["next"])
+	}
+
+	// With both day fields restricted, either one matching fires
+	got = runTool(t, NewCronTool(), map[string]any{
+		"action": "next", "expression": "0 0 1 * 5", "from": "2024-02-25T00:00:00Z", "count": 2,
+	})
+	if want := []string{"2024-03-01T00:00:00Z", "2024-03-08T00:00:00Z"}; !reflect.DeepEqual(got["next"], want) {
+		t.Errorf("expected the 1st and the next Friday, got %v", got["next"])
+	}
+}
+
+func TestCron_Describe(t *testing.T) {
+	tests := map[string]string{
+		"* * * * *":         "every minute",
+		"*/15 * * * *":      "every 15 minutes",
+		"0 * * * *":         "every hour",
+		"15 * * * *":        "at minute 15 of every hour",
+		"0 9,17 * * 1-5":    "at 9:00 AM and 5:00 PM on Monday through Friday",
+		"@monthly":          "at 12:00 AM on day 1 of the month",
+		"0 4 * JUN-AUG SUN": "at 4:00 AM on Sunday in June through August",
+		"*/10 9-17 * * *":   "every 10 minutes during hours 9 through 17",
+	}
+	for expr, want := range tests {
+		if got := runTool(t, NewCronTool(), map[string]any{"action": "describe", "expression": expr})["description"]; got != want {
+			t.Errorf("%s: expected %q, got %q", expr, want, got)
+		}
+	}
+}
+
+func TestCron_InvalidExpression(t *testing.T) {
+	tests := map[string]string{
+		"60 * * * *":  "minute field: value 60 out of range 0-59",
+		"* * *":       "expected 5 fields",
+		"* * * FOO *": "month field: invalid value 'FOO'",
+		"*/0 * * * *": "minute field: invalid step",
+		"0 0 30 2 *":  "never fires",
+	}
+	for expr, want := range tests {
+		raw, _ := json.Marshal(map[string]any{"action": "next", "expression": expr})
+		_, err := NewCronTool().Handler(raw)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", expr, want, err)
+		}
+	}
+}
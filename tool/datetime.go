@@ -14,7 +14,41 @@ import (
 // - Parse date strings
 // - Calculate date differences
 // - Format dates in different ways
+// - Check date ranges and weekends
+// - Compute sunrise and sunset
 func NewDateTimeTool() adapter.Tool {
+	return NewDateTimeToolWithConfig(DefaultDateTimeConfig())
+}
+
+// DateTimeConfig configures NewDateTimeToolWithConfig
+type DateTimeConfig struct {
+	DefaultTZ        string // timezone used when the input has none ("" = UTC)
+	WeekStartsSunday bool   // start weeks on Sunday instead of Monday
+}
+
+// DefaultDateTimeConfig provides sensible defaults
+func DefaultDateTimeConfig() DateTimeConfig {
+	return DateTimeConfig{
+		DefaultTZ: "UTC",
+	}
+}
+
+// NewDateTimeToolWithConfig creates the datetime tool with a custom default
+// timezone and week start. It panics if DefaultTZ isn't a known timezone, so
+// misconfiguration fails at startup.
+func NewDateTimeToolWithConfig(config DateTimeConfig) adapter.Tool {
+	if config.DefaultTZ == "" {
+		config.DefaultTZ = "UTC"
+	}
+	defaultLoc, err := time.LoadLocation(config.DefaultTZ)
+	if err != nil {
+		panic(fmt.Sprintf("tool: invalid DefaultTZ %q: %v", config.DefaultTZ, err))
+	}
+	weekStart := time.Monday
+	if config.WeekStartsSunday {
+		weekStart = time.Sunday
+	}
+
 	return adapter.NewTool(
 		"datetime",
 		"Work with dates and times. Get current time, convert timezones, parse dates, calculate differences, and format dates. Use this whenever you need to know the current time or work with dates.",
@@ -23,12 +57,12 @@ func NewDateTimeTool() adapter.Tool {
 			"properties": map[string]any{
 				"action": map[string]any{
 					"type":        "string",
-					"enum":        []string{"now", "parse", "format", "diff", "add"},
-					"description": "Action to perform: 'now' (current time), 'parse' (string to date), 'format' (date to string), 'diff' (time between dates), 'add' (add duration to date)",
+					"enum":        []string{"now", "parse", "format", "diff", "add", "between", "is_weekend", "is_weekday", "sun"},
+					"description": "Action to perform: 'now' (current time), 'parse' (string to date), 'format' (date to string), 'diff' (time between dates), 'add' (add duration to date), 'between' (is target within date..date2), 'is_weekend'/'is_weekday' (check date, default now), 'sun' (sunrise, sunset, solar noon and day length at latitude/longitude on date, default today)",
 				},
 				"timezone": map[string]any{
 					"type":        "string",
-					"description": "Timezone name (e.g., 'America/New_York', 'UTC', 'Asia/Tokyo'). Default: UTC",
+					"description": "Timezone name (e.g., 'America/New_York', 'UTC', 'Asia/Tokyo'). Default: " + config.DefaultTZ,
 				},
 				"date": map[string]any{
 					"type":        "string",
@@ -46,68 +80,109 @@ func NewDateTimeTool() adapter.Tool {
 					"type":        "string",
 					"description": "Duration to add (e.g., '1h', '24h', '7d', '30d', '-2h')",
 				},
+				"target": map[string]any{
+					"type":        "string",
+					"description": "Date to test for between, with date and date2 as the bounds",
+				},
+				"inclusive_start": map[string]any{
+					"type":        "boolean",
+					"description": "Whether between includes date itself (default: true)",
+				},
+				"inclusive_end": map[string]any{
+					"type":        "boolean",
+					"description": "Whether between includes date2 itself (default: true); a date-only date2 includes that whole day",
+				},
+				"latitude": map[string]any{
+					"type":        "number",
+					"description": "Latitude in degrees for sun (north positive)",
+				},
+				"longitude": map[string]any{
+					"type":        "number",
+					"description": "Longitude in degrees for sun (east positive)",
+				},
 			},
 			"required": []string{"action"},
 		},
 		func(input json.RawMessage) (any, error) {
 			var data struct {
-				Action   string `json:"action"`
-				Timezone string `json:"timezone"`
-				Date     string `json:"date"`
-				Date2    string `json:"date2"`
-				Format   string `json:"format"`
-				Duration string `json:"duration"`
+				Action         string   `json:"action"`
+				Timezone       string   `json:"timezone"`
+				Date           string   `json:"date"`
+				Date2          string   `json:"date2"`
+				Format         string   `json:"format"`
+				Duration       string   `json:"duration"`
+				Target         string   `json:"target"`
+				InclusiveStart *bool    `json:"inclusive_start"`
+				InclusiveEnd   *bool    `json:"inclusive_end"`
+				Latitude       *float64 `json:"latitude"`
+				Longitude      *float64 `json:"longitude"`
 			}
 			if err := json.Unmarshal(input, &data); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
-			}
-
-			// Default timezone
-			if data.Timezone == "" {
-				data.Timezone = "UTC"
+				return nil, InvalidInput("invalid input: %w", err)
 			}
 
-			loc, err := time.LoadLocation(data.Timezone)
-			if err != nil {
-				return nil, fmt.Errorf("invalid timezone '%s': %w", data.Timezone, err)
+			loc := defaultLoc
+			if data.Timezone != "" {
+				var err error
+				loc, err = time.LoadLocation(data.Timezone)
+				if err != nil {
+					return nil, InvalidInput("invalid timezone '%s': %w", data.Timezone, err)
+				}
 			}
 
 			switch data.Action {
 			case "now":
-				return getCurrentTime(loc, data.Format)
+				return getCurrentTime(loc, data.Format, weekStart)
 
 			case "parse":
 				if data.Date == "" {
-					return nil, fmt.Errorf("date is required for parse action")
+					return nil, InvalidInput("date is required for parse action")
 				}
-				return parseDate(data.Date, loc)
+				return parseDate(data.Date, loc, weekStart)
 
 			case "format":
 				if data.Date == "" {
-					return nil, fmt.Errorf("date is required for format action")
+					return nil, InvalidInput("date is required for format action")
 				}
 				return formatDate(data.Date, data.Format, loc)
 
 			case "diff":
 				if data.Date == "" || data.Date2 == "" {
-					return nil, fmt.Errorf("date and date2 are required for diff action")
+					return nil, InvalidInput("date and date2 are required for diff action")
 				}
 				return dateDiff(data.Date, data.Date2, loc)
 
 			case "add":
 				if data.Duration == "" {
-					return nil, fmt.Errorf("duration is required for add action")
+					return nil, InvalidInput("duration is required for add action")
 				}
 				return addDuration(data.Date, data.Duration, loc)
 
+			case "between":
+				if data.Date == "" || data.Date2 == "" || data.Target == "" {
+					return nil, InvalidInput("date, date2 and target are required for between action")
+				}
+				inclusiveStart := data.InclusiveStart == nil || *data.InclusiveStart
+				inclusiveEnd := data.InclusiveEnd == nil || *data.InclusiveEnd
+				return dateBetween(data.Target, data.Date, data.Date2, inclusiveStart, inclusiveEnd, loc)
+
+			case "is_weekend", "is_weekday":
+				return weekendCheck(data.Date, loc)
+
+			case "sun":
+				if data.Latitude == nil || data.Longitude == nil {
+					return nil, InvalidInput("latitude and longitude are required for sun action")
+				}
+				return sunTimes(data.Date, *data.Latitude, *data.Longitude, loc)
+
 			default:
-				return nil, fmt.Errorf("unknown action: %s", data.Action)
+				return nil, InvalidInput("unknown action: %s", data.Action)
 			}
 		},
 	)
 }
 
-func getCurrentTime(loc *time.Location, format string) (map[string]any, error) {
+func getCurrentTime(loc *time.Location, format string, weekStart time.Weekday) (map[string]any, error) {
 	now := time.Now().In(loc)
 	return map[string]any{
 		"iso":        now.Format(time.RFC3339),
@@ -116,6 +191,7 @@ func getCurrentTime(loc *time.Location, format string) (map[string]any, error) {
 		"formatted":  formatTime(now, format),
 		"timezone":   loc.String(),
 		"weekday":    now.Weekday().String(),
+		"week_start": startOfWeek(now, weekStart).Format("2006-01-02"),
 		"year":       now.Year(),
 		"month":      now.Month().String(),
 		"day":        now.Day(),
@@ -124,7 +200,7 @@ func getCurrentTime(loc *time.Location, format string) (map[string]any, error) {
 	}, nil
 }
 
-func parseDate(dateStr string, loc *time.Location) (map[string]any, error) {
+func parseDate(dateStr string, loc *time.Location, weekStart time.Weekday) (map[string]any, error) {
 	// Try multiple formats
 	formats := []string{
 		time.RFC3339,
@@ -148,25 +224,32 @@ func parseDate(dateStr string, loc *time.Location) (map[string]any, error) {
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("could not parse date '%s': try ISO 8601 format (YYYY-MM-DDTHH:MM:SS)", dateStr)
+		return nil, InvalidInput("could not parse date '%s': try ISO 8601 format (YYYY-MM-DDTHH:MM:SS)", dateStr)
 	}
 
 	return map[string]any{
-		"iso":      parsed.Format(time.RFC3339),
-		"unix":     parsed.Unix(),
-		"valid":    true,
-		"weekday":  parsed.Weekday().String(),
-		"timezone": loc.String(),
+		"iso":        parsed.Format(time.RFC3339),
+		"unix":       parsed.Unix(),
+		"valid":      true,
+		"weekday":    parsed.Weekday().String(),
+		"week_start": startOfWeek(parsed, weekStart).Format("2006-01-02"),
+		"timezone":   loc.String(),
 	}, nil
 }
 
+// startOfWeek returns midnight on the first day of t's week
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
 func formatDate(dateStr, format string, loc *time.Location) (map[string]any, error) {
 	parsed, err := time.ParseInLocation(time.RFC3339, dateStr, loc)
 	if err != nil {
 		// Try simpler format
 		parsed, err = time.ParseInLocation("2006-01-02", dateStr, loc)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse date: %w", err)
+			return nil, InvalidInput("could not parse date: %w", err)
 		}
 	}
 
@@ -204,7 +287,7 @@ func dateDiff(date1, date2 string, loc *time.Location) (map[string]any, error) {
 	if err != nil {
 		t1, err = time.ParseInLocation("2006-01-02", date1, loc)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse date1: %w", err)
+			return nil, InvalidInput("could not parse date1: %w", err)
 		}
 	}
 
@@ -212,7 +295,7 @@ func dateDiff(date1, date2 string, loc *time.Location) (map[string]any, error) {
 	if err != nil {
 		t2, err = time.ParseInLocation("2006-01-02", date2, loc)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse date2: %w", err)
+			return nil, InvalidInput("could not parse date2: %w", err)
 		}
 	}
 
@@ -247,7 +330,7 @@ func addDuration(dateStr, duration string, loc *time.Location) (map[string]any,
 		if err != nil {
 			baseTime, err = time.ParseInLocation("2006-01-02", dateStr, loc)
 			if err != nil {
-				return nil, fmt.Errorf("could not parse date: %w", err)
+				return nil, InvalidInput("could not parse date: %w", err)
 			}
 		}
 	}
@@ -259,13 +342,13 @@ func addDuration(dateStr, duration string, loc *time.Location) (map[string]any,
 		var days int
 		_, err := fmt.Sscanf(duration, "%dd", &days)
 		if err != nil {
-			return nil, fmt.Errorf("invalid duration: %w", err)
+			return nil, InvalidInput("invalid duration: %w", err)
 		}
 		dur = time.Duration(days) * 24 * time.Hour
 	} else {
 		dur, err = time.ParseDuration(duration)
 		if err != nil {
-			return nil, fmt.Errorf("invalid duration '%s': use formats like '1h', '30m', '7d'", duration)
+			return nil, InvalidInput("invalid duration '%s': use formats like '1h', '30m', '7d'", duration)
 		}
 	}
 
@@ -279,3 +362,80 @@ func addDuration(dateStr, duration string, loc *time.Location) (map[string]any,
 		"unix":     result.Unix(),
 	}, nil
 }
+
+// parseDateArg parses an RFC 3339 timestamp or a YYYY-MM-DD date in loc and
+// reports whether it was date-only
+func parseDateArg(dateStr string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(time.RFC3339, dateStr, loc); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+	if err != nil {
+		return time.Time{}, false, InvalidInput("could not parse date '%s': use YYYY-MM-DD or RFC 3339", dateStr)
+	}
+	return t, true, nil
+}
+
+// dateBetween reports whether target falls between start and end. Date-only
+// values are midnight in loc, except that an inclusive date-only end covers
+// its whole day.
+func dateBetween(target, start, end string, inclusiveStart, inclusiveEnd bool, loc *time.Location) (map[string]any, error) {
+	t, _, err := parseDateArg(target, loc)
+	if err != nil {
+		return nil, err
+	}
+	lo, _, err := parseDateArg(start, loc)
+	if err != nil {
+		return nil, err
+	}
+	hi, endDateOnly, err := parseDateArg(end, loc)
+	if err != nil {
+		return nil, err
+	}
+	if hi.Before(lo) {
+		return nil, InvalidInput("date2 (%s) is before date (%s)", end, start)
+	}
+
+	afterStart := t.After(lo) || (inclusiveStart && t.Equal(lo))
+	var beforeEnd bool
+	switch {
+	case inclusiveEnd && endDateOnly:
+		beforeEnd = t.Before(hi.AddDate(0, 0, 1))
+	case inclusiveEnd:
+		beforeEnd = !t.After(hi)
+	default:
+		beforeEnd = t.Before(hi)
+	}
+
+	return map[string]any{
+		"between":         afterStart && beforeEnd,
+		"target":          t.In(loc).Format(time.RFC3339),
+		"start":           lo.In(loc).Format(time.RFC3339),
+		"end":             hi.In(loc).Format(time.RFC3339),
+		"inclusive_start": inclusiveStart,
+		"inclusive_end":   inclusiveEnd,
+		"timezone":        loc.String(),
+	}, nil
+}
+
+// weekendCheck reports whether dateStr (default now) falls on a Saturday or
+// Sunday in loc
+func weekendCheck(dateStr string, loc *time.Location) (map[string]any, error) {
+	t := time.Now()
+	if dateStr != "" {
+		var err error
+		if t, _, err = parseDateArg(dateStr, loc); err != nil {
+			return nil, err
+		}
+	}
+	t = t.In(loc)
+
+	weekend := t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	return map[string]any{
+		"date":       t.Format("2006-01-02"),
+		"weekday":    t.Weekday().String(),
+		"is_weekend": weekend,
+		"is_weekday": !weekend,
+		"timezone":   loc.String(),
+	}, nil
+}
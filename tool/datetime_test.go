@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dvictor357/blaze"
+	"github.com/dvictor357/blaze/adapter"
+)
+
+func TestDateTime_DefaultTimezoneFromHeader(t *testing.T) {
+	e := blaze.New()
+	e.POST("/chat", adapter.AnthropicAdapterWithOptions(
+		[]adapter.Tool{NewDateTimeTool()},
+		adapter.WithDefaultTimezone(),
+	))
+
+	call := func(input map[string]any) map[string]any {
+		body, _ := json.Marshal(adapter.AnthropicChatRequest{
+			Model: "claude-3-5-sonnet",
+			Messages: []adapter.AnthropicMessage{{Role: "user", Content: []adapter.AnthropicContentBlock{
+				{Type: "tool_use", ID: "toolu_1", Name: "datetime", Input: input},
+			}}},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(adapter.TimezoneHeader, "Asia/Tokyo")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var resp adapter.AnthropicChatResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if len(resp.Content) != 1 || resp.Content[0].IsError {
+			t.Fatalf("unexpected response %s", rec.Body.String())
+		}
+		var result map[string]any
+		json.Unmarshal([]byte(resp.Content[0].Content), &result)
+		return result
+	}
+
+	if got := call(map[string]any{"action": "now"})["timezone"]; got != "Asia/Tokyo" {
+		t.Errorf("expected header timezone Asia/Tokyo, got %v", got)
+	}
+
+	// An explicit timezone is left untouched
+	if got := call(map[string]any{"action": "now", "timezone": "Europe/Paris"})["timezone"]; got != "Europe/Paris" {
+		t.Errorf("expected explicit timezone Europe/Paris, got %v", got)
+	}
+}
+
+func TestDateTime_ConfiguredDefaultTimezone(t *testing.T) {
+	tool := NewDateTimeToolWithConfig(DateTimeConfig{DefaultTZ: "America/New_York"})
+
+	if got := runTool(t, tool, map[string]any{"action": "now"})["timezone"]; got != "America/New_York" {
+		t.Errorf("expected configured default timezone, got %v", got)
+	}
+	got := runTool(t, tool, map[string]any{"action": "parse", "date": "2024-07-01T12:00:00"})
+	if got["iso"] != "2024-07-01T12:00:00-04:00" {
+		t.Errorf("expected date parsed in the default timezone, got %v", got["iso"])
+	}
+
+	// An explicit timezone still wins
+	if got := runTool(t, tool, map[string]any{"action": "now", "timezone": "Asia/Tokyo"})["timezone"]; got != "Asia/Tokyo" {
+		t.Errorf("expected explicit timezone, got %v", got)
+	}
+
+	// The plain constructor keeps UTC
+	if got := runTool(t, NewDateTimeTool(), map[string]any{"action": "now"})["timezone"]; got != "UTC" {
+		t.Errorf("expected UTC default, got %v", got)
+	}
+}
+
+func TestDateTime_WeekStart(t *testing.T) {
+	input := map[string]any{"action": "parse", "date": "2024-07-03"} // a Wednesday
+
+	if got := runTool(t, NewDateTimeTool(), input)["week_start"]; got != "2024-07-01" {
+		t.Errorf("expected Monday week start, got %v", got)
+	}
+	sunday := NewDateTimeToolWithConfig(DateTimeConfig{DefaultTZ: "UTC", WeekStartsSunday: true})
+	if got := runTool(t, sunday, input)["week_start"]; got != "2024-06-30" {
+		t.Errorf("expected Sunday week start, got %v", got)
+	}
+
+	// A config that only sets the timezone keeps the Monday default
+	berlin := NewDateTimeToolWithConfig(DateTimeConfig{DefaultTZ: "Europe/Berlin"})
+	if got := runTool(t, berlin, input)["week_start"]; got != "2024-07-01" {
+		t.Errorf("expected Monday week start by default, got %v", got)
+	}
+}
+
+func TestDateTime_InvalidTimezonePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected construction to panic")
+		}
+	}()
+	NewDateTimeToolWithConfig(DateTimeConfig{DefaultTZ: "Mars/Olympus_Mons"})
+}
+
+func TestDateTime_Between(t *testing.T) {
+	tool := NewDateTimeTool()
+	between := func(target string, extra map[string]any) any {
+		input := map[string]any{"action": "between", "date": "2024-07-01", "date2": "2024-07-05", "target": target}
+		for k, v := range extra {
+			input[k] = v
+		}
+		return runTool(t, tool, input)["between"]
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		extra  map[string]any
+		want   bool
+	}{
+		{"inside", "2024-07-03", nil, true},
+		{"start inclusive", "2024-07-01", nil, true},
+		{"start exclusive", "2024-07-01", map[string]any{"inclusive_start": false}, false},
+		{"end day inclusive", "2024-07-05T23:59:59Z", nil, true},
+		{"end exclusive", "2024-07-05", map[string]any{"inclusive_end": false}, false},
+		{"after", "2024-07-06", nil, false},
+		{"before", "2024-06-30T23:59:59Z", nil, false},
+	}
+	for _, tt := range tests {
+		if got := between(tt.target, tt.extra); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// Day boundaries follow the timezone: 23:30 UTC on the 5th is already the 6th in Tokyo
+	if got := between("2024-07-05T23:30:00Z", map[string]any{"timezone": "Asia/Tokyo"}); got != false {
+		t.Errorf("expected target outside the range in Asia/Tokyo, got %v", got)
+	}
+}
+
+func TestDateTime_IsWeekend(t *testing.T) {
+	tool := NewDateTimeTool()
+
+	got := runTool(t, tool, map[string]any{"action": "is_weekend", "date": "2024-07-06"})
+	if got["is_weekend"] != true || got["weekday"] != "Saturday" {
+		t.Errorf("expected Saturday to be a weekend, got %v", got)
+	}
+	got = runTool(t, tool, map[string]any{"action": "is_weekday", "date": "2024-07-08"})
+	if got["is_weekday"] != true || got["is_weekend"] != false {
+		t.Errorf("expected Monday to be a weekday, got %v", got)
+	}
+
+	// Friday evening in New York is already Saturday in UTC
+	got = runTool(t, tool, map[string]any{"action": "is_weekend", "date": "2024-07-05T20:00:00-04:00", "timezone": "America/New_York"})
+	if got["is_weekend"] != false {
+		t.Errorf("expected Friday in New York, got %v", got)
+	}
+}
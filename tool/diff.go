@@ -0,0 +1,355 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// diffSchema is the input schema for diff
+var diffSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"mode": map[string]any{
+			"type":        "string",
+			"enum":        []string{"text", "json"},
+			"description": "'text' for a unified line diff, 'json' for a structured diff of two JSON documents (default: text)",
+		},
+		"old": map[string]any{
+			"type":        "string",
+			"description": "Original text, or JSON document for json mode",
+		},
+		"new": map[string]any{
+			"type":        "string",
+			"description": "Changed text, or JSON document for json mode",
+		},
+		"context": map[string]any{
+			"type":        "integer",
+			"description": "Unchanged lines shown around each change in text mode (default: 3)",
+		},
+	},
+	"required": []string{"old", "new"},
+}
+
+// maxDiffCells bounds the LCS table (lines of old x lines of new, after
+// common leading and trailing lines are removed)
+const maxDiffCells = 4_000_000
+
+// NewDiffTool creates a tool for comparing two versions of a document.
+// It can:
+// - Produce a unified diff between two texts, line by line
+// - List the added, removed and changed paths between two JSON documents
+func NewDiffTool() adapter.Tool {
+	return adapter.NewTool(
+		"diff",
+		"Compare two versions of a text or JSON document. Text mode returns a unified diff; json mode returns the added, removed, and changed paths. Use this to see what changed between versions.",
+		diffSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Mode    string `json:"mode"`
+				Old     string `json:"old"`
+				New     string `json:"new"`
+				Context *int   `json:"context"`
+			}
+			if err := BindInput(input, &data, diffSchema); err != nil {
+				return nil, err
+			}
+
+			switch data.Mode {
+			case "", "text":
+				context := 3
+				if data.Context != nil && *data.Context >= 0 {
+					context = *data.Context
+				}
+				return diffText(data.Old, data.New, context)
+
+			case "json":
+				// Decode numbers exactly, so large integers that round to
+				// the same float64 still differ
+				var oldDoc, newDoc any
+				if err := unmarshalNumbers([]byte(data.Old), &oldDoc); err != nil {
+					return nil, InvalidInput("invalid JSON in old: %w", err)
+				}
+				if err := unmarshalNumbers([]byte(data.New), &newDoc); err != nil {
+					return nil, InvalidInput("invalid JSON in new: %w", err)
+				}
+				return diffJSON(exactNumbers(oldDoc), exactNumbers(newDoc)), nil
+
+			default:
+				return nil, InvalidInput("unknown mode: %s", data.Mode)
+			}
+		},
+	)
+}
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, '+' added.
+// oldPos and newPos count the lines of each side before this one.
+type diffOp struct {
+	kind           byte
+	line           string
+	oldPos, newPos int
+}
+
+// diffText returns a unified diff of oldText and newText with context lines
+// around each change
+func diffText(oldText, newText string, context int) (map[string]any, error) {
+	ops, err := diffLines(splitLines(oldText), splitLines(newText))
+	if err != nil {
+		return nil, err
+	}
+
+	added, removed := 0, 0
+	for _, op := range ops {
+		switch op.kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+
+	hunks := diffHunks(ops, context)
+	var b strings.Builder
+	if len(hunks) > 0 {
+		b.WriteString("--- old\n+++ new\n")
+	}
+	for _, h := range hunks {
+		b.WriteString(h)
+	}
+
+	return map[string]any{
+		"diff":      b.String(),
+		"added":     added,
+		"removed":   removed,
+		"hunks":     len(hunks),
+		"identical": added == 0 && removed == 0,
+	}, nil
+}
+
+// splitLines splits s into lines, ignoring a trailing newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines builds an edit script from a to b using the longest common
+// subsequence of lines
+func diffLines(a, b []string) ([]diffOp, error) {
+	// Common leading and trailing lines need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		return nil, InvalidInput("inputs are too different to diff (%d x %d changed lines)", len(midA), len(midB))
+	}
+
+	// lcs[i][j] is the LCS length of midA[i:] and midB[j:]
+	n, m := len(midA), len(midB)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	oldPos, newPos := 0, 0
+	emit := func(kind byte, line string) {
+		ops = append(ops, diffOp{kind: kind, line: line, oldPos: oldPos, newPos: newPos})
+		if kind != '+' {
+			oldPos++
+		}
+		if kind != '-' {
+			newPos++
+		}
+	}
+
+	for _, line := range a[:prefix] {
+		emit(' ', line)
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && midA[i] == midB[j]:
+			emit(' ', midA[i])
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] > lcs[i+1][j]):
+			emit('+', midB[j])
+			j++
+		default:
+			emit('-', midA[i])
+			i++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		emit(' ', line)
+	}
+	return ops, nil
+}
+
+// diffHunks groups the changes in ops into unified diff hunks, merging
+// changes separated by at most 2*context unchanged lines
+func diffHunks(ops []diffOp, context int) []string {
+	var hunks []string
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			// Stop when the unchanged run is too long to bridge
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = run
+		}
+
+		hunks = append(hunks, formatHunk(ops[start:end]))
+		i = end
+	}
+	return hunks
+}
+
+// formatHunk renders ops as one hunk with its @@ header
+func formatHunk(ops []diffOp) string {
+	oldCount, newCount := 0, 0
+	var body strings.Builder
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+		body.WriteByte(op.kind)
+		body.WriteString(op.line)
+		body.WriteByte('\n')
+	}
+	return fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(ops[0].oldPos, oldCount), hunkRange(ops[0].newPos, newCount)) + body.String()
+}
+
+// hunkRange formats a hunk's start line and length as in GNU diff: the
+// length is left out when it's 1, and an empty range starts at the line
+// before it
+func hunkRange(pos, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", pos)
+	case 1:
+		return fmt.Sprintf("%d", pos+1)
+	default:
+		return fmt.Sprintf("%d,%d", pos+1, count)
+	}
+}
+
+// diffJSON lists the paths, as JSON Pointers, that differ between two
+// decoded JSON documents
+func diffJSON(oldDoc, newDoc any) map[string]any {
+	changes := []map[string]any{}
+	collectJSONChanges("", oldDoc, newDoc, &changes)
+
+	counts := map[string]int{"added": 0, "removed": 0, "changed": 0}
+	for _, c := range changes {
+		counts[c["op"].(string)]++
+	}
+
+	return map[string]any{
+		"changes":   changes,
+		"added":     counts["added"],
+		"removed":   counts["removed"],
+		"changed":   counts["changed"],
+		"identical": len(changes) == 0,
+	}
+}
+
+// collectJSONChanges appends the differences between a and b at path.
+// Objects are compared key by key and arrays index by index; anything else
+// that differs is one change.
+func collectJSONChanges(path string, a, b any, changes *[]map[string]any) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			keys := make([]string, 0, len(av)+len(bv))
+			for k := range av {
+				keys = append(keys, k)
+			}
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			slices.Sort(keys)
+
+			for _, k := range keys {
+				child := path + "/" + escapePointer(k)
+				oldVal, inOld := av[k]
+				newVal, inNew := bv[k]
+				switch {
+				case !inNew:
+					*changes = append(*changes, map[string]any{"op": "removed", "path": child, "old": oldVal})
+				case !inOld:
+					*changes = append(*changes, map[string]any{"op": "added", "path": child, "new": newVal})
+				default:
+					collectJSONChanges(child, oldVal, newVal, changes)
+				}
+			}
+			return
+		}
+	case []any:
+		if bv, ok := b.([]any); ok {
+			for i := range max(len(av), len(bv)) {
+				child := fmt.Sprintf("%s/%d", path, i)
+				switch {
+				case i >= len(bv):
+					*changes = append(*changes, map[string]any{"op": "removed", "path": child, "old": av[i]})
+				case i >= len(av):
+					*changes = append(*changes, map[string]any{"op": "added", "path": child, "new": bv[i]})
+				default:
+					collectJSONChanges(child, av[i], bv[i], changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, map[string]any{"op": "changed", "path": path, "old": a, "new": b})
+	}
+}
+
+// escapePointer escapes a key for use as a JSON Pointer token
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
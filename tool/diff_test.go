@@ -0,0 +1,88 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff_TextAddedLine(t *testing.T) {
+	got := runTool(t, NewDiffTool(), map[string]any{
+		"old": "a\nb\nc\nd\n",
+		"new": "a\nb\nnew\nc\nd\n",
+	})
+
+	want := "--- old\n+++ new\n@@ -1,4 +1,5 @@\n a\n b\n+new\n c\n d\n"
+	if got["diff"] != want {
+		t.Errorf("expected diff:\n%s\ngot:\n%s", want, got["diff"])
+	}
+	if got["added"] != 1 || got["removed"] != 0 || got["hunks"] != 1 || got["identical"] != false {
+		t.Errorf("unexpected summary %v", got)
+	}
+}
+
+func TestDiff_TextHunks(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	got := runTool(t, NewDiffTool(), map[string]any{
+		"old":     old,
+		"new":     "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n",
+		"context": 1,
+	})
+
+	// Changes far apart get separate hunks; a removed last line has an empty new range
+	want := "--- old\n+++ new\n@@ -1,3 +1,3 @@\n 1\n-2\n+TWO\n 3\n@@ -9,2 +9 @@\n 9\n-10\n"
+	if got["diff"] != want {
+		t.Errorf("expected diff:\n%s\ngot:\n%s", want, got["diff"])
+	}
+	if got["added"] != 1 || got["removed"] != 2 || got["hunks"] != 2 {
+		t.Errorf("unexpected summary %v", got)
+	}
+
+	if same := runTool(t, NewDiffTool(), map[string]any{"old": old, "new": old}); same["identical"] != true || same["diff"] != "" {
+		t.Errorf("expected identical texts, got %v", same)
+	}
+}
+
+func TestDiff_JSONChangedField(t *testing.T) {
+	got := runTool(t, NewDiffTool(), map[string]any{
+		"mode": "json",
+		"old":  `{"name": "blaze", "version": "1.0", "tags": ["go"], "meta": {"a/b": 1}}`,
+		"new":  `{"name": "blaze", "version": "1.1", "tags": ["go", "ai"], "meta": {}, "license": "MIT"}`,
+	})
+
+	want := []map[string]any{
+		{"op": "added", "path": "/license", "new": "MIT"},
+		{"op": "removed", "path": "/meta/a~1b", "old": 1.0},
+		{"op": "added", "path": "/tags/1", "new": "ai"},
+		{"op": "changed", "path": "/version", "old": "1.0", "new": "1.1"},
+	}
+	if !reflect.DeepEqual(got["changes"], want) {
+		t.Errorf("expected %v, got %v", want, got["changes"])
+	}
+	if got["added"] != 2 || got["removed"] != 1 || got["changed"] != 1 {
+		t.Errorf("unexpected summary %v", got)
+	}
+
+	// A type change at the root is a single change
+	root := runTool(t, NewDiffTool(), map[string]any{"mode": "json", "old": `[1]`, "new": `{"a": 1}`})
+	if changes := root["changes"].([]map[string]any); len(changes) != 1 || changes[0]["path"] != "" {
+		t.Errorf("expected one root change, got %v", root["changes"])
+	}
+}
+
+func TestDiff_JSONLargeIntegers(t *testing.T) {
+	got := runTool(t, NewDiffTool(), map[string]any{
+		"mode": "json",
+		"old":  `{"id": 12345678901234567, "n": 1}`,
+		"new":  `{"id": 12345678901234568, "n": 1.0}`,
+	})
+
+	// 12345678901234567 rounds to 12345678901234568 as a float64, so it
+	// stays a json.Number; the other side is exact as a float64
+	want := []map[string]any{
+		{"op": "changed", "path": "/id", "old": json.Number("12345678901234567"), "new": 12345678901234568.0},
+	}
+	if !reflect.DeepEqual(got["changes"], want) {
+		t.Errorf("expected %v, got %v", want, got["changes"])
+	}
+}
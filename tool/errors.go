@@ -0,0 +1,48 @@
+package tool
+
+import "github.com/dvictor357/blaze/adapter"
+
+// Error is a tool failure with a Kind the adapters report to the client, so
+// it can tell bad input from failures worth retrying. Create one with
+// InvalidInput, NotFound, Upstream, RateLimited or Internal; errors without
+// a kind are reported as internal.
+type Error = adapter.ToolError
+
+// ErrorKind classifies an Error
+type ErrorKind = adapter.ErrorKind
+
+// Error kinds
+const (
+	KindInvalidInput = adapter.KindInvalidInput
+	KindNotFound     = adapter.KindNotFound
+	KindUpstream     = adapter.KindUpstream
+	KindRateLimited  = adapter.KindRateLimited
+	KindInternal     = adapter.KindInternal
+)
+
+// InvalidInput reports input the tool cannot work with, e.g. a missing
+// field or a malformed expression. The message is formatted as by
+// fmt.Errorf, including %w.
+func InvalidInput(format string, args ...any) *Error {
+	return adapter.NewToolError(KindInvalidInput, format, args...)
+}
+
+// NotFound reports that something the input refers to does not exist
+func NotFound(format string, args ...any) *Error {
+	return adapter.NewToolError(KindNotFound, format, args...)
+}
+
+// Upstream reports a failure of a remote service the tool depends on
+func Upstream(format string, args ...any) *Error {
+	return adapter.NewToolError(KindUpstream, format, args...)
+}
+
+// RateLimited reports that the tool or a service it calls is throttling
+func RateLimited(format string, args ...any) *Error {
+	return adapter.NewToolError(KindRateLimited, format, args...)
+}
+
+// Internal reports a failure inside the tool itself
+func Internal(format string, args ...any) *Error {
+	return adapter.NewToolError(KindInternal, format, args...)
+}
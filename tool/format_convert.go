@@ -0,0 +1,89 @@
+package tool
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dvictor357/blaze/adapter"
+	"github.com/dvictor357/blaze/internal/docfmt"
+)
+
+// formatConvertSchema is the input schema for format_convert
+var formatConvertSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"from": map[string]any{
+			"type":        "string",
+			"enum":        []string{"json", "yaml", "toml"},
+			"description": "Format of content",
+		},
+		"to": map[string]any{
+			"type":        "string",
+			"enum":        []string{"json", "yaml", "toml"},
+			"description": "Format to convert to",
+		},
+		"content": map[string]any{
+			"type":        "string",
+			"description": "Document to convert",
+		},
+	},
+	"required": []string{"from", "to", "content"},
+}
+
+// NewFormatConvertTool creates a tool that converts configuration documents
+// between JSON, YAML and TOML. Documents are decoded into plain maps and
+// lists, so keys come out sorted and comments are dropped; integers, floats,
+// booleans and strings keep their types.
+func NewFormatConvertTool() adapter.Tool {
+	return adapter.NewTool(
+		"format_convert",
+		"Convert a document between JSON, YAML and TOML. Use this when editing configuration files in a different format than the data you have. Keys are sorted and comments are not kept.",
+		formatConvertSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				From    string `json:"from"`
+				To      string `json:"to"`
+				Content string `json:"content"`
+			}
+			if err := BindInput(input, &data, formatConvertSchema); err != nil {
+				return nil, err
+			}
+
+			var doc any
+			var err error
+			switch data.From {
+			case "json":
+				doc, err = docfmt.ParseJSON(data.Content)
+			case "yaml":
+				doc, err = docfmt.ParseYAML(data.Content)
+			case "toml":
+				doc, err = docfmt.ParseTOML(data.Content)
+			default:
+				return nil, InvalidInput("unknown format: %s", data.From)
+			}
+			if err != nil {
+				return nil, InvalidInput("invalid %s: %w", strings.ToUpper(data.From), err)
+			}
+
+			var out string
+			switch data.To {
+			case "json":
+				out, err = docfmt.FormatJSON(doc)
+			case "yaml":
+				out, err = docfmt.FormatYAML(doc)
+			case "toml":
+				out, err = docfmt.FormatTOML(doc)
+			default:
+				return nil, InvalidInput("unknown format: %s", data.To)
+			}
+			if err != nil {
+				return nil, InvalidInput("%w", err)
+			}
+
+			return map[string]any{
+				"content": out,
+				"format":  data.To,
+			}, nil
+		},
+	)
+}
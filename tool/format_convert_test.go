@@ -0,0 +1,198 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// runFormatConvert calls the format_convert tool and returns the converted
+// content
+func runFormatConvert(from, to, content string) (string, error) {
+	raw, _ := json.Marshal(map[string]any{"from": from, "to": to, "content": content})
+	out, err := NewFormatConvertTool().Handler(raw)
+	if err != nil {
+		return "", err
+	}
+	return out.(map[string]any)["content"].(string), nil
+}
+
+func TestFormatConvert_JSONToYAML(t *testing.T) {
+	src := `{
+  "name": "blaze",
+  "port": 8080,
+  "ratio": 1.5,
+  "debug": false,
+  "token": null,
+  "version": "1.0",
+  "tags": ["go", "ai"],
+  "servers": [{"host": "a.example.com", "port": 80}],
+  "note": "line one\nline two: yes",
+  "answer": "yes",
+  "switch": "off"
+}`
+	got, err := runFormatConvert("json", "yaml", src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `answer: "yes"
+debug: false
+name: blaze
+note: "line one\nline two: yes"
+port: 8080
+ratio: 1.5
+servers:
+  - host: a.example.com
+    port: 80
+switch: "off"
+tags:
+  - go
+  - ai
+token: null
+version: "1.0"
+`
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	// And back again, with the same types
+	back, err := runFormatConvert("yaml", "json", got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var before, after any
+	json.Unmarshal([]byte(src), &before)
+	json.Unmarshal([]byte(back), &after)
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("round trip changed the document:\n%s", back)
+	}
+}
+
+func TestFormatConvert_YAMLToJSON(t *testing.T) {
+	src := `# service config
+name: blaze
+replicas: 3
+limits: {cpu: 0.5, memory: "512Mi"}
+env:
+- KEY=value
+- 'it''s quoted'
+script: |
+  make build
+  make test
+ports: [80, 443]
+enabled: yes
+`
+	got, err := runFormatConvert("yaml", "json", src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, got)
+	}
+	want := map[string]any{
+		"name":     "blaze",
+		"replicas": 3.0,
+		"limits":   map[string]any{"cpu": 0.5, "memory": "512Mi"},
+		"env":      []any{"KEY=value", "it's quoted"},
+		"script":   "make build\nmake test\n",
+		"ports":    []any{80.0, 443.0},
+		"enabled":  "yes", // YAML 1.2: only true/false are booleans
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("expected %v, got %v", want, doc)
+	}
+	if !strings.Contains(got, `"replicas": 3,`) {
+		t.Errorf("expected integers to stay integers, got:\n%s", got)
+	}
+}
+
+func TestFormatConvert_TOML(t *testing.T) {
+	src := `title = "example" # comment
+[owner]
+name = "Ada"
+dob = 1979-05-27T07:32:00Z
+
+[[products]]
+name = "Hammer"
+sku = 738_594_937
+
+[[products]]
+name = "Nail"
+colors = ["gray", 'silver']
+`
+	got, err := runFormatConvert("toml", "json", src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc map[string]any
+	json.Unmarshal([]byte(got), &doc)
+	want := map[string]any{
+		"title": "example",
+		"owner": map[string]any{"name": "Ada", "dob": "1979-05-27T07:32:00Z"},
+		"products": []any{
+			map[string]any{"name": "Hammer", "sku": 738594937.0},
+			map[string]any{"name": "Nail", "colors": []any{"gray", "silver"}},
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("expected %v, got %v", want, doc)
+	}
+
+	back, err := runFormatConvert("json", "toml", got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantTOML := `title = "example"
+
+[owner]
+dob = "1979-05-27T07:32:00Z"
+name = "Ada"
+
+[[products]]
+name = "Hammer"
+sku = 738594937
+
+[[products]]
+colors = ["gray", "silver"]
+name = "Nail"
+`
+	if back != wantTOML {
+		t.Errorf("expected:\n%s\ngot:\n%s", wantTOML, back)
+	}
+
+	// TOML has no null and needs a table at the top
+	for _, src := range []string{`{"a": null}`, `[1, 2]`} {
+		if _, err := runFormatConvert("json", "toml", src); err == nil {
+			t.Errorf("expected error converting %s to TOML", src)
+		}
+	}
+}
+
+func TestFormatConvert_MalformedInput(t *testing.T) {
+	tests := []struct {
+		from    string
+		content string
+		want    string
+	}{
+		{"json", "{\n  \"a\": 1,\n  \"b\": }\n", `invalid JSON: line 3: invalid character '}' looking for beginning of value: "  \"b\": }"`},
+		{"yaml", "a:\n  b: 1\n   c: 2\n", `invalid YAML: line 3: bad indentation: "   c: 2"`},
+		{"yaml", "a: [1, 2\n", `invalid YAML: line 1: unterminated '[': "a: [1, 2"`},
+		{"yaml", "a: b: c\n", `invalid YAML: line 1: unexpected ':' in plain value: "a: b: c"`},
+		{"toml", "a = 1\nb = \"open\n", `invalid TOML: line 2: unterminated string: "b = \"open"`},
+	}
+
+	for _, tt := range tests {
+		_, err := runFormatConvert(tt.from, "json", tt.content)
+		if err == nil {
+			t.Errorf("expected error for %q", tt.content)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, err.Error())
+		}
+	}
+}
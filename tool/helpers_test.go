@@ -0,0 +1,20 @@
+package tool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// runTool calls tool's handler with input and returns its result map,
+// failing the test on an error
+func runTool(t *testing.T, tool adapter.Tool, input map[string]any) map[string]any {
+	t.Helper()
+	raw, _ := json.Marshal(input)
+	out, err := tool.Handler(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out.(map[string]any)
+}
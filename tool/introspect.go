@@ -0,0 +1,61 @@
+package tool
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// introspectionToolName is the name of the tool returned by NewToolIntrospectionTool
+const introspectionToolName = "list_tools"
+
+// NewToolIntrospectionTool creates a tool that lets the AI discover the tools
+// in registry mid-conversation. It returns each tool's name, description and
+// input schema, optionally filtered by a query substring. Disabled tools and
+// the tool itself are not listed.
+func NewToolIntrospectionTool(registry *adapter.ToolRegistry) adapter.Tool {
+	return adapter.NewTool(
+		introspectionToolName,
+		"List the tools available to you with their descriptions and input schemas. Use this to discover capabilities before deciding which tool to call.",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Optional case-insensitive filter matched against tool names and descriptions",
+				},
+			},
+		},
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Query string `json:"query"`
+			}
+			if err := json.Unmarshal(input, &data); err != nil {
+				return nil, InvalidInput("invalid input: %w", err)
+			}
+			query := strings.ToLower(data.Query)
+
+			tools := []map[string]any{}
+			for _, t := range registry.EnabledTools() {
+				if t.Name == introspectionToolName {
+					continue
+				}
+				if query != "" && !strings.Contains(strings.ToLower(t.Name), query) &&
+					!strings.Contains(strings.ToLower(t.Description), query) {
+					continue
+				}
+				tools = append(tools, map[string]any{
+					"name":         t.Name,
+					"description":  t.Description,
+					"input_schema": t.InputSchema,
+				})
+			}
+
+			return map[string]any{
+				"tools": tools,
+				"count": len(tools),
+			}, nil
+		},
+	)
+}
@@ -0,0 +1,41 @@
+package tool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+func TestToolIntrospection(t *testing.T) {
+	registry := adapter.NewToolRegistry(NewDateTimeTool(), NewMemoryTool())
+	introspect := NewToolIntrospectionTool(registry)
+	if err := registry.Register(introspect); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	list := func(input string) []map[string]any {
+		out, err := introspect.Handler(json.RawMessage(input))
+		if err != nil {
+			t.Fatalf("list_tools failed: %v", err)
+		}
+		return out.(map[string]any)["tools"].([]map[string]any)
+	}
+
+	tools := list(`{}`)
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %d: %v", len(tools), tools)
+	}
+	if tools[0]["name"] != "datetime" || tools[1]["name"] != "memory" {
+		t.Errorf("expected datetime and memory in registration order, got %v, %v", tools[0]["name"], tools[1]["name"])
+	}
+	if tools[0]["input_schema"] == nil || tools[0]["description"] == "" {
+		t.Errorf("expected description and schema, got %v", tools[0])
+	}
+
+	// Tools registered later are visible too
+	registry.Register(NewJSONQueryTool())
+	if tools := list(`{"query":"JSON"}`); len(tools) != 1 || tools[0]["name"] != "json_query" {
+		t.Errorf("expected query to match json_query only, got %v", tools)
+	}
+}
@@ -1,13 +1,20 @@
 package tool
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/dvictor357/blaze"
 	"github.com/dvictor357/blaze/adapter"
 )
 
@@ -17,9 +24,30 @@ import (
 // - Dot notation: .field.nested
 // - Array indexing: .array[0]
 // - Array slicing: .array[0:3]
-// - Wildcards: .array[*].name
+// - Wildcards: .array[*].name, flattened across levels (.a[*].b[*].c)
 // - Filtering: .array[?name=="foo"]
+// - Recursive descent: ..name
+// - JSON Pointer (RFC 6901): /array/0/name
+// - Deep merge and JSON Patch (RFC 6902) of whole documents
 func NewJSONQueryTool() adapter.Tool {
+	return NewJSONQueryToolWithConfig(DefaultJSONQueryConfig())
+}
+
+// JSONQueryConfig configures NewJSONQueryToolWithConfig
+type JSONQueryConfig struct {
+	MaxInputBytes int // max length of the json and json2 inputs (0 = unlimited)
+}
+
+// DefaultJSONQueryConfig provides sensible defaults
+func DefaultJSONQueryConfig() JSONQueryConfig {
+	return JSONQueryConfig{
+		MaxInputBytes: 4 << 20, // 4MB
+	}
+}
+
+// NewJSONQueryToolWithConfig creates a json_query tool that rejects inputs
+// larger than config.MaxInputBytes before parsing them
+func NewJSONQueryToolWithConfig(config JSONQueryConfig) adapter.Tool {
 	return adapter.NewTool(
 		"json_query",
 		"Query and extract data from JSON. Use dot notation to access fields (e.g., '.data.users[0].name'). Supports array indexing, slicing, wildcards, and filtering. Use this to parse API responses or extract specific fields from JSON data.",
@@ -32,28 +60,69 @@ func NewJSONQueryTool() adapter.Tool {
 				},
 				"query": map[string]any{
 					"type":        "string",
-					"description": "Query path using dot notation (e.g., '.data.items[0].name', '.users[*].email', '.items[?status==\"active\"]')",
+					"description": "Query path using dot notation (e.g., '.data.items[0].name', '.users[*].email', '.items[?status==\"active\"]') or a JSON Pointer starting with '/' (e.g., '/data/items/0/name')",
 				},
 				"action": map[string]any{
 					"type":        "string",
-					"enum":        []string{"get", "keys", "length", "type", "flatten", "unique"},
-					"description": "Action: 'get' (extract value), 'keys' (list keys), 'length' (count items), 'type' (get type), 'flatten' (flatten array), 'unique' (deduplicate array)",
+					"enum":        []string{"get", "keys", "length", "type", "flatten", "unique", "sort", "group_by", "merge", "patch"},
+					"description": "Action: 'get' (extract value), 'keys' (list keys), 'length' (count items), 'type' (get type), 'flatten' (flatten array), 'unique' (deduplicate array), 'sort' (sort array), 'group_by' (group objects by the 'by' field, optionally aggregating), 'merge' (deep merge json2 into json), 'patch' (apply the RFC 6902 patch in json2 to json)",
+				},
+				"by": map[string]any{
+					"type":        "string",
+					"description": "For 'sort' on arrays of objects: the field path to sort by (e.g., '.price' or '/meta/rank'). For 'group_by': the field path to group by",
+				},
+				"order": map[string]any{
+					"type":        "string",
+					"enum":        []string{"asc", "desc"},
+					"description": "Sort order for 'sort' (default 'asc')",
+				},
+				"agg": map[string]any{
+					"type":        "string",
+					"enum":        []string{"count", "sum", "avg", "min", "max"},
+					"description": "For 'group_by': aggregate each group instead of returning its items",
+				},
+				"agg_field": map[string]any{
+					"type":        "string",
+					"description": "For 'group_by' with sum/avg/min/max: the numeric field path to aggregate",
+				},
+				"json2": map[string]any{
+					"type":        "string",
+					"description": "Second JSON document for 'merge', or a JSON Patch array for 'patch'. Both act on the whole document; query is ignored.",
+				},
+				"mode": map[string]any{
+					"type":        "string",
+					"enum":        []string{"replace", "concat"},
+					"description": "How 'merge' combines arrays: 'replace' (default) uses the array from json2, 'concat' appends it",
 				},
 			},
-			"required": []string{"json", "query"},
+			"required": []string{"json"},
 		},
 		func(input json.RawMessage) (any, error) {
 			var data struct {
-				JSON   string `json:"json"`
-				Query  string `json:"query"`
-				Action string `json:"action"`
+				JSON     string `json:"json"`
+				Query    string `json:"query"`
+				Action   string `json:"action"`
+				By       string `json:"by"`
+				Order    string `json:"order"`
+				Agg      string `json:"agg"`
+				AggField string `json:"agg_field"`
+				JSON2    string `json:"json2"`
+				Mode     string `json:"mode"`
 			}
 			if err := json.Unmarshal(input, &data); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+				return nil, InvalidInput("invalid input: %w", err)
 			}
 
 			if data.JSON == "" {
-				return nil, fmt.Errorf("json cannot be empty")
+				return nil, InvalidInput("json cannot be empty")
+			}
+			if max := config.MaxInputBytes; max > 0 {
+				if len(data.JSON) > max {
+					return nil, InvalidInput("json is too large (%d bytes, max %d)", len(data.JSON), max)
+				}
+				if len(data.JSON2) > max {
+					return nil, InvalidInput("json2 is too large (%d bytes, max %d)", len(data.JSON2), max)
+				}
 			}
 
 			if data.Action == "" {
@@ -61,13 +130,40 @@ func NewJSONQueryTool() adapter.Tool {
 			}
 
 			// Parse the JSON
-			var jsonData any
-			if err := json.Unmarshal([]byte(data.JSON), &jsonData); err != nil {
-				return nil, fmt.Errorf("invalid JSON: %w", err)
+			jsonData, order, err := decodeOrdered([]byte(data.JSON))
+			if err != nil {
+				return nil, InvalidInput("invalid JSON: %w", err)
+			}
+			jsonData = exactNumbers(jsonData)
+
+			// Merge and patch transform the whole document
+			if data.Action == "merge" || data.Action == "patch" {
+				if data.JSON2 == "" {
+					return nil, InvalidInput("json2 is required for %s", data.Action)
+				}
+				var second any
+				if err := unmarshalNumbers([]byte(data.JSON2), &second); err != nil {
+					return nil, InvalidInput("invalid json2: %w", err)
+				}
+				second = exactNumbers(second)
+
+				var result any
+				var err error
+				if data.Action == "merge" {
+					result, err = mergeDocuments(jsonData, second, data.Mode)
+				} else {
+					result, err = applyPatch(jsonData, second)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					"result": result,
+				}, nil
 			}
 
 			// Execute the query
-			result, err := executeQuery(jsonData, data.Query)
+			result, err := executeOrderedQuery(jsonData, data.Query, order)
 			if err != nil {
 				return nil, err
 			}
@@ -122,44 +218,300 @@ func NewJSONQueryTool() adapter.Tool {
 					"result": unique,
 				}, nil
 
+			case "sort":
+				sorted, err := sortValues(result, data.By, data.Order)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					"result": sorted,
+				}, nil
+
+			case "group_by":
+				groups, skipped, err := groupValues(result, data.By, data.Agg, data.AggField)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					"groups":  groups,
+					"count":   len(groups),
+					"skipped": skipped,
+				}, nil
+
 			default:
-				return nil, fmt.Errorf("unknown action: %s", data.Action)
+				return nil, InvalidInput("unknown action: %s", data.Action)
 			}
 		},
 	)
 }
 
-// executeQuery parses and executes a query path on JSON data
+// exactNumbers replaces the json.Numbers in v with float64, except for
+// integers a float64 can't hold exactly. Those stay json.Number, so large
+// IDs keep every digit.
+func exactNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			if f := float64(n); int64(f) == n {
+				return f
+			}
+			return v
+		}
+		if !strings.ContainsAny(v.String(), ".eE") {
+			return v // an integer beyond int64
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, item := range v {
+			v[k] = exactNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = exactNumbers(item)
+		}
+	}
+	return v
+}
+
+// keyOrder holds the document order of the members of each object decoded
+// by decodeOrdered, keyed by the object's map pointer
+type keyOrder map[uintptr][]string
+
+// keys returns the members of m in document order, or sorted when m wasn't
+// recorded
+func (o keyOrder) keys(m map[string]any) []string {
+	if keys, ok := o[reflect.ValueOf(m).Pointer()]; ok && len(keys) == len(m) {
+		return keys
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeOrdered decodes a JSON document like unmarshalNumbers, also
+// recording the key order of its objects, which maps don't keep
+func decodeOrdered(data []byte) (any, keyOrder, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	order := keyOrder{}
+	v, err := decodeOrderedValue(dec, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, nil, errors.New("unexpected content after the value")
+	}
+	return v, order, nil
+}
+
+// decodeOrderedValue decodes the next value from dec's tokens
+func decodeOrderedValue(dec *json.Decoder, order keyOrder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := map[string]any{}
+		var keys []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+			v, err := decodeOrderedValue(dec, order)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := m[key]; !dup {
+				keys = append(keys, key)
+			}
+			m[key] = v
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		order[reflect.ValueOf(m).Pointer()] = keys
+		return m, nil
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			v, err := decodeOrderedValue(dec, order)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	return tok, nil
+}
+
+// executeQuery parses and executes a query path on JSON data. Recursive
+// descent visits object members in sorted key order.
 func executeQuery(data any, query string) (any, error) {
+	return executeOrderedQuery(data, query, nil)
+}
+
+// executeOrderedQuery is executeQuery with recursive descent visiting object
+// members in the document order recorded in order
+func executeOrderedQuery(data any, query string, order keyOrder) (any, error) {
 	if query == "" || query == "." {
 		return data, nil
 	}
+	if strings.HasPrefix(query, "/") {
+		return evaluatePointer(data, query)
+	}
 
-	// Remove leading dot if present
-	query = strings.TrimPrefix(query, ".")
+	// Remove leading dot if present, keeping a leading ".." (recursive descent)
+	if !strings.HasPrefix(query, "..") {
+		query = strings.TrimPrefix(query, ".")
+	}
 
 	// Split query into parts, handling array notation
 	parts := splitQueryPath(query)
 
 	current := data
+	projected := false
 	for _, part := range parts {
-		var err error
-		current, err = accessField(current, part)
+		if projected {
+			current = projectField(current.([]any), part, order)
+			continue
+		}
+
+		next, err := accessField(current, part, order)
 		if err != nil {
 			return nil, err
 		}
+		projected = isProjection(part, current)
+		current = next
 	}
 
 	return current, nil
 }
 
+// isProjection reports whether applying part to input yields a list of
+// matches rather than a single value: wildcards, filters, slices, recursive
+// descent, and field access on an array
+func isProjection(part string, input any) bool {
+	if strings.HasPrefix(part, "..") {
+		return true
+	}
+	if strings.HasPrefix(part, "[") {
+		inner := strings.TrimSuffix(part[1:], "]")
+		return inner == "*" || strings.HasPrefix(inner, "?") || strings.Contains(inner, ":")
+	}
+	_, isArray := input.([]any)
+	return isArray
+}
+
+// projectField applies part to each element of a projection. Steps that
+// themselves project ([*], filters, slices, fields of nested arrays) have
+// their matches flattened into the result, so chains like
+// .users[*].orders[*].total return one flat list. Elements the step doesn't
+// apply to (missing fields, out-of-range indexes) are dropped.
+func projectField(items []any, part string, order keyOrder) []any {
+	results := []any{}
+	for _, item := range items {
+		val, err := accessField(item, part, order)
+		if err != nil {
+			continue
+		}
+		if matches, ok := val.([]any); ok && isProjection(part, item) {
+			results = append(results, matches...)
+			continue
+		}
+		results = append(results, val)
+	}
+	return results
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, InvalidInput("invalid JSON pointer '%s': must start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		// ~1 must be decoded before ~0 so "~01" becomes "~1", not "/"
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// evaluatePointer resolves an RFC 6901 JSON Pointer against data
+func evaluatePointer(data any, pointer string) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := data
+	for _, token := range tokens {
+		switch v := current.(type) {
+		case map[string]any:
+			val, ok := v[token]
+			if !ok {
+				return nil, NotFound("field '%s' not found", token)
+			}
+			current = val
+
+		case []any:
+			idx, err := pointerIndex(token, len(v))
+			if err != nil {
+				return nil, err
+			}
+			current = v[idx]
+
+		default:
+			return nil, InvalidInput("cannot access '%s' on %s", token, getType(current))
+		}
+	}
+
+	return current, nil
+}
+
+// pointerIndex parses an array index token, which must be a non-negative
+// integer without leading zeros
+func pointerIndex(token string, length int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, InvalidInput("invalid array index: %s", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx >= length {
+		return 0, InvalidInput("index %s out of range (length: %d)", token, length)
+	}
+	return idx, nil
+}
+
 // splitQueryPath splits a query path into parts, handling array notation
 func splitQueryPath(query string) []string {
 	var parts []string
 	var current strings.Builder
 	inBracket := false
+	var prev rune
 
 	for _, ch := range query {
+		// A second dot in a row marks a recursive descent part, e.g. "..name"
+		if ch == '.' && prev == '.' && !inBracket && current.Len() == 0 {
+			current.WriteString("..")
+			prev = 0
+			continue
+		}
+		prev = ch
+
 		switch ch {
 		case '[':
 			if current.Len() > 0 {
@@ -192,10 +544,16 @@ func splitQueryPath(query string) []string {
 	return parts
 }
 
-// accessField accesses a single field or array element
-func accessField(data any, field string) (any, error) {
+// accessField accesses a single field or array element. order is the key
+// order recursive descent follows.
+func accessField(data any, field string, order keyOrder) (any, error) {
 	if data == nil {
-		return nil, fmt.Errorf("cannot access '%s' on null", field)
+		return nil, InvalidInput("cannot access '%s' on null", field)
+	}
+
+	// Recursive descent ..field
+	if strings.HasPrefix(field, "..") {
+		return collectField(data, field[2:], order, []any{}), nil
 	}
 
 	// Handle array access [n], [n:m], [*], [?filter]
@@ -227,7 +585,7 @@ func accessField(data any, field string) (any, error) {
 		if val, ok := v[field]; ok {
 			return val, nil
 		}
-		return nil, fmt.Errorf("field '%s' not found", field)
+		return nil, NotFound("field '%s' not found", field)
 
 	case []any:
 		// Apply field access to each element (implicit wildcard)
@@ -242,8 +600,28 @@ func accessField(data any, field string) (any, error) {
 		return results, nil
 
 	default:
-		return nil, fmt.Errorf("cannot access field '%s' on %T", field, data)
+		return nil, InvalidInput("cannot access field '%s' on %T", field, data)
+	}
+}
+
+// collectField appends every value of field found at any depth of data to
+// results, an object's own field before matches nested inside it. Arrays are
+// walked in order and object members in the order given by order.
+func collectField(data any, field string, order keyOrder, results []any) []any {
+	switch v := data.(type) {
+	case map[string]any:
+		if val, ok := v[field]; ok {
+			results = append(results, val)
+		}
+		for _, k := range order.keys(v) {
+			results = collectField(v[k], field, order, results)
+		}
+	case []any:
+		for _, item := range v {
+			results = collectField(item, field, order, results)
+		}
 	}
+	return results
 }
 
 func wildcardAccess(data any) (any, error) {
@@ -257,19 +635,19 @@ func wildcardAccess(data any) (any, error) {
 		}
 		return values, nil
 	default:
-		return nil, fmt.Errorf("wildcard requires array or object")
+		return nil, InvalidInput("wildcard requires array or object")
 	}
 }
 
 func indexAccess(data any, indexStr string) (any, error) {
 	arr, ok := data.([]any)
 	if !ok {
-		return nil, fmt.Errorf("cannot index non-array")
+		return nil, InvalidInput("cannot index non-array")
 	}
 
 	idx, err := strconv.Atoi(indexStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid index: %s", indexStr)
+		return nil, InvalidInput("invalid index: %s", indexStr)
 	}
 
 	// Support negative indexing
@@ -278,7 +656,7 @@ func indexAccess(data any, indexStr string) (any, error) {
 	}
 
 	if idx < 0 || idx >= len(arr) {
-		return nil, fmt.Errorf("index %d out of range (length: %d)", idx, len(arr))
+		return nil, InvalidInput("index %d out of range (length: %d)", idx, len(arr))
 	}
 
 	return arr[idx], nil
@@ -287,7 +665,7 @@ func indexAccess(data any, indexStr string) (any, error) {
 func sliceAccess(data any, sliceStr string) (any, error) {
 	arr, ok := data.([]any)
 	if !ok {
-		return nil, fmt.Errorf("cannot slice non-array")
+		return nil, InvalidInput("cannot slice non-array")
 	}
 
 	parts := strings.Split(sliceStr, ":")
@@ -298,7 +676,7 @@ func sliceAccess(data any, sliceStr string) (any, error) {
 		var err error
 		start, err = strconv.Atoi(parts[0])
 		if err != nil {
-			return nil, fmt.Errorf("invalid slice start: %s", parts[0])
+			return nil, InvalidInput("invalid slice start: %s", parts[0])
 		}
 	}
 
@@ -306,7 +684,7 @@ func sliceAccess(data any, sliceStr string) (any, error) {
 		var err error
 		end, err = strconv.Atoi(parts[1])
 		if err != nil {
-			return nil, fmt.Errorf("invalid slice end: %s", parts[1])
+			return nil, InvalidInput("invalid slice end: %s", parts[1])
 		}
 	}
 
@@ -327,14 +705,14 @@ func sliceAccess(data any, sliceStr string) (any, error) {
 func filterAccess(data any, condition string) (any, error) {
 	arr, ok := data.([]any)
 	if !ok {
-		return nil, fmt.Errorf("filter requires array")
+		return nil, InvalidInput("filter requires array")
 	}
 
 	// Parse condition: field=="value" or field==value
 	re := regexp.MustCompile(`(\w+)\s*(==|!=|>|<|>=|<=)\s*"?([^"]*)"?`)
 	matches := re.FindStringSubmatch(condition)
 	if len(matches) < 4 {
-		return nil, fmt.Errorf("invalid filter condition: %s", condition)
+		return nil, InvalidInput("invalid filter condition: %s", condition)
 	}
 
 	field := matches[1]
@@ -399,7 +777,7 @@ func getKeys(data any) ([]string, error) {
 		}
 		return keys, nil
 	default:
-		return nil, fmt.Errorf("keys requires an object, got %T", data)
+		return nil, InvalidInput("keys requires an object, got %T", data)
 	}
 }
 
@@ -412,7 +790,7 @@ func getLength(data any) (int, error) {
 	case string:
 		return len(v), nil
 	default:
-		return 0, fmt.Errorf("cannot get length of %T", data)
+		return 0, InvalidInput("cannot get length of %T", data)
 	}
 }
 
@@ -427,7 +805,7 @@ func getType(data any) string {
 		return "array"
 	case string:
 		return "string"
-	case float64:
+	case float64, json.Number:
 		return "number"
 	case bool:
 		return "boolean"
@@ -439,7 +817,7 @@ func getType(data any) string {
 func flatten(data any) ([]any, error) {
 	arr, ok := data.([]any)
 	if !ok {
-		return nil, fmt.Errorf("flatten requires an array")
+		return nil, InvalidInput("flatten requires an array")
 	}
 
 	var result []any
@@ -456,7 +834,7 @@ func flatten(data any) ([]any, error) {
 func uniqueValues(data any) ([]any, error) {
 	arr, ok := data.([]any)
 	if !ok {
-		return nil, fmt.Errorf("unique requires an array")
+		return nil, InvalidInput("unique requires an array")
 	}
 
 	seen := make(map[string]bool)
@@ -472,3 +850,486 @@ func uniqueValues(data any) ([]any, error) {
 
 	return result, nil
 }
+
+// sortValues stable-sorts an array. Scalars sort naturally; elements of
+// different types order as null < boolean < number < string < array < object.
+// Arrays of objects need a by path, whose value is the sort key.
+func sortValues(data any, by, order string) ([]any, error) {
+	arr, ok := data.([]any)
+	if !ok {
+		return nil, InvalidInput("sort requires an array")
+	}
+
+	desc := false
+	switch order {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return nil, InvalidInput("unknown sort order: %s", order)
+	}
+
+	keys := make([]any, len(arr))
+	for i, item := range arr {
+		if by == "" {
+			if _, isObject := item.(map[string]any); isObject {
+				return nil, InvalidInput("sort of objects requires 'by'")
+			}
+			keys[i] = item
+			continue
+		}
+		// Elements missing the field sort as null
+		keys[i], _ = executeQuery(item, by)
+	}
+
+	// Sort indexes so each element moves with its key
+	idx := make([]int, len(arr))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		c := compareValues(keys[a], keys[b])
+		if desc {
+			return -c
+		}
+		return c
+	})
+
+	sorted := make([]any, len(arr))
+	for i, j := range idx {
+		sorted[i] = arr[j]
+	}
+	return sorted, nil
+}
+
+// typeRank orders JSON types for sorting mixed arrays
+func typeRank(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64, json.Number:
+		return 2
+	case string:
+		return 3
+	case []any:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// compareValues compares two decoded JSON values, first by type rank, then
+// by value. Arrays and objects of the same type compare equal.
+func compareValues(a, b any) int {
+	if ra, rb := typeRank(a), typeRank(b); ra != rb {
+		return ra - rb
+	}
+	switch av := a.(type) {
+	case bool:
+		bv := b.(bool)
+		if av == bv {
+			return 0
+		}
+		if !av {
+			return -1
+		}
+		return 1
+	case float64, json.Number:
+		return compareNumbers(av, b)
+	case string:
+		return naturalCompare(av, b.(string))
+	default:
+		return 0
+	}
+}
+
+// naturalCompare compares strings treating runs of digits as numbers, so
+// "item2" sorts before "item10"
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := digitPrefix(a), digitPrefix(b)
+			// Compare by magnitude without parsing, so long runs can't overflow
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) - len(tb)
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			a, b = a[len(na):], b[len(nb):]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// digitPrefix returns the leading run of ASCII digits in s
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+// groupValues groups an array of objects by the value at the by path. Items
+// that aren't objects, or whose by value is missing or null, are skipped and
+// counted. Without agg each group maps to its items; otherwise to the
+// group's count, or the sum/avg/min/max of the numeric values at aggField
+// (items without a number there are ignored; empty avg/min/max are null).
+func groupValues(data any, by, agg, aggField string) (map[string]any, int, error) {
+	arr, ok := data.([]any)
+	if !ok {
+		return nil, 0, InvalidInput("group_by requires an array")
+	}
+	if by == "" {
+		return nil, 0, InvalidInput("group_by requires 'by'")
+	}
+	switch agg {
+	case "", "count":
+	case "sum", "avg", "min", "max":
+		if aggField == "" {
+			return nil, 0, InvalidInput("agg '%s' requires 'agg_field'", agg)
+		}
+	default:
+		return nil, 0, InvalidInput("unknown agg: %s", agg)
+	}
+
+	groups := make(map[string][]any)
+	skipped := 0
+	for _, item := range arr {
+		if _, isObject := item.(map[string]any); !isObject {
+			skipped++
+			continue
+		}
+		key, err := executeQuery(item, by)
+		if err != nil || key == nil {
+			skipped++
+			continue
+		}
+		name := groupKey(key)
+		groups[name] = append(groups[name], item)
+	}
+
+	result := make(map[string]any, len(groups))
+	for name, items := range groups {
+		switch agg {
+		case "":
+			result[name] = items
+		case "count":
+			result[name] = len(items)
+		default:
+			result[name] = aggregate(items, agg, aggField)
+		}
+	}
+	return result, skipped, nil
+}
+
+// groupKey names a group: strings as-is, other values by their JSON encoding
+func groupKey(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// compareNumbers compares two decoded JSON numbers, exactly when both are
+// integers
+func compareNumbers(a, b any) int {
+	if ai, ok := blaze.JSONInt64(a); ok {
+		if bi, ok := blaze.JSONInt64(b); ok {
+			return cmp.Compare(ai, bi)
+		}
+	}
+	af, _ := blaze.JSONFloat64(a)
+	bf, _ := blaze.JSONFloat64(b)
+	return cmp.Compare(af, bf)
+}
+
+// aggregate computes sum, avg, min or max over the numbers at field in items
+func aggregate(items []any, agg, field string) any {
+	var nums []float64
+	for _, item := range items {
+		if n, err := executeQuery(item, field); err == nil {
+			if f, ok := blaze.JSONFloat64(n); ok {
+				nums = append(nums, f)
+			}
+		}
+	}
+
+	sum := 0.0
+	for _, n := range nums {
+		sum += n
+	}
+	if agg == "sum" {
+		return sum
+	}
+	if len(nums) == 0 {
+		return nil
+	}
+	switch agg {
+	case "avg":
+		return sum / float64(len(nums))
+	case "min":
+		return slices.Min(nums)
+	default:
+		return slices.Max(nums)
+	}
+}
+
+// mergeDocuments deep merges src into dst. Objects merge recursively; arrays
+// are replaced by src's array, or appended to in "concat" mode.
+func mergeDocuments(dst, src any, mode string) (any, error) {
+	switch mode {
+	case "", "replace":
+		return deepMerge(dst, src, false), nil
+	case "concat":
+		return deepMerge(dst, src, true), nil
+	default:
+		return nil, InvalidInput("unknown merge mode: %s", mode)
+	}
+}
+
+func deepMerge(dst, src any, concat bool) any {
+	switch s := src.(type) {
+	case map[string]any:
+		d, ok := dst.(map[string]any)
+		if !ok {
+			return s
+		}
+		for k, v := range s {
+			if existing, ok := d[k]; ok {
+				d[k] = deepMerge(existing, v, concat)
+			} else {
+				d[k] = v
+			}
+		}
+		return d
+	case []any:
+		if d, ok := dst.([]any); ok && concat {
+			return append(d, s...)
+		}
+		return s
+	default:
+		return src
+	}
+}
+
+// applyPatch applies an RFC 6902 JSON Patch to doc and returns the result
+func applyPatch(doc any, patch any) (any, error) {
+	ops, ok := patch.([]any)
+	if !ok {
+		return nil, InvalidInput("patch must be an array of operations")
+	}
+
+	for i, raw := range ops {
+		op, ok := raw.(map[string]any)
+		if !ok {
+			return nil, InvalidInput("patch operation %d must be an object", i)
+		}
+		name, _ := op["op"].(string)
+		path, ok := op["path"].(string)
+		if !ok {
+			return nil, InvalidInput("patch operation %d (%s): missing path", i, name)
+		}
+
+		var err error
+		doc, err = applyPatchOp(doc, name, path, op)
+		if err != nil {
+			return nil, InvalidInput("patch operation %d (%s): %w", i, name, err)
+		}
+	}
+	return doc, nil
+}
+
+func applyPatchOp(doc any, name, path string, op map[string]any) (any, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "add":
+		value, ok := op["value"]
+		if !ok {
+			return nil, InvalidInput("missing value")
+		}
+		return addAt(doc, tokens, value)
+
+	case "remove":
+		return removeAt(doc, tokens)
+
+	case "replace":
+		value, ok := op["value"]
+		if !ok {
+			return nil, InvalidInput("missing value")
+		}
+		if len(tokens) == 0 {
+			return value, nil
+		}
+		if doc, err = removeAt(doc, tokens); err != nil {
+			return nil, err
+		}
+		return addAt(doc, tokens, value)
+
+	case "move", "copy":
+		from, ok := op["from"].(string)
+		if !ok {
+			return nil, InvalidInput("missing from")
+		}
+		value, err := evaluatePointer(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if name == "copy" {
+			value = deepCopy(value)
+		} else {
+			if strings.HasPrefix(path, from+"/") {
+				return nil, InvalidInput("cannot move '%s' into its own child '%s'", from, path)
+			}
+			fromTokens, _ := parsePointer(from)
+			if doc, err = removeAt(doc, fromTokens); err != nil {
+				return nil, err
+			}
+		}
+		return addAt(doc, tokens, value)
+
+	case "test":
+		value, err := evaluatePointer(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, op["value"]) {
+			return nil, InvalidInput("test failed: value at '%s' is %v", path, value)
+		}
+		return doc, nil
+
+	default:
+		return nil, InvalidInput("unknown op")
+	}
+}
+
+// addAt adds value at the location given by pointer tokens, inserting into
+// arrays ("-" appends) and setting object members
+func addAt(node any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token, rest := tokens[0], tokens[1:]
+
+	switch v := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			v[token] = value
+			return v, nil
+		}
+		child, ok := v[token]
+		if !ok {
+			return nil, NotFound("field '%s' not found", token)
+		}
+		updated, err := addAt(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		v[token] = updated
+		return v, nil
+
+	case []any:
+		if len(rest) == 0 {
+			idx := len(v)
+			if token != "-" {
+				var err error
+				// Inserting at len(v) is allowed, so bound by one past the end
+				if idx, err = pointerIndex(token, len(v)+1); err != nil {
+					return nil, err
+				}
+			}
+			return slices.Insert(v, idx, value), nil
+		}
+		idx, err := pointerIndex(token, len(v))
+		if err != nil {
+			return nil, err
+		}
+		if v[idx], err = addAt(v[idx], rest, value); err != nil {
+			return nil, err
+		}
+		return v, nil
+
+	default:
+		return nil, InvalidInput("cannot access '%s' on %s", token, getType(node))
+	}
+}
+
+// removeAt removes the value at the location given by pointer tokens
+func removeAt(node any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, InvalidInput("cannot remove the whole document")
+	}
+	token, rest := tokens[0], tokens[1:]
+
+	switch v := node.(type) {
+	case map[string]any:
+		child, ok := v[token]
+		if !ok {
+			return nil, NotFound("field '%s' not found", token)
+		}
+		if len(rest) == 0 {
+			delete(v, token)
+			return v, nil
+		}
+		updated, err := removeAt(child, rest)
+		if err != nil {
+			return nil, err
+		}
+		v[token] = updated
+		return v, nil
+
+	case []any:
+		idx, err := pointerIndex(token, len(v))
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			return slices.Delete(v, idx, idx+1), nil
+		}
+		if v[idx], err = removeAt(v[idx], rest); err != nil {
+			return nil, err
+		}
+		return v, nil
+
+	default:
+		return nil, InvalidInput("cannot access '%s' on %s", token, getType(node))
+	}
+}
+
+// deepCopy returns a copy of a decoded JSON value that shares no maps or slices
+func deepCopy(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = deepCopy(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = deepCopy(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,320 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONQuery_PointerIntoNestedArray(t *testing.T) {
+	doc := `{"data": {"users": [{"name": "ada"}, {"name": "linus", "tags": ["a", "b"]}]}}`
+
+	if got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": "/data/users/0/name"})["result"]; got != "ada" {
+		t.Errorf("expected ada, got %v", got)
+	}
+	if got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": "/data/users/1/tags/1"})["result"]; got != "b" {
+		t.Errorf("expected b, got %v", got)
+	}
+
+	// The dot syntax keeps working alongside pointers
+	if got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": ".data.users[1].name"})["result"]; got != "linus" {
+		t.Errorf("expected linus, got %v", got)
+	}
+
+	for _, bad := range []string{"/data/users/01", "/data/users/2", "/data/users/-", "/data/missing"} {
+		raw, _ := json.Marshal(map[string]any{"json": doc, "query": bad})
+		if _, err := NewJSONQueryTool().Handler(raw); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+func TestJSONQuery_PointerEscapes(t *testing.T) {
+	doc := `{"a/b": 1, "m~n": 2, "~1": 3, "": {"": 4}}`
+
+	cases := map[string]any{
+		"/a~1b": 1.0,
+		"/m~0n": 2.0,
+		"/~01":  3.0, // ~01 decodes to the literal key "~1"
+		"//":    4.0, // empty reference tokens address empty keys
+	}
+	for query, want := range cases {
+		if got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": query})["result"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", query, want, got)
+		}
+	}
+}
+
+// decodeJSON parses s for comparisons against tool results
+func decodeJSON(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("bad fixture %s: %v", s, err)
+	}
+	return v
+}
+
+func TestJSONQuery_MergeDeepObjects(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "merge",
+		"json":   `{"server": {"host": "localhost", "port": 80, "tls": {"enabled": false}}, "tags": ["a"]}`,
+		"json2":  `{"server": {"port": 8080, "tls": {"enabled": true, "cert": "c.pem"}}, "tags": ["b"], "debug": true}`,
+	})["result"]
+
+	want := decodeJSON(t, `{"server": {"host": "localhost", "port": 8080, "tls": {"enabled": true, "cert": "c.pem"}}, "tags": ["b"], "debug": true}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJSONQuery_MergeConcatArrays(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "merge",
+		"mode":   "concat",
+		"json":   `{"plugins": ["lint"], "env": {"paths": ["/bin"]}}`,
+		"json2":  `{"plugins": ["test"], "env": {"paths": ["/usr/bin"]}}`,
+	})["result"]
+
+	want := decodeJSON(t, `{"plugins": ["lint", "test"], "env": {"paths": ["/bin", "/usr/bin"]}}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJSONQuery_PatchAddRemove(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "patch",
+		"json":   `{"name": "app", "features": ["a", "c"], "legacy": true}`,
+		"json2": `[
+			{"op": "add", "path": "/features/1", "value": "b"},
+			{"op": "add", "path": "/features/-", "value": "d"},
+			{"op": "add", "path": "/version", "value": 2},
+			{"op": "remove", "path": "/legacy"},
+			{"op": "test", "path": "/name", "value": "app"}
+		]`,
+	})["result"]
+
+	want := decodeJSON(t, `{"name": "app", "features": ["a", "b", "c", "d"], "version": 2}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// A failing operation aborts the whole patch
+	raw, _ := json.Marshal(map[string]any{
+		"action": "patch",
+		"json":   `{"a": 1}`,
+		"json2":  `[{"op": "remove", "path": "/missing"}]`,
+	})
+	if _, err := NewJSONQueryTool().Handler(raw); err == nil {
+		t.Errorf("expected error removing a missing field")
+	}
+}
+
+func TestJSONQuery_SortScalars(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "sort",
+		"json":   `["item10", 3, "item2", null, 1.5, true, "Item1", false, "item2"]`,
+	})["result"]
+
+	want := decodeJSON(t, `[null, false, true, 1.5, 3, "Item1", "item2", "item2", "item10"]`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJSONQuery_SortObjectsByField(t *testing.T) {
+	doc := `{"items": [
+		{"name": "c", "meta": {"price": 30}},
+		{"name": "a", "meta": {"price": 10}},
+		{"name": "b", "meta": {"price": 20}},
+		{"name": "d", "meta": {"price": 10}}
+	]}`
+
+	names := func(v any) []string {
+		var out []string
+		for _, item := range v.([]any) {
+			out = append(out, item.(map[string]any)["name"].(string))
+		}
+		return out
+	}
+
+	// Ties keep their original order
+	got := runTool(t, NewJSONQueryTool(), map[string]any{"action": "sort", "json": doc, "query": ".items", "by": ".meta.price"})["result"]
+	if want := []string{"a", "d", "b", "c"}; !reflect.DeepEqual(names(got), want) {
+		t.Errorf("expected %v, got %v", want, names(got))
+	}
+
+	raw, _ := json.Marshal(map[string]any{"action": "sort", "json": doc, "query": ".items"})
+	if _, err := NewJSONQueryTool().Handler(raw); err == nil {
+		t.Errorf("expected error sorting objects without 'by'")
+	}
+}
+
+func TestJSONQuery_SortDescending(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "sort",
+		"order":  "desc",
+		"json":   `[{"n": "x", "score": 2}, {"n": "y", "score": 5}, {"n": "z", "score": 2}]`,
+		"by":     "score",
+	})["result"]
+
+	want := decodeJSON(t, `[{"n": "y", "score": 5}, {"n": "x", "score": 2}, {"n": "z", "score": 2}]`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+const groupDoc = `{"users": [
+	{"name": "a", "country": "FR", "spent": 10},
+	{"name": "b", "country": "US", "spent": 5},
+	{"name": "c", "country": "FR", "spent": 2.5},
+	{"name": "d", "spent": 100},
+	{"name": "e", "country": "US"}
+]}`
+
+func TestJSONQuery_GroupByCount(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "group_by", "json": groupDoc, "query": ".users", "by": "country", "agg": "count",
+	})
+
+	want := map[string]any{"FR": 2, "US": 2}
+	if !reflect.DeepEqual(got["groups"], want) {
+		t.Errorf("expected %v, got %v", want, got["groups"])
+	}
+	// The user without a country is skipped, not grouped
+	if got["skipped"] != 1 {
+		t.Errorf("expected 1 skipped item, got %v", got["skipped"])
+	}
+
+	// Without agg each group holds its items
+	items := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "group_by", "json": groupDoc, "query": ".users", "by": "country",
+	})["groups"].(map[string]any)
+	if fr := items["FR"].([]any); len(fr) != 2 || fr[1].(map[string]any)["name"] != "c" {
+		t.Errorf("expected FR items a and c, got %v", items["FR"])
+	}
+}
+
+func TestJSONQuery_GroupBySum(t *testing.T) {
+	got := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "group_by", "json": groupDoc, "query": ".users", "by": "country", "agg": "sum", "agg_field": "spent",
+	})["groups"]
+
+	// e has no spent value and contributes nothing to the US sum
+	want := map[string]any{"FR": 12.5, "US": 5.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	avg := runTool(t, NewJSONQueryTool(), map[string]any{
+		"action": "group_by", "json": groupDoc, "query": ".users", "by": "country", "agg": "avg", "agg_field": "spent",
+	})["groups"].(map[string]any)
+	if avg["FR"] != 6.25 {
+		t.Errorf("expected FR avg 6.25, got %v", avg["FR"])
+	}
+}
+
+const ordersDoc = `{"users": [
+	{"name": "a", "orders": [{"total": 1}, {"total": 2}], "address": {"geo": {"lat": 10}}},
+	{"name": "b", "orders": [], "address": {"geo": {"lat": 20}}},
+	{"name": "c", "orders": [{"total": 3}]}
+]}`
+
+func TestJSONQuery_TwoLevelWildcard(t *testing.T) {
+	cases := map[string]string{
+		".users[*].orders[*].total": `[1, 2, 3]`,
+		".users[*].orders.total":    `[1, 2, 3]`, // implicit wildcard over nested arrays flattens too
+		".users[*].orders[0].total": `[1, 3]`,    // users without a first order are dropped
+		".users[*].orders[*]":       `[{"total": 1}, {"total": 2}, {"total": 3}]`,
+	}
+	for query, want := range cases {
+		got := runTool(t, NewJSONQueryTool(), map[string]any{"json": ordersDoc, "query": query})["result"]
+		if !reflect.DeepEqual(got, decodeJSON(t, want)) {
+			t.Errorf("%s: expected %s, got %v", query, want, got)
+		}
+	}
+}
+
+func TestJSONQuery_WildcardThroughObjects(t *testing.T) {
+	// address.geo is an object at each level; users without one are dropped
+	got := runTool(t, NewJSONQueryTool(), map[string]any{"json": ordersDoc, "query": ".users[*].address.geo.lat"})["result"]
+	if want := decodeJSON(t, `[10, 20]`); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJSONQuery_RecursiveDescent(t *testing.T) {
+	doc := `{
+		"id": 1,
+		"items": [
+			{"id": 2, "children": [{"id": 3}, {"name": "x"}]},
+			{"id": 4}
+		],
+		"meta": {"owner": {"id": 5}}
+	}`
+
+	cases := map[string]string{
+		"..id":          `[1, 2, 3, 4, 5]`,
+		".items..id":    `[2, 3, 4]`,
+		".items[*]..id": `[2, 3, 4]`,
+		".meta..owner":  `[{"id": 5}]`,
+	}
+	for query, want := range cases {
+		got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": query})["result"]
+		if !reflect.DeepEqual(got, decodeJSON(t, want)) {
+			t.Errorf("%s: expected %s, got %v", query, want, got)
+		}
+	}
+}
+
+func TestJSONQuery_RecursiveDescentDocumentOrder(t *testing.T) {
+	doc := `{"zeta": {"id": 1}, "alpha": {"id": 2}, "mid": [{"id": 3}], "id": 0}`
+	got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": "..id"})["result"]
+	if want := []any{0.0, 1.0, 2.0, 3.0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v in document order, got %v", want, got)
+	}
+}
+
+func TestJSONQuery_RecursiveDescentAbsent(t *testing.T) {
+	out := runTool(t, NewJSONQueryTool(), map[string]any{"json": `{"a": [{"b": 1}]}`, "query": "..missing"})
+
+	got, ok := out["result"].([]any)
+	if !ok || len(got) != 0 {
+		t.Errorf("expected empty array, got %#v", out["result"])
+	}
+	if b, _ := json.Marshal(out["result"]); string(b) != "[]" {
+		t.Errorf("expected [] when encoded, got %s", b)
+	}
+}
+
+func TestJSONQuery_MaxInputBytes(t *testing.T) {
+	q := NewJSONQueryToolWithConfig(JSONQueryConfig{MaxInputBytes: 64})
+
+	big, _ := json.Marshal(map[string]any{"json": `{"data": "` + strings.Repeat("x", 100) + `"}`, "query": ".data"})
+	_, err := q.Handler(big)
+	if err == nil || !strings.Contains(err.Error(), "json is too large") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+
+	small, _ := json.Marshal(map[string]any{"json": `{"data": "ok"}`, "query": ".data"})
+	if _, err := q.Handler(small); err != nil {
+		t.Errorf("expected input under the cap to pass, got %v", err)
+	}
+}
+
+func TestJSONQuery_LargeIntegers(t *testing.T) {
+	doc := `{"items": [{"id": 12345678901234567}, {"id": 12345678901234566}, {"id": 1.5}]}`
+
+	got := runTool(t, NewJSONQueryTool(), map[string]any{"json": doc, "query": ".items[0].id"})["result"]
+	if b, _ := json.Marshal(got); string(b) != "12345678901234567" {
+		t.Errorf("expected 12345678901234567, got %s", b)
+	}
+
+	// IDs that differ in the last digit still sort apart
+	sorted := runTool(t, NewJSONQueryTool(), map[string]any{"action": "sort", "json": doc, "query": ".items", "by": "id"})["result"]
+	if b, _ := json.Marshal(sorted); string(b) != `[{"id":1.5},{"id":12345678901234566},{"id":12345678901234567}]` {
+		t.Errorf("unexpected order %s", b)
+	}
+}
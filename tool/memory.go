@@ -1,43 +1,66 @@
 package tool
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dvictor357/blaze"
 	"github.com/dvictor357/blaze/adapter"
 )
 
 // MemoryStore is an in-memory key-value store with TTL support.
 // It persists data for the lifetime of the process.
 type MemoryStore struct {
-	mu    sync.RWMutex
-	data  map[string]memoryEntry
-	lists map[string][]any
+	mu     sync.RWMutex
+	data   map[string]memoryEntry
+	lists  map[string][]any
+	topics map[string]map[chan any]struct{} // subscribers waiting on each topic
 }
 
 type memoryEntry struct {
 	Value     any       `json:"value"`
 	CreatedAt time.Time `json:"created_at"`
-	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
 	TTL       int       `json:"ttl_seconds,omitempty"`
 }
 
+// expired reports whether the entry's TTL has passed
+func (e memoryEntry) expired() bool {
+	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+}
+
 // Global memory store instance
-var globalMemory = &MemoryStore{
-	data:  make(map[string]memoryEntry),
-	lists: make(map[string][]any),
+var globalMemory = NewMemoryStore()
+
+// NewMemoryStore creates an empty MemoryStore. It can back adapter sessions
+// via adapter.WithSessions.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		data:   make(map[string]memoryEntry),
+		lists:  make(map[string][]any),
+		topics: make(map[string]map[chan any]struct{}),
+	}
+}
+
+// Memory returns the store used by the memory tool
+func Memory() *MemoryStore {
+	return globalMemory
 }
 
 // NewMemoryTool creates a tool for storing and retrieving data in memory.
 // This allows the AI to persist information across tool calls within a session.
 // Supports:
 // - Key-value storage with optional TTL
-// - Lists (append, pop, range)
+// - Lists as queues or stacks (lpush/rpush, lpop/rpop, range)
 // - Counters (increment, decrement)
+// - Pub/sub between tool calls (publish, subscribe)
+// - Snapshots of the whole store (export, import)
 func NewMemoryTool() adapter.Tool {
-	return adapter.NewTool(
+	t := adapter.NewContextTool(
 		"memory",
 		"Store and retrieve data in memory. Use this to remember information across tool calls, create lists, or track counters. Data persists for the server lifetime.",
 		map[string]any{
@@ -45,8 +68,8 @@ func NewMemoryTool() adapter.Tool {
 			"properties": map[string]any{
 				"action": map[string]any{
 					"type":        "string",
-					"enum":        []string{"set", "get", "delete", "list", "keys", "clear", "incr", "decr", "append", "pop", "lrange", "llen"},
-					"description": "Action: 'set/get/delete' for key-value, 'incr/decr' for counters, 'append/pop/lrange/llen' for lists, 'keys' to list all keys, 'list' to dump all, 'clear' to reset",
+					"enum":        []string{"set", "get", "mset", "mget", "delete", "list", "keys", "clear", "incr", "decr", "append", "pop", "lpush", "rpush", "lpop", "rpop", "lrange", "llen", "publish", "subscribe", "export", "import"},
+					"description": "Action: 'set/get/delete' for key-value, 'mset/mget' to set or get many keys at once, 'incr/decr' for counters, 'append/pop/lrange/llen' for lists, 'keys' to list all keys, 'list' to dump all, 'clear' to reset. Lists run head (left, index 0) to tail (right): 'lpush'/'lpop' add/remove at the head, 'rpush'/'rpop' at the tail ('append' = rpush, 'pop' = rpop). Use rpush+lpop for a FIFO queue, rpush+rpop for a LIFO stack. 'publish' sends value to the current subscribers of the topic named by key; 'subscribe' waits up to timeout seconds for the next message. 'export' returns a snapshot of the whole store; 'import' loads one (merging unless replace is true)",
 				},
 				"key": map[string]any{
 					"type":        "string",
@@ -57,7 +80,24 @@ func NewMemoryTool() adapter.Tool {
 				},
 				"ttl": map[string]any{
 					"type":        "integer",
-					"description": "Time-to-live in seconds (0 = no expiry)",
+					"description": "Time-to-live in seconds (0 = no expiry), shared by all keys for mset",
+				},
+				"values": map[string]any{
+					"type":        "object",
+					"description": "Key-value pairs for mset",
+				},
+				"pattern": map[string]any{
+					"type":        "string",
+					"description": "Glob filter for keys: '*' matches any characters, '?' one character (e.g., 'user:*')",
+				},
+				"prefix": map[string]any{
+					"type":        "string",
+					"description": "Prefix filter for keys",
+				},
+				"keys": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Keys for mget",
 				},
 				"start": map[string]any{
 					"type":        "integer",
@@ -67,43 +107,75 @@ func NewMemoryTool() adapter.Tool {
 					"type":        "integer",
 					"description": "End index for lrange (default: -1 for all)",
 				},
+				"timeout": map[string]any{
+					"type":        "number",
+					"description": "Seconds subscribe waits for a message (default: 10, max: 60)",
+				},
+				"snapshot": map[string]any{
+					"type":        "object",
+					"description": "Store snapshot from export, for import",
+				},
+				"replace": map[string]any{
+					"type":        "boolean",
+					"description": "For import: clear the store first instead of merging (default: false)",
+				},
 			},
 			"required": []string{"action"},
 		},
-		func(input json.RawMessage) (any, error) {
+		func(ctx context.Context, input json.RawMessage) (any, error) {
 			var data struct {
-				Action string `json:"action"`
-				Key    string `json:"key"`
-				Value  any    `json:"value"`
-				TTL    int    `json:"ttl"`
-				Start  int    `json:"start"`
-				End    int    `json:"end"`
+				Action   string          `json:"action"`
+				Key      string          `json:"key"`
+				Value    any             `json:"value"`
+				TTL      int             `json:"ttl"`
+				Values   map[string]any  `json:"values"`
+				Keys     []string        `json:"keys"`
+				Pattern  string          `json:"pattern"`
+				Prefix   string          `json:"prefix"`
+				Start    int             `json:"start"`
+				End      int             `json:"end"`
+				Timeout  float64         `json:"timeout"`
+				Snapshot json.RawMessage `json:"snapshot"`
+				Replace  bool            `json:"replace"`
 			}
-			if err := json.Unmarshal(input, &data); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			// Values keep their numbers as json.Number, so large IDs keep every digit
+			if err := BindInputNumbers(input, &data); err != nil {
+				return nil, err
 			}
 
 			switch data.Action {
 			case "set":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for set")
+					return nil, InvalidInput("key is required for set")
 				}
 				return globalMemory.Set(data.Key, data.Value, data.TTL)
 
 			case "get":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for get")
+					return nil, InvalidInput("key is required for get")
 				}
 				return globalMemory.Get(data.Key)
 
+			case "mset":
+				if len(data.Values) == 0 {
+					return nil, InvalidInput("values is required for mset")
+				}
+				return globalMemory.MSet(data.Values, data.TTL)
+
+			case "mget":
+				if len(data.Keys) == 0 {
+					return nil, InvalidInput("keys is required for mget")
+				}
+				return globalMemory.MGet(data.Keys)
+
 			case "delete":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for delete")
+					return nil, InvalidInput("key is required for delete")
 				}
 				return globalMemory.Delete(data.Key)
 
 			case "keys":
-				return globalMemory.Keys()
+				return globalMemory.KeysMatching(data.Pattern, data.Prefix)
 
 			case "list":
 				return globalMemory.List()
@@ -113,43 +185,43 @@ func NewMemoryTool() adapter.Tool {
 
 			case "incr":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for incr")
+					return nil, InvalidInput("key is required for incr")
 				}
-				amount := 1
-				if data.Value != nil {
-					if v, ok := data.Value.(float64); ok {
-						amount = int(v)
-					}
-				}
-				return globalMemory.Incr(data.Key, amount)
+				return globalMemory.Incr(data.Key, incrAmount(input))
 
 			case "decr":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for decr")
-				}
-				amount := 1
-				if data.Value != nil {
-					if v, ok := data.Value.(float64); ok {
-						amount = int(v)
-					}
+					return nil, InvalidInput("key is required for decr")
 				}
-				return globalMemory.Incr(data.Key, -amount)
+				return globalMemory.Incr(data.Key, -incrAmount(input))
 
-			case "append":
+			case "append", "rpush":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for append")
+					return nil, InvalidInput("key is required for %s", data.Action)
 				}
 				return globalMemory.ListAppend(data.Key, data.Value)
 
-			case "pop":
+			case "lpush":
+				if data.Key == "" {
+					return nil, InvalidInput("key is required for lpush")
+				}
+				return globalMemory.ListPrepend(data.Key, data.Value)
+
+			case "pop", "rpop":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for pop")
+					return nil, InvalidInput("key is required for %s", data.Action)
 				}
 				return globalMemory.ListPop(data.Key)
 
+			case "lpop":
+				if data.Key == "" {
+					return nil, InvalidInput("key is required for lpop")
+				}
+				return globalMemory.ListPopFront(data.Key)
+
 			case "lrange":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for lrange")
+					return nil, InvalidInput("key is required for lrange")
 				}
 				end := -1
 				if data.End != 0 {
@@ -159,15 +231,55 @@ func NewMemoryTool() adapter.Tool {
 
 			case "llen":
 				if data.Key == "" {
-					return nil, fmt.Errorf("key is required for llen")
+					return nil, InvalidInput("key is required for llen")
 				}
 				return globalMemory.ListLen(data.Key)
 
+			case "publish":
+				if data.Key == "" {
+					return nil, InvalidInput("key is required for publish")
+				}
+				return globalMemory.Publish(data.Key, data.Value)
+
+			case "subscribe":
+				if data.Key == "" {
+					return nil, InvalidInput("key is required for subscribe")
+				}
+				timeout := 10 * time.Second
+				if data.Timeout > 0 {
+					timeout = time.Duration(min(data.Timeout, maxSubscribeSeconds) * float64(time.Second))
+				}
+				return globalMemory.SubscribeContext(ctx, data.Key, timeout)
+
+			case "export":
+				return globalMemory.Export()
+
+			case "import":
+				if len(data.Snapshot) == 0 {
+					return nil, InvalidInput("snapshot is required for import")
+				}
+				return globalMemory.Import(data.Snapshot, data.Replace)
+
 			default:
-				return nil, fmt.Errorf("unknown action: %s", data.Action)
+				return nil, InvalidInput("unknown action: %s", data.Action)
 			}
 		},
 	)
+	t.SideEffect = true // writes to the shared store
+	t.ReadOnly = memoryReadOnly
+	return t
+}
+
+// memoryReadActions are the memory actions that leave the store unchanged
+var memoryReadActions = []string{"get", "mget", "keys", "list", "lrange", "llen", "export"}
+
+// memoryReadOnly reports whether a memory call only reads, so dry runs
+// still run it
+func memoryReadOnly(input json.RawMessage) bool {
+	var data struct {
+		Action string `json:"action"`
+	}
+	return json.Unmarshal(input, &data) == nil && slices.Contains(memoryReadActions, data.Action)
 }
 
 // Set stores a value with optional TTL
@@ -238,6 +350,55 @@ func (m *MemoryStore) Get(key string) (map[string]any, error) {
 	return result, nil
 }
 
+// MSet stores several values with a shared optional TTL in one atomic step
+func (m *MemoryStore) MSet(values map[string]any, ttlSeconds int) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for key, value := range values {
+		entry := memoryEntry{
+			Value:     value,
+			CreatedAt: now,
+		}
+		if ttlSeconds > 0 {
+			entry.ExpiresAt = now.Add(time.Duration(ttlSeconds) * time.Second)
+			entry.TTL = ttlSeconds
+		}
+		m.data[key] = entry
+	}
+
+	return map[string]any{
+		"success": true,
+		"count":   len(values),
+		"ttl":     ttlSeconds,
+	}, nil
+}
+
+// MGet retrieves several values from one consistent snapshot. Keys that are
+// missing or expired are left out of values and listed in missing.
+func (m *MemoryStore) MGet(keys []string) (map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	values := make(map[string]any, len(keys))
+	missing := []string{}
+	for _, key := range keys {
+		entry, exists := m.data[key]
+		if !exists || entry.expired() {
+			missing = append(missing, key)
+			continue
+		}
+		values[key] = entry.Value
+	}
+
+	return map[string]any{
+		"values":  values,
+		"found":   len(values),
+		"missing": missing,
+	}, nil
+}
+
 // Delete removes a key
 func (m *MemoryStore) Delete(key string) (map[string]any, error) {
 	m.mu.Lock()
@@ -256,19 +417,32 @@ func (m *MemoryStore) Delete(key string) (map[string]any, error) {
 
 // Keys returns all keys
 func (m *MemoryStore) Keys() (map[string]any, error) {
+	return m.KeysMatching("", "")
+}
+
+// KeysMatching returns the sorted keys that start with prefix and match the
+// glob pattern, where '*' matches any run of characters and '?' any single
+// character. Empty filters match everything; expired keys are skipped.
+func (m *MemoryStore) KeysMatching(pattern, prefix string) (map[string]any, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	keys := make([]string, 0, len(m.data)+len(m.lists))
+	matches := func(k string) bool {
+		return strings.HasPrefix(k, prefix) && (pattern == "" || globMatch(pattern, k))
+	}
 
-	for k := range m.data {
-		keys = append(keys, k)
+	keys := make([]string, 0, len(m.data)+len(m.lists))
+	for k, entry := range m.data {
+		if !entry.expired() && matches(k) {
+			keys = append(keys, k)
+		}
 	}
 	for k := range m.lists {
-		if _, exists := m.data[k]; !exists {
+		if _, exists := m.data[k]; !exists && matches(k) {
 			keys = append(keys, k+"(list)")
 		}
 	}
+	slices.Sort(keys)
 
 	return map[string]any{
 		"keys":  keys,
@@ -310,33 +484,210 @@ func (m *MemoryStore) Clear() (map[string]any, error) {
 	}, nil
 }
 
+// MemorySnapshot is the JSON form of a whole store, as produced by Export
+// and loaded by Import
+type MemorySnapshot struct {
+	Data  map[string]memoryEntry `json:"data"`
+	Lists map[string][]any       `json:"lists"`
+}
+
+// Export returns a snapshot of every live key with its TTL metadata and every
+// list. Expired keys are dropped.
+func (m *MemoryStore) Export() (map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := MemorySnapshot{
+		Data:  make(map[string]memoryEntry, len(m.data)),
+		Lists: make(map[string][]any, len(m.lists)),
+	}
+	for k, entry := range m.data {
+		if !entry.expired() {
+			snapshot.Data[k] = entry
+		}
+	}
+	for k, list := range m.lists {
+		snapshot.Lists[k] = slices.Clone(list)
+	}
+
+	return map[string]any{
+		"snapshot": snapshot,
+		"keys":     len(snapshot.Data),
+		"lists":    len(snapshot.Lists),
+	}, nil
+}
+
+// Import loads a snapshot produced by Export. Imported keys and lists
+// overwrite existing ones; with replace the store is cleared first. Keys
+// whose expiry has passed since the export are skipped.
+func (m *MemoryStore) Import(raw json.RawMessage, replace bool) (map[string]any, error) {
+	var snapshot MemorySnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, InvalidInput("invalid snapshot: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if replace {
+		m.data = make(map[string]memoryEntry)
+		m.lists = make(map[string][]any)
+	}
+
+	imported, skipped := 0, 0
+	for k, entry := range snapshot.Data {
+		if entry.expired() {
+			skipped++
+			continue
+		}
+		m.data[k] = entry
+		imported++
+	}
+	for k, list := range snapshot.Lists {
+		m.lists[k] = list
+	}
+
+	return map[string]any{
+		"success":  true,
+		"keys":     imported,
+		"lists":    len(snapshot.Lists),
+		"expired":  skipped,
+		"replaced": replace,
+	}, nil
+}
+
+// incrAmount returns the "value" of an incr or decr input, 1 if it has
+// none. Integers are read exactly, however large; fractions are truncated.
+func incrAmount(input json.RawMessage) int {
+	var data struct {
+		Value any `json:"value"`
+	}
+	if err := BindInputNumbers(input, &data); err != nil || data.Value == nil {
+		return 1
+	}
+	if n, ok := blaze.JSONInt64(data.Value); ok {
+		return int(n)
+	}
+	if f, ok := blaze.JSONFloat64(data.Value); ok {
+		return int(f)
+	}
+	return 1
+}
+
 // Incr increments a counter
 func (m *MemoryStore) Incr(key string, amount int) (map[string]any, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	current := 0
-	if entry, exists := m.data[key]; exists {
-		if v, ok := entry.Value.(float64); ok {
-			current = int(v)
-		} else if v, ok := entry.Value.(int); ok {
-			current = v
-		}
+	entry, current, err := m.counterEntry(key)
+	if err != nil {
+		return nil, err
 	}
 
+	// Keep the entry's creation time and expiry; only the value changes
 	newValue := current + amount
-	m.data[key] = memoryEntry{
-		Value:     float64(newValue),
-		CreatedAt: time.Now(),
+	entry.Value = newValue
+	m.data[key] = entry
+
+	return map[string]any{
+		"key":      key,
+		"previous": current,
+		"current":  newValue,
+	}, nil
+}
+
+// IncrBounded adds amount to a counter and clamps the result to min and/or
+// max when they are non-nil. Reading, updating and clamping happen under one
+// lock, so concurrent callers never see or store an out-of-range value.
+func (m *MemoryStore) IncrBounded(key string, amount int, min, max *int) (map[string]any, error) {
+	if min != nil && max != nil && *min > *max {
+		return nil, InvalidInput("min (%d) is greater than max (%d)", *min, *max)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, current, err := m.counterEntry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, clamped := current+amount, false
+	if min != nil && newValue < *min {
+		newValue, clamped = *min, true
+	}
+	if max != nil && newValue > *max {
+		newValue, clamped = *max, true
 	}
 
+	entry.Value = newValue
+	m.data[key] = entry
+
 	return map[string]any{
 		"key":      key,
 		"previous": current,
 		"current":  newValue,
+		"clamped":  clamped,
+	}, nil
+}
+
+// Counter returns the current value of a counter (0 if unset)
+func (m *MemoryStore) Counter(key string) (map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, current, err := m.counterEntry(key)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"key":     key,
+		"current": current,
+	}, nil
+}
+
+// ResetCounter sets a counter to value
+func (m *MemoryStore) ResetCounter(key string, value int) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Reset overwrites any value, numeric or not, but keeps the expiry
+	entry, current, _ := m.counterEntry(key)
+	entry.Value = value
+	m.data[key] = entry
+
+	return map[string]any{
+		"key":      key,
+		"previous": current,
+		"current":  value,
 	}, nil
 }
 
+// counterEntry returns the entry at key and its integer value. A missing or
+// expired key is a fresh entry with value 0; a non-numeric value is an error.
+// Callers must hold the lock.
+func (m *MemoryStore) counterEntry(key string) (memoryEntry, int, error) {
+	entry, exists := m.data[key]
+	if !exists || entry.expired() {
+		return memoryEntry{CreatedAt: time.Now()}, 0, nil
+	}
+
+	switch v := entry.Value.(type) {
+	case float64:
+		return entry, int(v), nil
+	case int:
+		return entry, v, nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return entry, int(n), nil
+		}
+		f, _ := v.Float64()
+		return entry, int(f), nil
+	default:
+		return entry, 0, InvalidInput("value at '%s' is not a number (got %s)", key, getType(entry.Value))
+	}
+}
+
 // ListAppend adds an item to a list
 func (m *MemoryStore) ListAppend(key string, value any) (map[string]any, error) {
 	m.mu.Lock()
@@ -354,6 +705,43 @@ func (m *MemoryStore) ListAppend(key string, value any) (map[string]any, error)
 	}, nil
 }
 
+// ListPrepend adds an item to the head of a list
+func (m *MemoryStore) ListPrepend(key string, value any) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lists[key] = slices.Insert(m.lists[key], 0, value)
+
+	return map[string]any{
+		"key":    key,
+		"length": len(m.lists[key]),
+	}, nil
+}
+
+// ListPopFront removes and returns the first item
+func (m *MemoryStore) ListPopFront(key string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list, exists := m.lists[key]
+	if !exists || len(list) == 0 {
+		return map[string]any{
+			"key":   key,
+			"empty": true,
+		}, nil
+	}
+
+	item := list[0]
+	list[0] = nil // let the popped item be collected while the backing array lives on
+	m.lists[key] = list[1:]
+
+	return map[string]any{
+		"key":    key,
+		"value":  item,
+		"length": len(m.lists[key]),
+	}, nil
+}
+
 // ListPop removes and returns the last item
 func (m *MemoryStore) ListPop(key string) (map[string]any, error) {
 	m.mu.Lock()
@@ -441,3 +829,123 @@ func (m *MemoryStore) ListLen(key string) (map[string]any, error) {
 		"exists": exists,
 	}, nil
 }
+
+// subscriberBuffer bounds the messages queued for one subscriber. Subscribe
+// returns after one message, so later publishes to a subscriber that hasn't
+// picked up its message yet are dropped rather than blocking or queueing.
+const subscriberBuffer = 1
+
+// maxSubscribeSeconds caps how long the memory tool's subscribe may block
+const maxSubscribeSeconds = 60.0
+
+// Publish sends value to every current subscriber of topic. Messages are not
+// stored: with no subscribers waiting, the message is dropped.
+func (m *MemoryStore) Publish(topic string, value any) (map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	delivered, dropped := 0, 0
+	for ch := range m.topics[topic] {
+		select {
+		case ch <- value:
+			delivered++
+		default:
+			dropped++
+		}
+	}
+
+	return map[string]any{
+		"topic":     topic,
+		"delivered": delivered,
+		"dropped":   dropped,
+	}, nil
+}
+
+// Subscribe waits up to timeout for the next message published to topic
+func (m *MemoryStore) Subscribe(topic string, timeout time.Duration) (map[string]any, error) {
+	return m.SubscribeContext(context.Background(), topic, timeout)
+}
+
+// SubscribeContext is like Subscribe but also returns, with "cancelled"
+// set, as soon as ctx is done, e.g. because the client disconnected
+func (m *MemoryStore) SubscribeContext(ctx context.Context, topic string, timeout time.Duration) (map[string]any, error) {
+	ch := make(chan any, subscriberBuffer)
+
+	m.mu.Lock()
+	if m.topics[topic] == nil {
+		m.topics[topic] = make(map[chan any]struct{})
+	}
+	m.topics[topic][ch] = struct{}{}
+	m.mu.Unlock()
+
+	// Channels are never closed; unsubscribing removes them so Publish stops sending
+	defer func() {
+		m.mu.Lock()
+		delete(m.topics[topic], ch)
+		if len(m.topics[topic]) == 0 {
+			delete(m.topics, topic)
+		}
+		m.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case value := <-ch:
+		return map[string]any{
+			"topic":    topic,
+			"message":  value,
+			"received": true,
+		}, nil
+	case <-timer.C:
+		return map[string]any{
+			"topic":     topic,
+			"received":  false,
+			"timed_out": true,
+		}, nil
+
+	case <-ctx.Done():
+		return map[string]any{
+			"topic":     topic,
+			"received":  false,
+			"cancelled": true,
+		}, nil
+	}
+}
+
+// Subscribers returns the number of subscribers currently waiting on topic
+func (m *MemoryStore) Subscribers(topic string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.topics[topic])
+}
+
+// globMatch reports whether s matches pattern, where '*' matches any run of
+// characters (including none) and '?' exactly one; everything else is literal
+func globMatch(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	star, mark := -1, 0 // last '*' in the pattern and where its match started
+
+	for si < len(str) {
+		switch {
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case star >= 0:
+			// Let the last '*' swallow one more character and retry
+			mark++
+			pi, si = star+1, mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
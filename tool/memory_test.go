@@ -0,0 +1,412 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+	"github.com/dvictor357/blaze/adapter"
+)
+
+func TestMemory_DryRunDoesNotMutate(t *testing.T) {
+	e := blaze.New()
+	e.POST("/chat", adapter.AnthropicAdapter(NewMemoryTool()))
+
+	call := func(dryRun bool, input map[string]any) adapter.AnthropicContentBlock {
+		body, _ := json.Marshal(adapter.AnthropicChatRequest{
+			Model:  "claude-3-5-sonnet",
+			DryRun: dryRun,
+			Messages: []adapter.AnthropicMessage{{Role: "user", Content: []adapter.AnthropicContentBlock{
+				{Type: "tool_use", ID: "toolu_1", Name: "memory", Input: input},
+			}}},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var resp adapter.AnthropicChatResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if len(resp.Content) != 1 || resp.Content[0].IsError {
+			t.Fatalf("unexpected response %s", rec.Body.String())
+		}
+		return resp.Content[0]
+	}
+	set := func(dryRun bool) adapter.AnthropicContentBlock {
+		return call(dryRun, map[string]any{"action": "set", "key": "dry_run_test", "value": "v"})
+	}
+	defer Memory().Delete("dry_run_test")
+
+	block := set(true)
+	if !strings.Contains(block.Content, `"dry_run":true`) {
+		t.Errorf("expected dry-run preview, got %s", block.Content)
+	}
+	if got, _ := Memory().Get("dry_run_test"); got["found"] != false {
+		t.Fatalf("expected dry run not to mutate the store, got %v", got)
+	}
+
+	// Without the flag the same call writes to the store
+	set(false)
+	if got, _ := Memory().Get("dry_run_test"); got["found"] != true {
+		t.Errorf("expected key to be set, got %v", got)
+	}
+
+	// Reads still run in a dry run
+	for _, input := range []map[string]any{
+		{"action": "get", "key": "dry_run_test"},
+		{"action": "keys", "pattern": "dry_run_*"},
+	} {
+		if block := call(true, input); !strings.Contains(block.Content, "dry_run_test") || strings.Contains(block.Content, `"dry_run":true`) {
+			t.Errorf("%v: expected the read to run, got %s", input["action"], block.Content)
+		}
+	}
+}
+
+// drain pops key with action until the list is empty and returns the values
+func drain(t *testing.T, key, action string) []any {
+	t.Helper()
+	var values []any
+	for {
+		got := runTool(t, NewMemoryTool(), map[string]any{"action": action, "key": key})
+		if got["empty"] == true {
+			return values
+		}
+		values = append(values, got["value"])
+	}
+}
+
+func TestMemory_QueueFIFO(t *testing.T) {
+	key := "memory_test_queue"
+	for _, v := range []string{"a", "b", "c"} {
+		runTool(t, NewMemoryTool(), map[string]any{"action": "rpush", "key": key, "value": v})
+	}
+
+	got := drain(t, key, "lpop")
+	if want := []any{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected FIFO order %v, got %v", want, got)
+	}
+}
+
+func TestMemory_StackLIFO(t *testing.T) {
+	key := "memory_test_stack"
+	for _, v := range []string{"a", "b", "c"} {
+		runTool(t, NewMemoryTool(), map[string]any{"action": "rpush", "key": key, "value": v})
+	}
+
+	got := drain(t, key, "rpop")
+	if want := []any{"c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected LIFO order %v, got %v", want, got)
+	}
+
+	// lpush adds at the head
+	runTool(t, NewMemoryTool(), map[string]any{"action": "rpush", "key": key, "value": "x"})
+	runTool(t, NewMemoryTool(), map[string]any{"action": "lpush", "key": key, "value": "y"})
+	items := runTool(t, NewMemoryTool(), map[string]any{"action": "lrange", "key": key})["items"]
+	if want := []any{"y", "x"}; !reflect.DeepEqual(items, want) {
+		t.Errorf("expected %v after lpush, got %v", want, items)
+	}
+}
+
+func TestMemory_PublishSubscribe(t *testing.T) {
+	topic := "memory_test_topic"
+
+	got := make(chan map[string]any, 1)
+	go func() {
+		raw, _ := json.Marshal(map[string]any{"action": "subscribe", "key": topic, "timeout": 2})
+		out, _ := NewMemoryTool().Handler(raw)
+		got <- out.(map[string]any)
+	}()
+
+	// Wait for the subscriber to register before publishing
+	deadline := time.Now().Add(time.Second)
+	for Memory().Subscribers(topic) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	pub := runTool(t, NewMemoryTool(), map[string]any{"action": "publish", "key": topic, "value": map[string]any{"step": "done"}})
+	if pub["delivered"] != 1 {
+		t.Errorf("expected 1 delivery, got %v", pub)
+	}
+
+	select {
+	case result := <-got:
+		if result["received"] != true || !reflect.DeepEqual(result["message"], map[string]any{"step": "done"}) {
+			t.Errorf("expected published message, got %v", result)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("subscribe did not return")
+	}
+	if n := Memory().Subscribers(topic); n != 0 {
+		t.Errorf("expected subscriber to be removed, got %d", n)
+	}
+}
+
+func TestMemory_SubscribeTimeout(t *testing.T) {
+	got := runTool(t, NewMemoryTool(), map[string]any{"action": "subscribe", "key": "memory_test_quiet", "timeout": 0.05})
+	if got["timed_out"] != true || got["received"] != false {
+		t.Errorf("expected timeout, got %v", got)
+	}
+}
+
+func TestMemory_SubscribeCancelled(t *testing.T) {
+	topic := "memory_test_cancel"
+	ctx, cancel := context.WithCancel(context.Background())
+
+	got := make(chan map[string]any, 1)
+	go func() {
+		raw, _ := json.Marshal(map[string]any{"action": "subscribe", "key": topic, "timeout": 60})
+		out, _ := NewMemoryTool().ContextHandler(ctx, raw)
+		got <- out.(map[string]any)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for Memory().Subscribers(topic) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case result := <-got:
+		if result["cancelled"] != true || result["received"] != false {
+			t.Errorf("expected cancelled result, got %v", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscribe did not return after cancellation")
+	}
+	if n := Memory().Subscribers(topic); n != 0 {
+		t.Errorf("expected subscriber to be removed, got %d", n)
+	}
+}
+
+func TestMemory_IncrKeepsExpiry(t *testing.T) {
+	key := "memory_test_incr_ttl"
+	defer Memory().Delete(key)
+
+	runTool(t, NewMemoryTool(), map[string]any{"action": "set", "key": key, "value": 5, "ttl": 60})
+	Memory().mu.RLock()
+	before := Memory().data[key]
+	Memory().mu.RUnlock()
+
+	got := runTool(t, NewMemoryTool(), map[string]any{"action": "incr", "key": key})
+	if got["current"] != 6 {
+		t.Errorf("expected 6, got %v", got["current"])
+	}
+
+	Memory().mu.RLock()
+	after := Memory().data[key]
+	Memory().mu.RUnlock()
+	if !after.ExpiresAt.Equal(before.ExpiresAt) || after.TTL != 60 || !after.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("expected expiry to be kept, before %+v after %+v", before, after)
+	}
+}
+
+func TestMemory_IncrNonNumeric(t *testing.T) {
+	key := "memory_test_incr_string"
+	defer Memory().Delete(key)
+
+	runTool(t, NewMemoryTool(), map[string]any{"action": "set", "key": key, "value": "hello"})
+	raw, _ := json.Marshal(map[string]any{"action": "incr", "key": key})
+	_, err := NewMemoryTool().Handler(raw)
+	if err == nil || !strings.Contains(err.Error(), "not a number (got string)") {
+		t.Fatalf("expected non-numeric error, got %v", err)
+	}
+
+	// The value is left untouched
+	if got, _ := Memory().Get(key); got["value"] != "hello" {
+		t.Errorf("expected value to be unchanged, got %v", got)
+	}
+}
+
+func TestMemory_LargeIntegerRoundTrip(t *testing.T) {
+	key := "memory_test_large_int"
+	defer Memory().Delete(key)
+
+	runTool(t, NewMemoryTool(), map[string]any{"action": "set", "key": key, "value": json.Number("12345678901234567")})
+	got := runTool(t, NewMemoryTool(), map[string]any{"action": "get", "key": key})
+	if b, _ := json.Marshal(got["value"]); string(b) != "12345678901234567" {
+		t.Errorf("expected 12345678901234567, got %s", b)
+	}
+}
+
+func TestMemory_MSetMGet(t *testing.T) {
+	defer func() {
+		for _, k := range []string{"memory_test_m1", "memory_test_m2", "memory_test_m3"} {
+			Memory().Delete(k)
+		}
+	}()
+
+	set := runTool(t, NewMemoryTool(), map[string]any{"action": "mset", "ttl": 60, "values": map[string]any{
+		"memory_test_m1": "one",
+		"memory_test_m2": map[string]any{"n": 2},
+		"memory_test_m3": []any{3},
+	}})
+	if set["count"] != 3 {
+		t.Fatalf("expected 3 keys set, got %v", set)
+	}
+
+	got := runTool(t, NewMemoryTool(), map[string]any{"action": "mget", "keys": []string{"memory_test_m1", "memory_test_m2", "memory_test_nope"}})
+	want := map[string]any{"memory_test_m1": "one", "memory_test_m2": map[string]any{"n": json.Number("2")}}
+	if !reflect.DeepEqual(got["values"], want) {
+		t.Errorf("expected values %v, got %v", want, got["values"])
+	}
+	if !reflect.DeepEqual(got["missing"], []string{"memory_test_nope"}) || got["found"] != 2 {
+		t.Errorf("expected one missing key, got %v", got)
+	}
+
+	// The shared TTL applies to every key
+	Memory().mu.RLock()
+	ttl := Memory().data["memory_test_m3"].TTL
+	Memory().mu.RUnlock()
+	if ttl != 60 {
+		t.Errorf("expected shared ttl 60, got %d", ttl)
+	}
+}
+
+func TestMemory_KeysPrefix(t *testing.T) {
+	store := NewMemoryStore()
+	store.Set("cache:a", 1, 0)
+	store.Set("cache:b", 2, 0)
+	store.Set("user:1", "ada", 0)
+	store.ListAppend("cache:list", "x")
+
+	got, _ := store.KeysMatching("", "cache:")
+	if want := []string{"cache:a", "cache:b", "cache:list(list)"}; !reflect.DeepEqual(got["keys"], want) {
+		t.Errorf("expected %v, got %v", want, got["keys"])
+	}
+}
+
+func TestMemory_KeysGlob(t *testing.T) {
+	store := NewMemoryStore()
+	for _, k := range []string{"user:1", "user:22", "user:1:settings", "users", "admin:user:1"} {
+		store.Set(k, true, 0)
+	}
+	store.Set("user:expired", true, 1)
+	store.mu.Lock()
+	e := store.data["user:expired"]
+	e.ExpiresAt = time.Now().Add(-time.Second)
+	store.data["user:expired"] = e
+	store.mu.Unlock()
+
+	got, _ := store.KeysMatching("user:*", "")
+	if want := []string{"user:1", "user:1:settings", "user:22"}; !reflect.DeepEqual(got["keys"], want) {
+		t.Errorf("expected %v, got %v", want, got["keys"])
+	}
+
+	got, _ = store.KeysMatching("user:?", "")
+	if want := []string{"user:1"}; !reflect.DeepEqual(got["keys"], want) {
+		t.Errorf("expected %v, got %v", want, got["keys"])
+	}
+
+	cases := map[[2]string]bool{
+		{"*:1", "admin:user:1"}: true,
+		{"a*b*c", "aXbYbZc"}:    true,
+		{"a*b", "aXbYc"}:        false,
+		{"*", ""}:               true,
+		{"?", ""}:               false,
+		{"lit*", "lit*eral"}:    true,
+	}
+	for c, want := range cases {
+		if got := globMatch(c[0], c[1]); got != want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", c[0], c[1], got, want)
+		}
+	}
+}
+
+func TestMemory_ExportImportRoundTrip(t *testing.T) {
+	src := NewMemoryStore()
+	src.Set("plain", "v", 0)
+	src.Set("session", map[string]any{"user": "ada"}, 3600)
+	src.Set("gone", true, 1)
+	src.ListAppend("queue", "job-1")
+	src.ListAppend("queue", 2.0)
+
+	src.mu.Lock()
+	e := src.data["gone"]
+	e.ExpiresAt = time.Now().Add(-time.Second)
+	src.data["gone"] = e
+	src.mu.Unlock()
+
+	exported, _ := src.Export()
+	if exported["keys"] != 2 {
+		t.Fatalf("expected expired key to be dropped, got %v", exported)
+	}
+	raw, err := json.Marshal(exported["snapshot"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := NewMemoryStore()
+	dst.Set("existing", 1.0, 0)
+	if _, err := dst.Import(raw, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := dst.Get("existing"); got["found"] != true {
+		t.Errorf("expected merge to keep existing keys, got %v", got)
+	}
+	if got, _ := dst.Get("session"); !reflect.DeepEqual(got["value"], map[string]any{"user": "ada"}) {
+		t.Errorf("expected session value, got %v", got)
+	}
+	if !dst.data["session"].ExpiresAt.Equal(src.data["session"].ExpiresAt) || dst.data["session"].TTL != 3600 {
+		t.Errorf("expected expiry to survive, got %+v", dst.data["session"])
+	}
+	if got, _ := dst.Get("plain"); got["value"] != "v" || got["expires_at"] != nil {
+		t.Errorf("expected plain key without expiry, got %v", got)
+	}
+	if got, _ := dst.ListRange("queue", 0, -1); !reflect.DeepEqual(got["items"], []any{"job-1", 2.0}) {
+		t.Errorf("expected list to round-trip, got %v", got["items"])
+	}
+
+	if _, err := dst.Import(raw, true); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := dst.Get("existing"); got["found"] != false {
+		t.Errorf("expected replace to drop existing keys, got %v", got)
+	}
+}
+
+func TestMemory_ImportSkipsExpired(t *testing.T) {
+	store := NewMemoryStore()
+	raw := json.RawMessage(`{"data": {
+		"old": {"value": 1, "created_at": "2020-01-01T00:00:00Z", "expires_at": "2020-01-01T01:00:00Z", "ttl_seconds": 3600},
+		"new": {"value": 2, "created_at": "2020-01-01T00:00:00Z"}
+	}}`)
+
+	got, err := store.Import(raw, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["keys"] != 1 || got["expired"] != 1 {
+		t.Errorf("expected 1 imported and 1 expired, got %v", got)
+	}
+	if _, err := store.Import(json.RawMessage(`[1]`), false); err == nil {
+		t.Error("expected invalid snapshot error")
+	}
+}
+
+func TestMemory_IncrLargeInteger(t *testing.T) {
+	key := "memory_test_incr_large"
+	defer Memory().Delete(key)
+
+	// 17 digits: float64 would round this to ...568
+	raw := json.RawMessage(`{"action": "incr", "key": "` + key + `", "value": 12345678901234567}`)
+	if _, err := NewMemoryTool().Handler(raw); err != nil {
+		t.Fatal(err)
+	}
+	got := runTool(t, NewMemoryTool(), map[string]any{"action": "incr", "key": key})
+	if got["current"] != 12345678901234568 {
+		t.Errorf("expected 12345678901234568, got %v", got["current"])
+	}
+}
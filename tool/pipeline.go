@@ -0,0 +1,182 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// PipelineStep is one tool call of a pipeline. Input is the tool's input,
+// in which string values are references:
+//   - "$" is the previous step's output (the pipeline's input for the
+//     first step), "$.results[0].url" or "$/results/0/url" a part of it,
+//     using the json_query path syntax
+//   - "$input" and "$input.query" refer to the pipeline's input the same way
+//   - a "|json" suffix passes the value as a JSON string, e.g. for the
+//     json field of json_query
+//   - "$$" starts a literal "$"
+//
+// Other values, and strings without a leading "$", are passed as is. A nil
+// Input passes the previous output unchanged.
+type PipelineStep struct {
+	Tool  adapter.Tool
+	Input map[string]any
+}
+
+// NewPipelineTool creates a tool that runs steps in order, feeding each
+// step's output into the next one's input, e.g. web_search into web_read
+// into json_query. It returns {"result": <last output>}; with
+// "intermediate": true in its input, "steps" lists every step's tool and
+// output as well. A failing step fails the pipeline with the step's error
+// kind. Steps receive the pipeline's context, and their progress messages
+// are relayed. The pipeline has side effects if any of its steps does. It
+// panics if a step's tool has no handler.
+func NewPipelineTool(name, desc string, steps []PipelineStep) adapter.Tool {
+	for i, step := range steps {
+		if step.Tool.Handler == nil && step.Tool.ProgressHandler == nil && step.Tool.ContextHandler == nil {
+			panic(fmt.Sprintf("tool: pipeline step %d (%q) has no handler", i+1, step.Tool.Name))
+		}
+	}
+
+	t := adapter.NewContextTool(
+		name,
+		desc,
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"intermediate": map[string]any{
+					"type":        "boolean",
+					"description": "Also return the output of every step",
+				},
+			},
+		},
+		func(ctx context.Context, input json.RawMessage) (any, error) {
+			var pipelineInput any
+			if err := json.Unmarshal(input, &pipelineInput); err != nil {
+				return nil, InvalidInput("invalid input: %w", err)
+			}
+			progress := adapter.ContextProgress(ctx)
+			opts, _ := pipelineInput.(map[string]any)
+			intermediate, _ := opts["intermediate"].(bool)
+
+			prev := pipelineInput
+			var outputs []map[string]any
+			for i, step := range steps {
+				stepName := step.Tool.Name
+				stepInput, err := resolvePipelineInput(step.Input, prev, pipelineInput)
+				if err != nil {
+					return nil, Internal("step %d (%s): %w", i+1, stepName, err)
+				}
+				raw, err := json.Marshal(stepInput)
+				if err != nil {
+					return nil, Internal("step %d (%s): %w", i+1, stepName, err)
+				}
+
+				out, err := step.Tool.Invoke(ctx, raw, progress)
+				if err != nil {
+					return nil, adapter.NewToolError(adapter.ErrorKindOf(err), "step %d (%s): %w", i+1, stepName, err)
+				}
+				// Decode the output generically, so later paths see plain JSON
+				if prev, err = toJSONValue(out); err != nil {
+					return nil, Internal("step %d (%s): %w", i+1, stepName, err)
+				}
+				if intermediate {
+					outputs = append(outputs, map[string]any{"tool": stepName, "output": prev})
+				}
+			}
+
+			result := map[string]any{"result": prev}
+			if intermediate {
+				result["steps"] = outputs
+			}
+			return result, nil
+		},
+	)
+	for _, step := range steps {
+		t.SideEffect = t.SideEffect || step.Tool.SideEffect
+	}
+	return t
+}
+
+// resolvePipelineInput returns a step's input with its references replaced,
+// or prev when the step has no input
+func resolvePipelineInput(input map[string]any, prev, pipelineInput any) (any, error) {
+	if input == nil {
+		return prev, nil
+	}
+	return resolvePipelineValue(input, prev, pipelineInput)
+}
+
+// resolvePipelineValue replaces the references in v, descending into
+// objects and arrays
+func resolvePipelineValue(v, prev, pipelineInput any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return resolvePipelineRef(v, prev, pipelineInput)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			resolved, err := resolvePipelineValue(item, prev, pipelineInput)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := resolvePipelineValue(item, prev, pipelineInput)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// resolvePipelineRef evaluates a "$" reference, returning other strings as is
+func resolvePipelineRef(ref string, prev, pipelineInput any) (any, error) {
+	if literal, ok := strings.CutPrefix(ref, "$$"); ok {
+		return "$" + literal, nil
+	}
+	path, ok := strings.CutPrefix(ref, "$")
+	if !ok {
+		return ref, nil
+	}
+	path, asJSON := strings.CutSuffix(path, "|json")
+
+	data := prev
+	if rest, ok := strings.CutPrefix(path, "input"); ok && (rest == "" || rest[0] == '.' || rest[0] == '/' || rest[0] == '[') {
+		data, path = pipelineInput, rest
+	}
+	v, err := executeQuery(data, path)
+	if err != nil {
+		return nil, InvalidInput("reference %q: %w", ref, err)
+	}
+	if asJSON {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	return v, nil
+}
+
+// toJSONValue converts a tool result to the generic values json.Unmarshal
+// produces
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
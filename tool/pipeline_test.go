@@ -0,0 +1,133 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// mockPipelineTools returns a search tool listing two URLs and a read tool
+// echoing the URL it was given
+func mockPipelineTools() (search, read adapter.Tool) {
+	search = adapter.NewTool("search", "Mock search", nil, func(input json.RawMessage) (any, error) {
+		var data struct {
+			Query string `json:"query"`
+		}
+		json.Unmarshal(input, &data)
+		return map[string]any{"results": []SearchResult{
+			{Title: data.Query + " docs", URL: "https://example.com/docs"},
+			{Title: "other", URL: "https://example.com/other"},
+		}}, nil
+	})
+	read = adapter.NewTool("read", "Mock read", nil, func(input json.RawMessage) (any, error) {
+		var data struct {
+			URL string `json:"url"`
+		}
+		json.Unmarshal(input, &data)
+		if data.URL == "" {
+			return nil, InvalidInput("url cannot be empty")
+		}
+		return map[string]any{"url": data.URL, "content": `{"version":"1.2"}`}, nil
+	})
+	return search, read
+}
+
+func TestPipeline_SearchThenRead(t *testing.T) {
+	search, read := mockPipelineTools()
+	pipeline := NewPipelineTool("search_and_read", "Read the top result", []PipelineStep{
+		{Tool: search, Input: map[string]any{"query": "$input.topic"}},
+		{Tool: read, Input: map[string]any{"url": "$.results[0].url"}},
+		{Tool: NewJSONQueryTool(), Input: map[string]any{"json": "$.content", "query": ".version"}},
+	})
+
+	out, err := pipeline.Handler(json.RawMessage(`{"topic":"blaze"}`))
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	result := out.(map[string]any)
+	if got := result["result"].(map[string]any)["result"]; got != "1.2" {
+		t.Errorf("expected the queried version, got %v", result)
+	}
+	if _, ok := result["steps"]; ok {
+		t.Error("expected no intermediate outputs by default")
+	}
+}
+
+func TestPipeline_Intermediate(t *testing.T) {
+	search, read := mockPipelineTools()
+	pipeline := NewPipelineTool("search_and_read", "Read the top result", []PipelineStep{
+		{Tool: search, Input: map[string]any{"query": "$input.topic"}},
+		{Tool: read, Input: map[string]any{"url": "$/results/1/url"}},
+	})
+
+	out, err := pipeline.Handler(json.RawMessage(`{"topic":"blaze","intermediate":true}`))
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	steps := out.(map[string]any)["steps"].([]map[string]any)
+	if len(steps) != 2 || steps[0]["tool"] != "search" || steps[1]["tool"] != "read" {
+		t.Fatalf("expected both steps, got %v", steps)
+	}
+	title := steps[0]["output"].(map[string]any)["results"].([]any)[0].(map[string]any)["title"]
+	if title != "blaze docs" {
+		t.Errorf("expected the search output, got %v", steps[0]["output"])
+	}
+	if url := steps[1]["output"].(map[string]any)["url"]; url != "https://example.com/other" {
+		t.Errorf("expected the second URL to be read, got %v", url)
+	}
+}
+
+func TestPipeline_StepError(t *testing.T) {
+	search, read := mockPipelineTools()
+	pipeline := NewPipelineTool("search_and_read", "Read the top result", []PipelineStep{
+		{Tool: search, Input: map[string]any{"query": "x"}},
+		{Tool: read, Input: map[string]any{"url": ""}},
+	})
+
+	_, err := pipeline.Handler(json.RawMessage(`{}`))
+	var te *Error
+	if !errors.As(err, &te) || te.Kind != KindInvalidInput || te.Error() != "step 2 (read): url cannot be empty" {
+		t.Errorf("expected the step's invalid_input error, got %v", err)
+	}
+}
+
+func TestPipeline_SideEffect(t *testing.T) {
+	search, _ := mockPipelineTools()
+	if NewPipelineTool("p", "", []PipelineStep{{Tool: search}}).SideEffect {
+		t.Error("expected no side effect without a side-effecting step")
+	}
+	if !NewPipelineTool("p", "", []PipelineStep{{Tool: search}, {Tool: NewWebFetchTool()}}).SideEffect {
+		t.Error("expected the side effect of web_fetch to carry over")
+	}
+}
+
+func TestPipeline_RelaysProgress(t *testing.T) {
+	crawl := adapter.NewProgressTool("crawl", "Crawl", nil, func(input json.RawMessage, progress adapter.ProgressFunc) (any, error) {
+		progress("crawling...")
+		return map[string]any{"pages": 2}, nil
+	})
+	pipeline := NewPipelineTool("p", "", []PipelineStep{{Tool: crawl}})
+
+	var messages []string
+	_, err := pipeline.Invoke(context.Background(), json.RawMessage(`{}`), func(msg string) {
+		messages = append(messages, msg)
+	})
+	if err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0] != "crawling..." {
+		t.Errorf("expected the step's progress, got %q", messages)
+	}
+}
+
+func TestPipeline_RejectsStepWithoutHandler(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a step without a handler to panic")
+		}
+	}()
+	NewPipelineTool("p", "", []PipelineStep{{Tool: adapter.Tool{Name: "empty"}}})
+}
@@ -0,0 +1,83 @@
+package tool
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// wordsPerMinute is the reading speed used for reading time estimates
+const wordsPerMinute = 200
+
+// readingStats describes the length and difficulty of a text
+type readingStats struct {
+	Words          int
+	ReadingMinutes int     // words / 200 wpm, rounded up
+	ReadingEase    float64 // Flesch reading ease: ~100 very easy, ~0 very hard
+}
+
+var (
+	// markdownLinkTargetRe matches the "(url)" part of [text](url) and ![alt](url)
+	markdownLinkTargetRe = regexp.MustCompile(`\]\([^)]*\)`)
+	sentenceEndRe        = regexp.MustCompile(`[.!?]+(\s|$)`)
+)
+
+// computeReadingStats estimates word count, reading time and Flesch reading
+// ease for markdown text. Line breaks also end sentences, so headings and
+// list items without punctuation don't merge into one long sentence. The
+// syllable count is an English vowel-group heuristic.
+func computeReadingStats(markdown string) readingStats {
+	text := markdownLinkTargetRe.ReplaceAllString(markdown, "]")
+
+	words, sentences, syllables := 0, 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		for _, sentence := range sentenceEndRe.Split(line, -1) {
+			n := 0
+			for _, word := range strings.FieldsFunc(sentence, isWordSeparator) {
+				if strings.IndexFunc(word, unicode.IsLetter) < 0 && strings.IndexFunc(word, unicode.IsDigit) < 0 {
+					continue
+				}
+				n++
+				syllables += countSyllables(word)
+			}
+			if n > 0 {
+				words += n
+				sentences++
+			}
+		}
+	}
+
+	stats := readingStats{Words: words}
+	if words == 0 {
+		return stats
+	}
+	stats.ReadingMinutes = (words + wordsPerMinute - 1) / wordsPerMinute
+	ease := 206.835 - 1.015*float64(words)/float64(sentences) - 84.6*float64(syllables)/float64(words)
+	stats.ReadingEase = math.Round(ease*10) / 10
+	return stats
+}
+
+// isWordSeparator splits on anything but letters, digits and apostrophes
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+}
+
+// countSyllables approximates the syllables in an English word by counting
+// vowel groups, ignoring a silent trailing "e"
+func countSyllables(word string) int {
+	word = strings.ToLower(word)
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+	if count > 1 && strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") {
+		count--
+	}
+	return max(count, 1)
+}
@@ -0,0 +1,46 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadingStats_Fixture(t *testing.T) {
+	// 3 headings of 2 words + 50 sentences of 9 words = 456 words
+	sentence := "The cat sat on the mat and was happy. "
+	md := "# Intro Text\n\n" + strings.Repeat(sentence, 25) + "\n\n## Middle Part\n\n" +
+		strings.Repeat(sentence, 25) + "\n\n## End [Here](https://example.com/a/b/c)\n"
+
+	stats := computeReadingStats(md)
+	if stats.Words != 456 {
+		t.Errorf("expected 456 words, got %d", stats.Words)
+	}
+	// 456 words at 200 wpm is 2.3 minutes, rounded up
+	if stats.ReadingMinutes != 3 {
+		t.Errorf("expected 3 minutes, got %d", stats.ReadingMinutes)
+	}
+	// Short sentences of one-syllable words read very easily
+	if stats.ReadingEase < 90 || stats.ReadingEase > 121 {
+		t.Errorf("expected a very easy score, got %v", stats.ReadingEase)
+	}
+
+	hard := computeReadingStats("Notwithstanding considerable institutional unaccountability, interdisciplinary collaborations systematically underestimate organizational complexity.")
+	if hard.ReadingEase >= stats.ReadingEase || hard.ReadingEase > 30 {
+		t.Errorf("expected a hard score well below %v, got %v", stats.ReadingEase, hard.ReadingEase)
+	}
+}
+
+func TestReadingStats_Empty(t *testing.T) {
+	if stats := computeReadingStats("---\n\n```\n```"); stats != (readingStats{}) {
+		t.Errorf("expected zero stats, got %+v", stats)
+	}
+}
+
+func TestWebRead_ReadingStats(t *testing.T) {
+	page := `<html><body><article><p>` + strings.Repeat("One two three four five. ", 100) + `</p></article></body></html>`
+
+	result, _ := readPage(t, page)
+	if result["word_count"] != 500 || result["reading_time_minutes"] != 3 {
+		t.Errorf("expected 500 words and 3 minutes, got %v and %v", result["word_count"], result["reading_time_minutes"])
+	}
+}
@@ -0,0 +1,85 @@
+package tool
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// shellQuoteSchema is the input schema for shell_quote
+var shellQuoteSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"command": map[string]any{
+			"type":        "string",
+			"description": "Program to run, e.g. 'grep'. Quoted like any argument, so it cannot carry its own arguments",
+		},
+		"args": map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "Arguments, each passed to the program as-is",
+		},
+	},
+	"required": []string{"command"},
+}
+
+// NewShellQuoteTool creates a tool that builds a POSIX shell command line
+// from a program and its arguments. Every word that is not made only of
+// safe characters is single-quoted, so spaces, quotes, '$', globs and
+// backticks reach the program literally. Nothing is executed.
+func NewShellQuoteTool() adapter.Tool {
+	return adapter.NewTool(
+		"shell_quote",
+		"Quote a command and its arguments for a POSIX shell (sh, bash, zsh). Returns the quoted command line and the argv array. Use this when generating shell commands from untrusted or unusual values. Does not run anything.",
+		shellQuoteSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Command string   `json:"command"`
+				Args    []string `json:"args"`
+			}
+			if err := BindInput(input, &data, shellQuoteSchema); err != nil {
+				return nil, err
+			}
+
+			argv := append([]string{data.Command}, data.Args...)
+			words := make([]string, len(argv))
+			for i, arg := range argv {
+				words[i] = shellQuote(arg)
+			}
+			// A bare NAME=value command word would be a variable assignment
+			if words[0] == data.Command && strings.Contains(data.Command, "=") {
+				words[0] = "'" + data.Command + "'"
+			}
+
+			return map[string]any{
+				"command": strings.Join(words, " "),
+				"argv":    argv,
+			}, nil
+		},
+	)
+}
+
+// shellQuote returns s as a single POSIX shell word. Words made only of
+// characters the shell never interprets are left bare, except for a
+// leading '=', which zsh expands to a program path; anything else is
+// wrapped in single quotes; an embedded single quote ends the quoted run,
+// is escaped with a backslash and reopens it.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if s[0] != '=' && strings.IndexFunc(s, isShellUnsafe) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isShellUnsafe reports whether r needs quoting in a POSIX shell word
+func isShellUnsafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("@%+=:,./-_", r)
+}
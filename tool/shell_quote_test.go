@@ -0,0 +1,54 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShellQuote_Arguments(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"plain", "plain"},
+		{"--file=a/b.txt", "--file=a/b.txt"},
+		{"=ls", "'=ls'"},
+		{"", "''"},
+		{"two words", "'two words'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+		{"$(rm -rf /)", "'$(rm -rf /)'"},
+		{"*.go; `id`", "'*.go; `id`'"},
+		{"line\nbreak", "'line\nbreak'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got := runTool(t, NewShellQuoteTool(), map[string]any{"command": "echo", "args": []string{tt.arg}})
+			if got["command"] != "echo "+tt.want {
+				t.Errorf("expected %q, got %q", "echo "+tt.want, got["command"])
+			}
+		})
+	}
+}
+
+func TestShellQuote_Argv(t *testing.T) {
+	got := runTool(t, NewShellQuoteTool(), map[string]any{"command": "my tool", "args": []string{"a b", "'"}})
+
+	if got["command"] != `'my tool' 'a b' ''\'''` {
+		t.Errorf("unexpected command %q", got["command"])
+	}
+	want := []string{"my tool", "a b", "'"}
+	if !reflect.DeepEqual(got["argv"], want) {
+		t.Errorf("expected argv %q, got %q", want, got["argv"])
+	}
+}
+
+func TestShellQuote_AssignmentCommand(t *testing.T) {
+	got := runTool(t, NewShellQuoteTool(), map[string]any{"command": "PATH=/tmp", "args": []string{"a=b"}})
+
+	// Only the command word could be taken for an assignment
+	if got["command"] != "'PATH=/tmp' a=b" {
+		t.Errorf("unexpected command %q", got["command"])
+	}
+}
@@ -0,0 +1,168 @@
+package tool
+
+import (
+	"math"
+	"time"
+)
+
+// sunTimes computes sunrise, sunset and solar noon for the calendar date of
+// dateStr (default today) in loc at the given coordinates, using the NOAA
+// solar calculator equations. Longitude is positive east. Within the polar
+// circles the sun may not rise or set that day; polar_day or polar_night is
+// set and sunrise/sunset are left out.
+func sunTimes(dateStr string, latitude, longitude float64, loc *time.Location) (map[string]any, error) {
+	if latitude < -90 || latitude > 90 {
+		return nil, InvalidInput("latitude must be between -90 and 90, got %g", latitude)
+	}
+	if longitude < -180 || longitude > 180 {
+		return nil, InvalidInput("longitude must be between -180 and 180, got %g", longitude)
+	}
+
+	day := time.Now().In(loc)
+	if dateStr != "" {
+		var err error
+		if day, _, err = parseDateArg(dateStr, loc); err != nil {
+			return nil, err
+		}
+		day = day.In(loc)
+	}
+
+	// NOAA works in minutes from 00:00 UTC on the calendar date
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	jd := float64(midnight.Unix())/86400 + 2440587.5
+	at := func(minutes float64) time.Time {
+		return midnight.Add(time.Duration(minutes * float64(time.Minute))).In(loc).Truncate(time.Second)
+	}
+
+	result := map[string]any{
+		"date":        day.Format("2006-01-02"),
+		"latitude":    latitude,
+		"longitude":   longitude,
+		"solar_noon":  at(solarNoonUTC(jd, longitude)).Format(time.RFC3339),
+		"polar_day":   false,
+		"polar_night": false,
+		"timezone":    loc.String(),
+	}
+
+	sunrise, okRise := sunriseSetUTC(true, jd, latitude, longitude)
+	sunset, okSet := sunriseSetUTC(false, jd, latitude, longitude)
+	if !okRise || !okSet {
+		// The hour angle is undefined: the sun stays above or below the horizon
+		decl := sunDeclination(julianCentury(jd + 0.5))
+		polarDay := (latitude >= 0) == (decl >= 0)
+		result["polar_day"], result["polar_night"] = polarDay, !polarDay
+		if polarDay {
+			result["day_length"], result["day_length_hours"] = "24h0m0s", 24.0
+		} else {
+			result["day_length"], result["day_length_hours"] = "0s", 0.0
+		}
+		return result, nil
+	}
+
+	rise, set := at(sunrise), at(sunset)
+	length := set.Sub(rise)
+	result["sunrise"] = rise.Format(time.RFC3339)
+	result["sunset"] = set.Format(time.RFC3339)
+	result["day_length"] = length.String()
+	result["day_length_hours"] = math.Round(length.Hours()*100) / 100
+	return result, nil
+}
+
+// sunriseSetUTC returns the minutes after 00:00 UTC of sunrise (rise) or
+// sunset, refined with a second pass at the first estimate. It reports false
+// when the sun doesn't cross the horizon.
+func sunriseSetUTC(rise bool, jd, latitude, longitude float64) (float64, bool) {
+	estimate := func(jd float64) (float64, bool) {
+		t := julianCentury(jd)
+		ha, ok := sunriseHourAngle(latitude, sunDeclination(t))
+		if !ok {
+			return 0, false
+		}
+		if !rise {
+			ha = -ha
+		}
+		return 720 - 4*(longitude+ha) - equationOfTime(t), true
+	}
+
+	minutes, ok := estimate(jd)
+	if !ok {
+		return 0, false
+	}
+	if refined, ok := estimate(jd + minutes/1440); ok {
+		minutes = refined
+	}
+	return minutes, true
+}
+
+// solarNoonUTC returns the minutes after 00:00 UTC of solar noon
+func solarNoonUTC(jd, longitude float64) float64 {
+	noon := 720 - 4*longitude - equationOfTime(julianCentury(jd-longitude/360))
+	return 720 - 4*longitude - equationOfTime(julianCentury(jd+noon/1440))
+}
+
+// sunriseHourAngle returns the hour angle in degrees at which the sun's upper
+// limb touches the horizon, allowing for refraction. It reports false during
+// polar day or night.
+func sunriseHourAngle(latitude, declination float64) (float64, bool) {
+	lat, decl := degToRad(latitude), degToRad(declination)
+	arg := math.Cos(degToRad(90.833))/(math.Cos(lat)*math.Cos(decl)) - math.Tan(lat)*math.Tan(decl)
+	if arg < -1 || arg > 1 {
+		return 0, false
+	}
+	return radToDeg(math.Acos(arg)), true
+}
+
+// julianCentury converts a Julian day to centuries since J2000.0
+func julianCentury(jd float64) float64 {
+	return (jd - 2451545.0) / 36525.0
+}
+
+// sunDeclination returns the sun's apparent declination in degrees
+func sunDeclination(t float64) float64 {
+	return radToDeg(math.Asin(math.Sin(degToRad(obliquityCorrection(t))) * math.Sin(degToRad(sunApparentLong(t)))))
+}
+
+// equationOfTime returns the difference between apparent and mean solar time
+// in minutes
+func equationOfTime(t float64) float64 {
+	epsilon := degToRad(obliquityCorrection(t))
+	l0 := degToRad(sunGeomMeanLong(t))
+	m := degToRad(sunGeomMeanAnomaly(t))
+	e := 0.016708634 - t*(0.000042037+0.0000001267*t)
+	y := math.Pow(math.Tan(epsilon/2), 2)
+
+	eqTime := y*math.Sin(2*l0) - 2*e*math.Sin(m) + 4*e*y*math.Sin(m)*math.Cos(2*l0) -
+		0.5*y*y*math.Sin(4*l0) - 1.25*e*e*math.Sin(2*m)
+	return radToDeg(eqTime) * 4
+}
+
+// sunGeomMeanLong returns the sun's geometric mean longitude in degrees
+func sunGeomMeanLong(t float64) float64 {
+	return math.Mod(280.46646+t*(36000.76983+t*0.0003032), 360)
+}
+
+// sunGeomMeanAnomaly returns the sun's geometric mean anomaly in degrees
+func sunGeomMeanAnomaly(t float64) float64 {
+	return 357.52911 + t*(35999.05029-0.0001537*t)
+}
+
+// sunApparentLong returns the sun's apparent longitude in degrees
+func sunApparentLong(t float64) float64 {
+	m := degToRad(sunGeomMeanAnomaly(t))
+	center := math.Sin(m)*(1.914602-t*(0.004817+0.000014*t)) +
+		math.Sin(2*m)*(0.019993-0.000101*t) + math.Sin(3*m)*0.000289
+	omega := 125.04 - 1934.136*t
+	return sunGeomMeanLong(t) + center - 0.00569 - 0.00478*math.Sin(degToRad(omega))
+}
+
+// obliquityCorrection returns the corrected obliquity of the ecliptic in degrees
+func obliquityCorrection(t float64) float64 {
+	seconds := 21.448 - t*(46.8150+t*(0.00059-t*0.001813))
+	mean := 23 + (26+seconds/60)/60
+	omega := 125.04 - 1934.136*t
+	return mean + 0.00256*math.Cos(degToRad(omega))
+}
+
+func degToRad(deg float64) float64 { return deg * math.Pi / 180 }
+
+func radToDeg(rad float64) float64 { return rad * 180 / math.Pi }
@@ -0,0 +1,64 @@
+package tool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSun_NewYorkSummerSolstice(t *testing.T) {
+	got := runTool(t, NewDateTimeTool(), map[string]any{
+		"action": "sun", "date": "2024-06-20", "latitude": 40.7128, "longitude": -74.0060, "timezone": "America/New_York",
+	})
+
+	// Published times for New York on 2024-06-20: sunrise 05:25, solar noon 12:58, sunset 20:31 EDT
+	for field, want := range map[string]string{
+		"sunrise":    "2024-06-20T05:25:00-04:00",
+		"solar_noon": "2024-06-20T12:58:00-04:00",
+		"sunset":     "2024-06-20T20:31:00-04:00",
+	} {
+		s, _ := got[field].(string)
+		at, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("%s: expected a timestamp, got %v", field, got[field])
+		}
+		wantAt, _ := time.Parse(time.RFC3339, want)
+		if diff := at.Sub(wantAt).Abs(); diff > time.Minute {
+			t.Errorf("%s: expected about %s, got %s", field, want, s)
+		}
+	}
+	if hours, _ := got["day_length_hours"].(float64); hours < 15.05 || hours > 15.15 {
+		t.Errorf("expected a day length of about 15h06m, got %v", got["day_length"])
+	}
+	if got["polar_day"] != false || got["polar_night"] != false {
+		t.Errorf("expected no polar flags, got %v", got)
+	}
+}
+
+func TestSun_Polar(t *testing.T) {
+	tool := NewDateTimeTool()
+	tromso := func(date string) map[string]any {
+		return runTool(t, tool, map[string]any{
+			"action": "sun", "date": date, "latitude": 69.6496, "longitude": 18.9560, "timezone": "Europe/Oslo",
+		})
+	}
+
+	summer := tromso("2024-06-21")
+	if summer["polar_day"] != true || summer["day_length_hours"] != 24.0 || summer["sunrise"] != nil {
+		t.Errorf("expected polar day in Tromsø in June, got %v", summer)
+	}
+	winter := tromso("2024-12-21")
+	if winter["polar_night"] != true || winter["day_length_hours"] != 0.0 || winter["sunset"] != nil {
+		t.Errorf("expected polar night in Tromsø in December, got %v", winter)
+	}
+}
+
+func TestSun_RequiresCoordinates(t *testing.T) {
+	for _, input := range []string{
+		`{"action": "sun", "latitude": 10}`,
+		`{"action": "sun", "latitude": 91, "longitude": 0}`,
+	} {
+		if _, err := NewDateTimeTool().Handler([]byte(input)); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
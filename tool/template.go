@@ -0,0 +1,399 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// TemplateConfig configures NewTemplateTool
+type TemplateConfig struct {
+	MaxTemplateBytes   int           // max length of the template (0 = unlimited)
+	MaxDataBytes       int           // max length of the data object (0 = unlimited)
+	MaxOutputBytes     int           // output beyond this is cut and truncated is set, and longer strings built by functions fail (0 = unlimited)
+	MaxRangeIterations int           // total iterations of all range actions in one render (0 = unlimited)
+	Timeout            time.Duration // max rendering time (0 = unlimited)
+}
+
+// DefaultTemplateConfig provides sensible defaults
+func DefaultTemplateConfig() TemplateConfig {
+	return TemplateConfig{
+		MaxTemplateBytes:   64 << 10, // 64KB
+		MaxDataBytes:       1 << 20,  // 1MB
+		MaxOutputBytes:     1 << 20,  // 1MB
+		MaxRangeIterations: 100000,
+		Timeout:            5 * time.Second,
+	}
+}
+
+// templateSchema is the input schema for template
+var templateSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"template": map[string]any{
+			"type":        "string",
+			"description": "Go text/template source, e.g. 'Hello {{.name}}!' or '{{range .items}}- {{.}}\\n{{end}}'. Extra functions: upper, lower, trim, join, default",
+		},
+		"data": map[string]any{
+			"type":        "object",
+			"description": "Values available to the template as {{.field}}",
+		},
+	},
+	"required": []string{"template"},
+}
+
+// templateFuncs are the functions available besides text/template's
+// builtins. None of them touch the filesystem, network or processes.
+// Functions that build strings are added per render by stringLimit.funcs.
+var templateFuncs = template.FuncMap{
+	"trim": strings.TrimSpace,
+	"default": func(fallback, v any) any {
+		if v == nil || v == "" {
+			return fallback
+		}
+		return v
+	},
+}
+
+var (
+	errRenderLimit   = errors.New("output limit reached")  // stops rendering at the output cap
+	errRenderTimeout = errors.New("render timeout")        // stops rendering after the deadline
+	errRangeLimit    = errors.New("range iteration limit") // stops rendering at the iteration cap
+	errStringLimit   = errors.New("string length limit")   // stops rendering at the string cap
+)
+
+// rangeTickFunc is the function called at the start of every range
+// iteration to count it
+const rangeTickFunc = "_rangeTick"
+
+// NewTemplateTool creates a tool that renders Go text/template templates
+// with JSON data, e.g. for emails and reports. Templates can only read the
+// data they are given: there are no filesystem or exec functions, a
+// reference to a missing key is an error, and the sizes of template, data
+// and output, range iterations and rendering time are capped.
+func NewTemplateTool(config ...TemplateConfig) adapter.Tool {
+	cfg := DefaultTemplateConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	return adapter.NewContextTool(
+		"template",
+		"Render a Go text/template with JSON data and return the output. Use this to produce formatted text such as emails, reports, or lists from structured data.",
+		templateSchema,
+		func(ctx context.Context, input json.RawMessage) (any, error) {
+			var data struct {
+				Template string          `json:"template"`
+				Data     json.RawMessage `json:"data"`
+			}
+			// Not BindInput, which would round-trip data through float64
+			if err := json.Unmarshal(input, &data); err != nil {
+				return nil, InvalidInput("invalid input: %w", err)
+			}
+			if data.Template == "" {
+				return nil, InvalidInput("template is required")
+			}
+			if cfg.MaxTemplateBytes > 0 && len(data.Template) > cfg.MaxTemplateBytes {
+				return nil, InvalidInput("template is too large (%d bytes, max %d)", len(data.Template), cfg.MaxTemplateBytes)
+			}
+			if cfg.MaxDataBytes > 0 && len(data.Data) > cfg.MaxDataBytes {
+				return nil, InvalidInput("data is too large (%d bytes, max %d)", len(data.Data), cfg.MaxDataBytes)
+			}
+
+			values, err := decodeTemplateData(data.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			out := &renderWriter{max: cfg.MaxOutputBytes}
+			budget := &rangeBudget{limited: cfg.MaxRangeIterations > 0, left: int64(cfg.MaxRangeIterations), stop: &out.stop}
+			strs := &stringLimit{max: cfg.MaxOutputBytes, stop: &out.stop}
+			funcs := strs.funcs()
+			funcs[rangeTickFunc] = budget.tick
+			tmpl, err := template.New("template").Funcs(templateFuncs).Funcs(funcs).Option("missingkey=error").Parse(data.Template)
+			if err != nil {
+				return nil, InvalidInput("template parse error: %w", err)
+			}
+			for _, t := range tmpl.Templates() {
+				if t.Tree != nil {
+					limitRanges(t.Tree.Root)
+				}
+			}
+
+			// Render in the background, so loops that write nothing still
+			// end at the timeout. The stop flag makes the abandoned render
+			// fail at its next write or range iteration.
+			done := make(chan error, 1)
+			go func() { done <- tmpl.Execute(out, values) }()
+			var timeout <-chan time.Time
+			if cfg.Timeout > 0 {
+				timer := time.NewTimer(cfg.Timeout)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case err = <-done:
+			case <-timeout:
+				out.stop.Store(true)
+				return nil, InvalidInput("template execution error: rendering took longer than %s", cfg.Timeout)
+			case <-ctx.Done():
+				out.stop.Store(true)
+				return nil, Internal("template execution cancelled: %w", context.Cause(ctx))
+			}
+			switch {
+			case errors.Is(err, errRenderLimit):
+			case errors.Is(err, errRangeLimit):
+				return nil, InvalidInput("template execution error: more than %d range iterations", cfg.MaxRangeIterations)
+			case errors.Is(err, errStringLimit):
+				return nil, InvalidInput("template execution error: a string would be longer than %d bytes", cfg.MaxOutputBytes)
+			case err != nil:
+				return nil, InvalidInput("template execution error: %w", err)
+			}
+
+			return map[string]any{
+				"output":    out.buf.String(),
+				"bytes":     out.buf.Len(),
+				"truncated": out.truncated,
+			}, nil
+		},
+	)
+}
+
+// renderWriter collects template output up to max bytes and fails writes
+// once stop is set
+type renderWriter struct {
+	buf       bytes.Buffer
+	max       int
+	stop      atomic.Bool
+	truncated bool
+}
+
+func (w *renderWriter) Write(p []byte) (int, error) {
+	if w.stop.Load() {
+		return 0, errRenderTimeout
+	}
+	if w.max > 0 && w.buf.Len()+len(p) > w.max {
+		// Cut at a rune boundary, so the output stays valid UTF-8
+		n := w.max - w.buf.Len()
+		for n > 0 && !utf8.RuneStart(p[n]) {
+			n--
+		}
+		w.buf.Write(p[:n])
+		w.truncated = true
+		return 0, errRenderLimit
+	}
+	return w.buf.Write(p)
+}
+
+// rangeBudget counts the iterations of all range actions of a render
+type rangeBudget struct {
+	limited bool
+	left    int64 // iterations still allowed, if limited
+	stop    *atomic.Bool
+}
+
+// tick runs at the start of every range iteration. It fails once the
+// budget is spent or the render was abandoned, so even loops that write
+// nothing stop.
+func (b *rangeBudget) tick() (string, error) {
+	if b.stop.Load() {
+		return "", errRenderTimeout
+	}
+	if b.limited {
+		if b.left == 0 {
+			return "", errRangeLimit
+		}
+		b.left--
+	}
+	return "", nil
+}
+
+// stringLimit caps the strings built by template functions, so templates
+// that keep doubling a variable or pad to a huge width fail instead of
+// exhausting memory
+type stringLimit struct {
+	max  int // 0 = unlimited
+	stop *atomic.Bool
+}
+
+// funcs returns the string functions, replacing text/template's builtin
+// print, printf, println, html, js and urlquery
+func (l *stringLimit) funcs() template.FuncMap {
+	return template.FuncMap{
+		"print":    func(args ...any) (string, error) { return l.check(fmt.Sprint(args...)) },
+		"println":  func(args ...any) (string, error) { return l.check(fmt.Sprintln(args...)) },
+		"printf":   l.printf,
+		"html":     func(args ...any) (string, error) { return l.check(template.HTMLEscaper(args...)) },
+		"js":       func(args ...any) (string, error) { return l.check(template.JSEscaper(args...)) },
+		"urlquery": func(args ...any) (string, error) { return l.check(template.URLQueryEscaper(args...)) },
+		"upper":    func(s string) (string, error) { return l.check(strings.ToUpper(s)) },
+		"lower":    func(s string) (string, error) { return l.check(strings.ToLower(s)) },
+		"join":     l.join,
+	}
+}
+
+// check fails if s is over the limit or the render was abandoned
+func (l *stringLimit) check(s string) (string, error) {
+	if l.stop.Load() {
+		return "", errRenderTimeout
+	}
+	if l.max > 0 && len(s) > l.max {
+		return "", errStringLimit
+	}
+	return s, nil
+}
+
+// printf is fmt.Sprintf, refusing formats whose widths and precisions
+// alone exceed the limit: fmt pads in memory before the result can be
+// checked
+func (l *stringLimit) printf(format string, args ...any) (string, error) {
+	if l.max > 0 && printfPadding(format, args) > l.max {
+		return "", errStringLimit
+	}
+	return l.check(fmt.Sprintf(format, args...))
+}
+
+// join joins items with sep, stopping once the result is over the limit
+func (l *stringLimit) join(sep string, items []any) (string, error) {
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(fmt.Sprint(item))
+		if l.max > 0 && b.Len() > l.max {
+			return "", errStringLimit
+		}
+	}
+	return l.check(b.String())
+}
+
+// printfPadding returns an upper bound of the bytes the widths and
+// precisions of format can add. Every number in a verb counts, and with a
+// '*' in format so does every integer argument.
+func printfPadding(format string, args []any) int {
+	total := 0
+	add := func(n uint64) {
+		total = int(min(uint64(total)+min(n, math.MaxInt32), math.MaxInt32))
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		n := uint64(0)
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0; i++ {
+			if c := format[i]; '0' <= c && c <= '9' {
+				n = min(n*10+uint64(c-'0'), math.MaxInt32)
+				continue
+			}
+			add(n)
+			n = 0
+		}
+		add(n)
+	}
+	if strings.Contains(format, "*") {
+		for _, arg := range args {
+			switch v := reflect.ValueOf(arg); v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if i := v.Int(); i < 0 {
+					add(uint64(-(i + 1)) + 1)
+				} else {
+					add(uint64(i))
+				}
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				add(v.Uint())
+			}
+		}
+	}
+	return total
+}
+
+// limitRanges makes every range action below node call rangeTickFunc at
+// the start of each iteration
+func limitRanges(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			limitRanges(child)
+		}
+	case *parse.RangeNode:
+		limitRanges(n.List)
+		limitRanges(n.ElseList)
+		tick := &parse.ActionNode{
+			NodeType: parse.NodeAction,
+			Pos:      n.Pos,
+			Line:     n.Line,
+			Pipe: &parse.PipeNode{
+				NodeType: parse.NodePipe,
+				Pos:      n.Pos,
+				Line:     n.Line,
+				Cmds: []*parse.CommandNode{{
+					NodeType: parse.NodeCommand,
+					Pos:      n.Pos,
+					Args:     []parse.Node{parse.NewIdentifier(rangeTickFunc).SetPos(n.Pos)},
+				}},
+			},
+		}
+		n.List.Nodes = append([]parse.Node{tick}, n.List.Nodes...)
+	case *parse.IfNode:
+		limitRanges(n.List)
+		limitRanges(n.ElseList)
+	case *parse.WithNode:
+		limitRanges(n.List)
+		limitRanges(n.ElseList)
+	}
+}
+
+// decodeTemplateData decodes the data object, keeping whole numbers as
+// integers so they print as "1000000" rather than "1e+06"
+func decodeTemplateData(raw json.RawMessage) (any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return map[string]any{}, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, InvalidInput("invalid data: %w", err)
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return nil, InvalidInput("invalid data: must be a JSON object, got %s", getType(v))
+	}
+	return convertNumbers(v), nil
+}
+
+// convertNumbers replaces json.Number values with int64 or float64
+func convertNumbers(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]any:
+		for k, item := range val {
+			val[k] = convertNumbers(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = convertNumbers(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,169 @@
+package tool
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// renderTemplate calls the template tool with input and returns its result
+func renderTemplate(t *testing.T, input map[string]any, config ...TemplateConfig) (map[string]any, error) {
+	t.Helper()
+	raw, _ := json.Marshal(input)
+	out, err := NewTemplateTool(config...).Handler(raw)
+	if err != nil {
+		return nil, err
+	}
+	return out.(map[string]any), nil
+}
+
+func TestTemplate_Variables(t *testing.T) {
+	got, err := renderTemplate(t, map[string]any{
+		"template": "Hello {{.name}}, you have {{.count}} new messages{{if .urgent}} (urgent){{end}}. {{upper .team}}",
+		"data":     map[string]any{"name": "Ada", "count": 1000000, "urgent": true, "team": "ops"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hello Ada, you have 1000000 new messages (urgent). OPS"; got["output"] != want {
+		t.Errorf("expected %q, got %q", want, got["output"])
+	}
+}
+
+func TestTemplate_RangeOverSlice(t *testing.T) {
+	got, err := renderTemplate(t, map[string]any{
+		"template": "{{range $i, $item := .items}}{{$i}}. {{$item.name}} x{{$item.qty}}\n{{end}}Total: {{len .items}} ({{join \", \" .tags}})",
+		"data": map[string]any{
+			"items": []any{map[string]any{"name": "tea", "qty": 2}, map[string]any{"name": "cake", "qty": 1}},
+			"tags":  []any{"food", "order"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0. tea x2\n1. cake x1\nTotal: 2 (food, order)"; got["output"] != want {
+		t.Errorf("expected %q, got %q", want, got["output"])
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		input map[string]any
+		want  string
+	}{
+		{map[string]any{"template": "{{.name"}, "template parse error"},
+		{map[string]any{"template": "{{.missing}}", "data": map[string]any{}}, "template execution error"},
+		{map[string]any{"template": `{{readFile "/etc/passwd"}}`}, `function "readFile" not defined`},
+		{map[string]any{"template": "x", "data": "not an object"}, "invalid"},
+		{map[string]any{}, "template is required"},
+	}
+	for _, tt := range tests {
+		_, err := renderTemplate(t, tt.input)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tt.input, tt.want, err)
+		}
+	}
+}
+
+func TestTemplate_Limits(t *testing.T) {
+	got, err := renderTemplate(t, map[string]any{
+		"template": "{{range .items}}0123456789{{end}}",
+		"data":     map[string]any{"items": make([]any, 100)},
+	}, TemplateConfig{MaxOutputBytes: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["truncated"] != true || got["output"] != "0123456789012345678901234" {
+		t.Errorf("expected output cut at 25 bytes, got %v", got)
+	}
+
+	_, err = renderTemplate(t, map[string]any{"template": strings.Repeat("x", 11)}, TemplateConfig{MaxTemplateBytes: 10})
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected template size error, got %v", err)
+	}
+
+	// Recursive templates stop at text/template's depth limit
+	_, err = renderTemplate(t, map[string]any{"template": `{{define "loop"}}{{template "loop" .}}{{end}}{{template "loop" .}}`},
+		TemplateConfig{Timeout: time.Second})
+	if err == nil {
+		t.Error("expected recursion to fail")
+	}
+}
+
+func TestTemplate_LoopWithoutOutputTimesOut(t *testing.T) {
+	start := time.Now()
+	_, err := renderTemplate(t, map[string]any{"template": "{{range 3000000000}}{{end}}done"},
+		TemplateConfig{Timeout: 200 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "longer than 200ms") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected rendering to stop at the timeout, took %s", elapsed)
+	}
+}
+
+func TestTemplate_RangeIterationLimit(t *testing.T) {
+	limits := TemplateConfig{MaxRangeIterations: 100, Timeout: time.Second}
+
+	got, err := renderTemplate(t, map[string]any{"template": "{{range 10}}{{range $.items}}x{{end}}{{end}}", "data": map[string]any{"items": make([]any, 9)}}, limits)
+	if err != nil || len(got["output"].(string)) != 90 {
+		t.Fatalf("expected 100 iterations to be allowed, got %v, %v", got, err)
+	}
+
+	// Nested ranges count towards one budget
+	_, err = renderTemplate(t, map[string]any{"template": "{{range 10}}{{range $.items}}x{{end}}{{end}}", "data": map[string]any{"items": make([]any, 10)}}, limits)
+	if err == nil || !strings.Contains(err.Error(), "more than 100 range iterations") {
+		t.Errorf("expected the iteration limit, got %v", err)
+	}
+	_, err = renderTemplate(t, map[string]any{"template": "{{range .n}}{{end}}", "data": map[string]any{"n": 3000000000}}, limits)
+	if err == nil || !strings.Contains(err.Error(), "range iterations") {
+		t.Errorf("expected the iteration limit for a range over data, got %v", err)
+	}
+}
+
+func TestTemplate_StringLimit(t *testing.T) {
+	limits := TemplateConfig{MaxOutputBytes: 1 << 20, Timeout: 5 * time.Second}
+
+	got, err := renderTemplate(t, map[string]any{"template": `{{printf "%05.1f|%-4s|%*d" 3.14159 "ab" 3 7}} {{join "," .tags}}`, "data": map[string]any{"tags": []any{"a", "b"}}}, limits)
+	if err != nil || got["output"] != "003.1|ab  |  7 a,b" {
+		t.Fatalf("expected formatted output, got %v, %v", got, err)
+	}
+
+	templates := []string{
+		`{{$s := "xxxxxxxx"}}{{range 30}}{{$s = printf "%s%s" $s $s}}{{end}}`,
+		`{{$s := "xxxxxxxx"}}{{range 30}}{{$s = print $s $s}}{{end}}`,
+		`{{$s := "\\"}}{{range 30}}{{$s = js $s}}{{end}}`,
+		`{{printf "%*d" 1000000000 1}}`,
+		`{{printf "%.1000000000f" 1.0}}`,
+		`{{join "" .big}}`,
+	}
+	big := make([]any, 300)
+	for i := range big {
+		big[i] = strings.Repeat("x", 4096)
+	}
+	for _, tmpl := range templates {
+		_, err := renderTemplate(t, map[string]any{"template": tmpl, "data": map[string]any{"big": big}}, limits)
+		if err == nil || !strings.Contains(err.Error(), "longer than 1048576 bytes") {
+			t.Errorf("%s: expected the string limit, got %v", tmpl, err)
+		}
+	}
+}
+
+func TestTemplate_DataLimit(t *testing.T) {
+	_, err := renderTemplate(t, map[string]any{"template": "{{.s}}", "data": map[string]any{"s": strings.Repeat("x", 100)}},
+		TemplateConfig{MaxDataBytes: 50})
+	if err == nil || !strings.Contains(err.Error(), "data is too large") {
+		t.Errorf("expected data size error, got %v", err)
+	}
+}
+
+func TestTemplate_TruncatesAtRuneBoundary(t *testing.T) {
+	got, err := renderTemplate(t, map[string]any{"template": "ab日本語"}, TemplateConfig{MaxOutputBytes: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["output"] != "ab日" || got["truncated"] != true {
+		t.Errorf("expected output cut before the split character, got %q", got["output"])
+	}
+}
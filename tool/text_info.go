@@ -0,0 +1,233 @@
+package tool
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// textInfoSchema is the input schema for text_info
+var textInfoSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"text": map[string]any{
+			"type":        "string",
+			"description": "Text to analyze",
+		},
+	},
+	"required": []string{"text"},
+}
+
+// textScripts are the writing systems text_info recognizes, in the order
+// ties are broken
+var textScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Arabic", unicode.Arabic},
+	{"Hebrew", unicode.Hebrew},
+	{"Devanagari", unicode.Devanagari},
+	{"Thai", unicode.Thai},
+	{"Hangul", unicode.Hangul},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Han", unicode.Han},
+}
+
+// scriptLanguages maps scripts used by essentially one major language to it
+var scriptLanguages = map[string]string{
+	"Greek":      "el",
+	"Hebrew":     "he",
+	"Thai":       "th",
+	"Hangul":     "ko",
+	"Hiragana":   "ja",
+	"Katakana":   "ja",
+	"Devanagari": "hi",
+}
+
+// languageStopwords are frequent function words per language, used to tell
+// apart languages that share a script
+var languageStopwords = map[string][]string{
+	// Latin
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "was", "for", "with", "on", "are", "this", "be", "you", "not", "have", "but", "they"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "las", "del", "por", "un", "una", "es", "con", "para", "se", "no", "lo", "su", "al"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "sie", "es", "zu", "den", "mit", "ein", "eine", "auf", "auch", "dem", "sich", "von", "wir"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "du", "que", "pas", "pour", "dans", "qui", "sur", "au", "avec", "il", "elle", "nous", "vous"},
+	"it": {"il", "di", "che", "e", "la", "per", "non", "una", "sono", "della", "gli", "con", "del", "le", "si", "questo", "anche", "ma", "come", "nel"},
+	"pt": {"o", "de", "que", "e", "do", "da", "em", "um", "para", "com", "não", "uma", "os", "no", "se", "na", "por", "mais", "as", "dos"},
+	"nl": {"de", "het", "een", "en", "van", "ik", "te", "dat", "die", "in", "is", "niet", "op", "voor", "met", "zijn", "maar", "ook", "wat", "wij"},
+	// Cyrillic
+	"ru": {"и", "в", "не", "на", "что", "я", "с", "он", "как", "это", "по", "но", "его", "все", "она", "так", "же", "из", "за", "вы"},
+	"uk": {"і", "в", "не", "на", "що", "я", "з", "він", "як", "це", "та", "але", "його", "ще", "вона", "так", "й", "із", "за", "ви"},
+	// Arabic
+	"ar": {"في", "من", "على", "أن", "إلى", "عن", "هذا", "التي", "الذي", "مع", "كان", "هذه", "لا", "ما", "أو"},
+	"fa": {"و", "در", "به", "از", "که", "این", "را", "با", "است", "برای", "آن", "یک", "می", "شد", "هم"},
+}
+
+// stopwordSets holds languageStopwords as sets
+var stopwordSets = func() map[string]map[string]bool {
+	sets := map[string]map[string]bool{}
+	for lang, words := range languageStopwords {
+		sets[lang] = map[string]bool{}
+		for _, w := range words {
+			sets[lang][w] = true
+		}
+	}
+	return sets
+}()
+
+// NewTextInfoTool creates a tool that describes a snippet of text. It can:
+// - Count characters, bytes, words and lines
+// - Report the dominant script (Latin, Cyrillic, Han, ...) and whether the
+// text is plain ASCII or valid UTF-8
+// - Guess the language from its script, or from stopwords for the languages
+// that share one (English, Spanish, German, French, Italian, Portuguese,
+// Dutch, Russian, Ukrainian, Arabic, Persian)
+//
+// Language detection is a heuristic: short snippets and mixed text give low
+// confidence or "unknown".
+func NewTextInfoTool() adapter.Tool {
+	return adapter.NewTool(
+		"text_info",
+		"Describe a text: character, word and line counts, the writing script, encoding validity, and a best-guess language with a confidence score. Use this to decide how to handle text in an unknown language.",
+		textInfoSchema,
+		func(input json.RawMessage) (any, error) {
+			var data struct {
+				Text string `json:"text"`
+			}
+			if err := BindInput(input, &data, textInfoSchema); err != nil {
+				return nil, err
+			}
+			return textInfo(data.Text), nil
+		},
+	)
+}
+
+// textInfo computes the text_info result for text
+func textInfo(text string) map[string]any {
+	words := strings.FieldsFunc(text, isWordSeparator)
+	script := dominantScript(text)
+	lang, confidence := detectLanguage(script, words)
+
+	encoding := "utf-8"
+	switch {
+	case !utf8.ValidString(text):
+		encoding = "invalid"
+	case isASCII(text):
+		encoding = "ascii"
+	}
+
+	lines := 0
+	if text != "" {
+		lines = strings.Count(strings.TrimSuffix(text, "\n"), "\n") + 1
+	}
+
+	return map[string]any{
+		"chars":      utf8.RuneCountInString(text),
+		"bytes":      len(text),
+		"words":      len(words),
+		"lines":      lines,
+		"script":     script,
+		"encoding":   encoding,
+		"utf8_valid": utf8.ValidString(text) && !strings.ContainsRune(text, utf8.RuneError),
+		"language":   lang,
+		"confidence": confidence,
+	}
+}
+
+// isASCII reports whether s contains only 7-bit characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// dominantScript returns the script most letters of text belong to, or
+// "unknown" when there are no letters in a recognized script. Japanese kana
+// win over Han, since Japanese mixes both.
+func dominantScript(text string) string {
+	counts := map[string]int{}
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		for _, s := range textScripts {
+			if unicode.Is(s.table, r) {
+				counts[s.name]++
+				break
+			}
+		}
+	}
+
+	if counts["Hiragana"]+counts["Katakana"] > 0 && counts["Hiragana"]+counts["Katakana"] >= counts["Han"]/4 {
+		if counts["Hiragana"] >= counts["Katakana"] {
+			return "Hiragana"
+		}
+		return "Katakana"
+	}
+
+	best, most := "unknown", 0
+	for _, s := range textScripts {
+		if counts[s.name] > most {
+			best, most = s.name, counts[s.name]
+		}
+	}
+	return best
+}
+
+// detectLanguage guesses the ISO 639-1 language of words written in script,
+// with a confidence between 0 and 1
+func detectLanguage(script string, words []string) (string, float64) {
+	if lang, ok := scriptLanguages[script]; ok {
+		return lang, 0.9
+	}
+	if script == "Han" {
+		// Han alone is most likely Chinese; Japanese would show kana
+		return "zh", 0.7
+	}
+	if len(words) == 0 {
+		return "unknown", 0
+	}
+
+	scores := map[string]int{}
+	for _, w := range words {
+		w = strings.ToLower(w)
+		for lang, set := range stopwordSets {
+			if set[w] {
+				scores[lang]++
+			}
+		}
+	}
+
+	best, bestScore, second := "unknown", 0, 0
+	for lang, score := range scores {
+		switch {
+		case score > bestScore || score == bestScore && lang < best:
+			second = max(second, bestScore)
+			best, bestScore = lang, score
+		case score > second:
+			second = score
+		}
+	}
+	if bestScore == 0 {
+		return "unknown", 0
+	}
+
+	// Confidence grows with the share of stopwords and the lead over the
+	// runner-up, and is capped for very short texts
+	share := math.Min(1, float64(bestScore)/float64(len(words))*3)
+	lead := float64(bestScore-second) / float64(bestScore)
+	length := math.Min(1, float64(len(words))/10)
+	confidence := math.Round(share*(0.5+0.5*lead)*length*100) / 100
+	return best, confidence
+}
@@ -0,0 +1,52 @@
+package tool
+
+import "testing"
+
+func TestTextInfo_Language(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The quick brown fox jumps over the lazy dog, and it was not in the mood for this.", "en"},
+		{"El perro de mi vecino es muy grande y no le gusta que los niños jueguen en la calle.", "es"},
+		{"Der Hund ist nicht groß, aber er hat eine laute Stimme und mag die Katze von nebenan nicht.", "de"},
+		{"Le chat est sur la table et il ne veut pas descendre pour manger avec nous.", "fr"},
+		{"Это был хороший день, и мы не хотели уходить из парка, но она сказала, что пора.", "ru"},
+		{"今日は天気がいいので、公園に散歩に行きました。", "ja"},
+		{"今天天气很好，我们去公园散步。", "zh"},
+		{"오늘은 날씨가 좋아서 공원에 산책하러 갔습니다.", "ko"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := runTool(t, NewTextInfoTool(), map[string]any{"text": tt.text})
+			if got["language"] != tt.want {
+				t.Errorf("expected %s, got %v", tt.want, got)
+			}
+			if got["confidence"].(float64) <= 0.3 {
+				t.Errorf("expected a confident guess, got %v", got["confidence"])
+			}
+		})
+	}
+}
+
+func TestTextInfo_Counts(t *testing.T) {
+	got := runTool(t, NewTextInfoTool(), map[string]any{"text": "Grüße aus Köln\nzweite Zeile\n"})
+
+	if got["chars"] != 28 || got["bytes"] != 31 || got["words"] != 5 || got["lines"] != 2 {
+		t.Errorf("unexpected counts %v", got)
+	}
+	if got["script"] != "Latin" || got["encoding"] != "utf-8" || got["utf8_valid"] != true {
+		t.Errorf("unexpected script or encoding %v", got)
+	}
+
+	ascii := runTool(t, NewTextInfoTool(), map[string]any{"text": "12345 !!!"})
+	if ascii["encoding"] != "ascii" || ascii["script"] != "unknown" || ascii["language"] != "unknown" {
+		t.Errorf("expected ascii text without a language, got %v", ascii)
+	}
+
+	// A replacement character suggests text that was decoded wrongly
+	if bad := runTool(t, NewTextInfoTool(), map[string]any{"text": "caf�"}); bad["utf8_valid"] != false {
+		t.Errorf("expected utf8_valid false, got %v", bad)
+	}
+}
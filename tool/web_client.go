@@ -0,0 +1,86 @@
+package tool
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebClientConfig configures NewWebClient
+type WebClientConfig struct {
+	Timeout             time.Duration // limit for a whole request, including the body
+	MaxIdleConns        int           // idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // idle connections kept per host
+	IdleConnTimeout     time.Duration // how long an idle connection is kept
+}
+
+// DefaultWebClientConfig provides sensible defaults
+func DefaultWebClientConfig() WebClientConfig {
+	return WebClientConfig{
+		Timeout:             15 * time.Second,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// NewWebClient creates an HTTP client whose transport pools connections,
+// suitable for SetWebClient
+func NewWebClient(config ...WebClientConfig) *http.Client {
+	cfg := DefaultWebClientConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          cfg.MaxIdleConns,
+			MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+			IdleConnTimeout:       cfg.IdleConnTimeout,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+var (
+	webClientMu sync.RWMutex
+	webClient   = NewWebClient()
+)
+
+// WebClient returns the HTTP client shared by web_fetch, web_read and
+// web_search
+func WebClient() *http.Client {
+	webClientMu.RLock()
+	defer webClientMu.RUnlock()
+	return webClient
+}
+
+// SetWebClient replaces the HTTP client shared by the web tools, e.g. to
+// tune pooling or add a proxy. The tools still apply their own redirect
+// policies and the circuit breaker. A nil client restores the default.
+func SetWebClient(client *http.Client) {
+	if client == nil {
+		client = NewWebClient()
+	}
+	webClientMu.Lock()
+	defer webClientMu.Unlock()
+	webClient = client
+}
+
+// newWebClient returns a copy of the shared client with checkRedirect, which
+// may be nil, guarded by the shared circuit breaker. The copy keeps the
+// shared transport, so connections are reused across calls and tools.
+func newWebClient(checkRedirect func(*http.Request, []*http.Request) error) *http.Client {
+	client := *WebClient()
+	client.Transport = webBreaker.Transport(client.Transport)
+	client.CheckRedirect = checkRedirect
+	return &client
+}
@@ -0,0 +1,75 @@
+package tool
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+)
+
+// reuseTracker counts new and reused connections of the requests it sends
+type reuseTracker struct {
+	next          http.RoundTripper
+	conns, reused atomic.Int32
+}
+
+func (t *reuseTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.conns.Add(1)
+			if info.Reused {
+				t.reused.Add(1)
+			}
+		},
+	}
+	return t.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+func TestWebClient_ReusesConnections(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body><p>Hello</p></body></html>"))
+	}))
+	defer srv.Close()
+
+	client := NewWebClient()
+	tracker := &reuseTracker{next: client.Transport}
+	client.Transport = tracker
+	SetWebClient(client)
+	defer SetWebClient(nil)
+
+	input, _ := json.Marshal(map[string]string{"url": srv.URL})
+	for _, tool := range []struct {
+		name    string
+		handler func(json.RawMessage) (any, error)
+	}{
+		{"web_fetch", NewWebFetchTool().Handler},
+		{"web_fetch", NewWebFetchTool().Handler},
+		{"web_read", NewWebReadTool().Handler},
+	} {
+		if _, err := tool.handler(input); err != nil {
+			t.Fatalf("%s: %v", tool.name, err)
+		}
+	}
+
+	if got := tracker.conns.Load(); got != 3 {
+		t.Fatalf("expected 3 requests through the shared client, got %d", got)
+	}
+	if got := tracker.reused.Load(); got != 2 {
+		t.Errorf("expected 2 reused connections, got %d", got)
+	}
+}
+
+func TestSetWebClient_NilRestoresDefault(t *testing.T) {
+	custom := &http.Client{}
+	SetWebClient(custom)
+	if WebClient() != custom {
+		t.Fatal("expected the custom client to be shared")
+	}
+	SetWebClient(nil)
+	if c := WebClient(); c == custom || c.Transport == nil {
+		t.Error("expected a pooled default client after SetWebClient(nil)")
+	}
+}
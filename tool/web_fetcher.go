@@ -1,21 +1,41 @@
 package tool
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/dvictor357/blaze/adapter"
 )
 
+// WebFetchConfig configures NewWebFetchTool
+type WebFetchConfig struct {
+	MaxRedirects    int  // redirects to follow before failing (0 = fail on any redirect)
+	RefuseDowngrade bool // fail when a redirect goes from https to http
+}
+
+// DefaultWebFetchConfig provides sensible defaults
+func DefaultWebFetchConfig() WebFetchConfig {
+	return WebFetchConfig{
+		MaxRedirects: 10,
+	}
+}
+
 // NewWebFetchTool creates a basic HTTP fetcher that returns raw content.
 // Use this when you need the unprocessed response (e.g., for APIs, JSON, raw data).
 // For reading webpages, prefer NewWebReadTool which provides clean Markdown.
-func NewWebFetchTool() adapter.Tool {
-	return adapter.NewTool(
+// The result includes final_url, the URL after following redirects.
+func NewWebFetchTool(config ...WebFetchConfig) adapter.Tool {
+	cfg := DefaultWebFetchConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	t := adapter.NewContextTool(
 		"web_fetch",
 		"Fetch raw content from a URL (HTTP GET). Returns unprocessed response body. Best for APIs or when you need raw data. For readable webpage content, use 'web_read' instead.",
 		map[string]any{
@@ -32,26 +52,26 @@ func NewWebFetchTool() adapter.Tool {
 			},
 			"required": []string{"url"},
 		},
-		func(input json.RawMessage) (any, error) {
+		func(ctx context.Context, input json.RawMessage) (any, error) {
 			var data struct {
 				URL     string            `json:"url"`
 				Headers map[string]string `json:"headers"`
 			}
 			if err := json.Unmarshal(input, &data); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+				return nil, InvalidInput("invalid input: %w", err)
 			}
 
 			if data.URL == "" {
-				return nil, fmt.Errorf("url cannot be empty")
+				return nil, InvalidInput("url cannot be empty")
 			}
 			if !strings.HasPrefix(data.URL, "http") {
 				data.URL = "https://" + data.URL
 			}
 
-			client := &http.Client{Timeout: 15 * time.Second}
-			req, err := http.NewRequest("GET", data.URL, nil)
+			client := newWebClient(redirectPolicy(cfg.MaxRedirects, cfg.RefuseDowngrade))
+			req, err := http.NewRequestWithContext(ctx, "GET", data.URL, nil)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create request: %w", err)
+				return nil, InvalidInput("failed to create request: %w", err)
 			}
 
 			// Set default User-Agent
@@ -64,7 +84,7 @@ func NewWebFetchTool() adapter.Tool {
 
 			resp, err := client.Do(req)
 			if err != nil {
-				return nil, fmt.Errorf("request failed: %w", err)
+				return nil, Upstream("request failed: %w", err)
 			}
 			defer resp.Body.Close()
 
@@ -72,9 +92,12 @@ func NewWebFetchTool() adapter.Tool {
 			const MaxBodySize = 50 * 1024
 			body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
 			if err != nil {
-				return nil, fmt.Errorf("failed to read body: %w", err)
+				return nil, Upstream("failed to read body: %w", err)
 			}
 
+			// Transcode to UTF-8 using the declared or detected charset
+			text, charset := decodeBody(body, resp.Header.Get("Content-Type"))
+
 			// Collect response headers
 			respHeaders := make(map[string]string)
 			for k, v := range resp.Header {
@@ -83,15 +106,55 @@ func NewWebFetchTool() adapter.Tool {
 				}
 			}
 
-			return map[string]any{
+			result := map[string]any{
 				"status":       resp.StatusCode,
 				"url":          data.URL,
+				"final_url":    resp.Request.URL.String(),
 				"content_type": resp.Header.Get("Content-Type"),
 				"headers":      respHeaders,
-				"body":         string(body),
+				"charset":      charset,
+				"body":         text,
 				"size":         len(body),
 				"truncated":    len(body) >= MaxBodySize,
-			}, nil
+			}
+
+			// Decode JSON responses so the model doesn't have to re-parse the body.
+			// A body cut off at MaxBodySize won't parse and is left raw.
+			if isJSONContentType(resp.Header.Get("Content-Type")) {
+				var parsed any
+				if err := json.Unmarshal([]byte(text), &parsed); err == nil {
+					result["json"] = parsed
+				}
+			}
+
+			return result, nil
 		},
 	)
+	t.SideEffect = true // reaches the network
+	return t
+}
+
+// isJSONContentType reports whether a Content-Type is application/json or a
+// +json suffix type such as application/problem+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// redirectPolicy returns a CheckRedirect func that follows at most max
+// redirects and, if refuseDowngrade is set, refuses https to http redirects
+func redirectPolicy(max int, refuseDowngrade bool) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return fmt.Errorf("stopped after %d redirects", max)
+		}
+		prev := via[len(via)-1]
+		if refuseDowngrade && prev.URL.Scheme == "https" && req.URL.Scheme == "http" {
+			return fmt.Errorf("refusing redirect from https to http (%s)", req.URL)
+		}
+		return nil
+	}
 }
@@ -0,0 +1,185 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/blaze"
+	"github.com/dvictor357/blaze/adapter"
+)
+
+// redirectChain serves /0 -> /1 -> ... -> /n, where /n returns "done"
+func redirectChain(n int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var step int
+		if _, err := fmt.Sscan(r.URL.Path[1:], &step); err != nil || step >= n {
+			w.Write([]byte("done"))
+			return
+		}
+		http.Redirect(w, r, "/"+strconv.Itoa(step+1), http.StatusFound)
+	}))
+}
+
+func TestWebFetch_FinalURL(t *testing.T) {
+	srv := redirectChain(3)
+	defer srv.Close()
+
+	input, _ := json.Marshal(map[string]string{"url": srv.URL + "/0"})
+	out, err := NewWebFetchTool().Handler(input)
+	if err != nil {
+		t.Fatalf("web_fetch failed: %v", err)
+	}
+	result := out.(map[string]any)
+	if result["final_url"] != srv.URL+"/3" {
+		t.Errorf("expected final_url %s/3, got %v", srv.URL, result["final_url"])
+	}
+	if result["url"] != srv.URL+"/0" || result["body"] != "done" {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestWebFetch_RedirectCap(t *testing.T) {
+	srv := redirectChain(3)
+	defer srv.Close()
+	input, _ := json.Marshal(map[string]string{"url": srv.URL + "/0"})
+
+	// Exactly at the cap succeeds
+	if _, err := NewWebFetchTool(WebFetchConfig{MaxRedirects: 3}).Handler(input); err != nil {
+		t.Errorf("expected 3 redirects to be allowed, got %v", err)
+	}
+
+	// One over the cap fails
+	_, err := NewWebFetchTool(WebFetchConfig{MaxRedirects: 2}).Handler(input)
+	if err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Errorf("expected redirect cap error, got %v", err)
+	}
+}
+
+func TestRedirectPolicy_Downgrade(t *testing.T) {
+	mustReq := func(raw string) *http.Request {
+		u, _ := url.Parse(raw)
+		return &http.Request{URL: u}
+	}
+	via := []*http.Request{mustReq("https://example.com/")}
+
+	if err := redirectPolicy(10, true)(mustReq("http://example.com/"), via); err == nil {
+		t.Error("expected https to http redirect to be refused")
+	}
+	if err := redirectPolicy(10, true)(mustReq("https://example.org/"), via); err != nil {
+		t.Errorf("expected https to https redirect to be allowed, got %v", err)
+	}
+	if err := redirectPolicy(10, false)(mustReq("http://example.com/"), via); err != nil {
+		t.Errorf("expected downgrade to be allowed by default, got %v", err)
+	}
+}
+
+func TestWebFetch_ContentTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"login":"golang","repos":[1,2]}`))
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Write([]byte(`{"title":"Not Found"}`))
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(`{"looks":"like json"}`))
+		}
+	}))
+	defer srv.Close()
+
+	fetch := func(path string) map[string]any {
+		input, _ := json.Marshal(map[string]string{"url": srv.URL + path})
+		out, err := NewWebFetchTool().Handler(input)
+		if err != nil {
+			t.Fatalf("web_fetch %s failed: %v", path, err)
+		}
+		return out.(map[string]any)
+	}
+
+	result := fetch("/json")
+	parsed, ok := result["json"].(map[string]any)
+	if !ok || parsed["login"] != "golang" || len(parsed["repos"].([]any)) != 2 {
+		t.Errorf("expected parsed json field, got %v", result["json"])
+	}
+	if result["body"] != `{"login":"golang","repos":[1,2]}` {
+		t.Errorf("expected raw body to be kept, got %v", result["body"])
+	}
+
+	if parsed, ok := fetch("/problem")["json"].(map[string]any); !ok || parsed["title"] != "Not Found" {
+		t.Errorf("expected +json type to be parsed, got %v", parsed)
+	}
+
+	result = fetch("/text")
+	if _, ok := result["json"]; ok {
+		t.Errorf("expected no json field for text/plain, got %v", result["json"])
+	}
+	if result["body"] != `{"looks":"like json"}` {
+		t.Errorf("expected text body as-is, got %v", result["body"])
+	}
+}
+
+func TestWebFetch_CancelledWithRequest(t *testing.T) {
+	started := make(chan struct{})
+	aborted := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	e := blaze.New()
+	e.POST("/chat", adapter.AnthropicAdapter(NewWebFetchTool()))
+	body, _ := json.Marshal(adapter.AnthropicChatRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []adapter.AnthropicMessage{{Role: "user", Content: []adapter.AnthropicContentBlock{
+			{Type: "tool_use", ID: "toolu_1", Name: "web_fetch", Input: map[string]any{"url": srv.URL}},
+		}}},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/chat", strings.NewReader(string(body))).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	done := make(chan struct{})
+	go func() {
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("outbound request never reached the server")
+	}
+	cancel()
+
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("outbound request was not aborted after cancellation")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return after cancellation")
+	}
+	b := WebCircuitBreaker()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s := b.hosts[strings.TrimPrefix(srv.URL, "http://")]; s != nil && s.failures > 0 {
+		t.Errorf("expected a cancelled request not to count against the host, got %d failures", s.failures)
+	}
+}
@@ -1,14 +1,15 @@
 package tool
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/dvictor357/blaze/adapter"
 )
@@ -17,11 +18,11 @@ import (
 // 1. Fetches the URL
 // 2. Extracts the main content (removes nav, ads, footers)
 // 3. Converts HTML to clean Markdown
-// 4. Extracts metadata (title, description, links)
+// 4. Extracts metadata (title, description, author, publish date, canonical URL, links)
 //
 // This saves tokens and gives the AI readable content instead of HTML soup.
 func NewWebReadTool() adapter.Tool {
-	return adapter.NewTool(
+	t := adapter.NewContextTool(
 		"web_read",
 		"Read a webpage and return clean, readable content in Markdown format. Extracts the main article content, removes navigation/ads/clutter, and provides metadata. Use this to read documentation, articles, or any webpage.",
 		map[string]any{
@@ -34,40 +35,41 @@ func NewWebReadTool() adapter.Tool {
 			},
 			"required": []string{"url"},
 		},
-		func(input json.RawMessage) (any, error) {
+		func(ctx context.Context, input json.RawMessage) (any, error) {
 			var data struct {
 				URL string `json:"url"`
 			}
 			if err := json.Unmarshal(input, &data); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+				return nil, InvalidInput("invalid input: %w", err)
 			}
 
 			if data.URL == "" {
-				return nil, fmt.Errorf("url cannot be empty")
+				return nil, InvalidInput("url cannot be empty")
 			}
 			if !strings.HasPrefix(data.URL, "http") {
 				data.URL = "https://" + data.URL
 			}
 
 			// Fetch the page
-			client := &http.Client{Timeout: 15 * time.Second}
-			req, _ := http.NewRequest("GET", data.URL, nil)
+			client := newWebClient(nil)
+			req, _ := http.NewRequestWithContext(ctx, "GET", data.URL, nil)
 			req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; BlazeBot/1.0; +https://github.com/dvictor357/blaze)")
 			req.Header.Set("Accept", "text/html,application/xhtml+xml")
 
 			resp, err := client.Do(req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to fetch: %w", err)
+				return nil, Upstream("failed to fetch: %w", err)
 			}
 			defer resp.Body.Close()
 
 			// Limit to 500KB to prevent memory issues
 			body, err := io.ReadAll(io.LimitReader(resp.Body, 500*1024))
 			if err != nil {
-				return nil, fmt.Errorf("failed to read body: %w", err)
+				return nil, Upstream("failed to read body: %w", err)
 			}
 
-			html := string(body)
+			// Transcode to UTF-8 using the declared or detected charset
+			html, charset := decodeBody(body, resp.Header.Get("Content-Type"))
 
 			// Extract metadata
 			title := extractMeta(html, `<title[^>]*>([^<]+)</title>`)
@@ -82,12 +84,19 @@ func NewWebReadTool() adapter.Tool {
 				description = ogDesc
 			}
 
+			// Citation metadata, preferring JSON-LD over meta tags
+			article := extractArticleMeta(html, data.URL)
+
 			// Extract and clean main content
 			content := extractMainContent(html)
 			markdown := htmlToMarkdown(content)
 
 			// Extract links from the page
 			links := extractLinks(html, data.URL)
+			nextURL, prevURL := extractPagination(html, data.URL)
+
+			// Length and difficulty of the full content, before truncation
+			stats := computeReadingStats(markdown)
 
 			// Truncate markdown to preserve context window (max 8KB)
 			const MaxContentSize = 8 * 1024
@@ -98,16 +107,27 @@ func NewWebReadTool() adapter.Tool {
 			}
 
 			return map[string]any{
-				"url":         data.URL,
-				"title":       title,
-				"description": description,
-				"content":     markdown,
-				"links":       links,
-				"truncated":   truncated,
-				"status":      resp.StatusCode,
+				"url":                  data.URL,
+				"title":                title,
+				"description":          description,
+				"author":               article.Author,
+				"published":            article.Published,
+				"canonical":            article.Canonical,
+				"content":              markdown,
+				"links":                links,
+				"next_url":             nextURL,
+				"prev_url":             prevURL,
+				"truncated":            truncated,
+				"status":               resp.StatusCode,
+				"charset":              charset,
+				"word_count":           stats.Words,
+				"reading_ease":         stats.ReadingEase,
+				"reading_time_minutes": stats.ReadingMinutes,
 			}, nil
 		},
 	)
+	t.SideEffect = true // reaches the network
+	return t
 }
 
 // extractMainContent removes navigation, scripts, styles, and extracts the main content
@@ -150,6 +170,29 @@ func extractMainContent(html string) string {
 	return html
 }
 
+var (
+	preCodeRe = regexp.MustCompile(`(?is)<pre([^>]*)>\s*<code([^>]*)>(.*?)</code>\s*</pre>`)
+	preRe     = regexp.MustCompile(`(?is)<pre([^>]*)>(.*?)</pre>`)
+
+	// langClassRe matches class="language-go" or class="lang-go" among other classes
+	langClassRe = regexp.MustCompile(`(?i)class\s*=\s*["'][^"']*\blang(?:uage)?-([\w+#.-]+)`)
+	dataLangRe  = regexp.MustCompile(`(?i)data-lang(?:uage)?\s*=\s*["']([\w+#.-]+)["']`)
+)
+
+// codeLanguage returns the language hint from the first attribute string
+// that has one, or "" for a bare fence
+func codeLanguage(attrs ...string) string {
+	for _, a := range attrs {
+		if m := langClassRe.FindStringSubmatch(a); m != nil {
+			return strings.ToLower(m[1])
+		}
+		if m := dataLangRe.FindStringSubmatch(a); m != nil {
+			return strings.ToLower(m[1])
+		}
+	}
+	return ""
+}
+
 // htmlToMarkdown converts HTML to Markdown
 func htmlToMarkdown(html string) string {
 	md := html
@@ -162,6 +205,17 @@ func htmlToMarkdown(html string) string {
 	md = regexp.MustCompile(`(?is)<h5[^>]*>(.*?)</h5>`).ReplaceAllString(md, "\n##### $1\n")
 	md = regexp.MustCompile(`(?is)<h6[^>]*>(.*?)</h6>`).ReplaceAllString(md, "\n###### $1\n")
 
+	// Convert pre/code blocks first, so the inline code rule below doesn't
+	// claim their <code> element
+	md = preCodeRe.ReplaceAllStringFunc(md, func(block string) string {
+		m := preCodeRe.FindStringSubmatch(block)
+		return "\n```" + codeLanguage(m[2], m[1]) + "\n" + m[3] + "\n```\n"
+	})
+	md = preRe.ReplaceAllStringFunc(md, func(block string) string {
+		m := preRe.FindStringSubmatch(block)
+		return "\n```" + codeLanguage(m[1]) + "\n" + m[2] + "\n```\n"
+	})
+
 	// Convert formatting
 	md = regexp.MustCompile(`(?is)<strong[^>]*>(.*?)</strong>`).ReplaceAllString(md, "**$1**")
 	md = regexp.MustCompile(`(?is)<b[^>]*>(.*?)</b>`).ReplaceAllString(md, "**$1**")
@@ -189,21 +243,11 @@ func htmlToMarkdown(html string) string {
 	// Convert blockquotes
 	md = regexp.MustCompile(`(?is)<blockquote[^>]*>(.*?)</blockquote>`).ReplaceAllString(md, "> $1\n")
 
-	// Convert pre/code blocks
-	md = regexp.MustCompile(`(?is)<pre[^>]*><code[^>]*>(.*?)</code></pre>`).ReplaceAllString(md, "\n```\n$1\n```\n")
-	md = regexp.MustCompile(`(?is)<pre[^>]*>(.*?)</pre>`).ReplaceAllString(md, "\n```\n$1\n```\n")
-
 	// Remove remaining HTML tags
 	md = regexp.MustCompile(`<[^>]+>`).ReplaceAllString(md, "")
 
-	// Decode common HTML entities
-	md = strings.ReplaceAll(md, "&nbsp;", " ")
-	md = strings.ReplaceAll(md, "&amp;", "&")
-	md = strings.ReplaceAll(md, "&lt;", "<")
-	md = strings.ReplaceAll(md, "&gt;", ">")
-	md = strings.ReplaceAll(md, "&quot;", "\"")
-	md = strings.ReplaceAll(md, "&#39;", "'")
-	md = strings.ReplaceAll(md, "&apos;", "'")
+	// Decode HTML entities (after tag stripping, so an escaped "&lt;" can't form a tag)
+	md = decodeEntities(md)
 
 	// Clean up whitespace
 	md = regexp.MustCompile(`\n{3,}`).ReplaceAllString(md, "\n\n")
@@ -213,6 +257,12 @@ func htmlToMarkdown(html string) string {
 	return md
 }
 
+// decodeEntities decodes named and numeric HTML entities, turning
+// non-breaking spaces into plain spaces so whitespace cleanup collapses them
+func decodeEntities(s string) string {
+	return strings.ReplaceAll(html.UnescapeString(s), "\u00a0", " ")
+}
+
 // extractMeta extracts content matching a regex pattern
 func extractMeta(html, pattern string) string {
 	re := regexp.MustCompile(pattern)
@@ -234,6 +284,199 @@ func extractMetaProperty(html, property string) string {
 	return extractMeta(html, pattern)
 }
 
+// articleMeta is the citation metadata of a page
+type articleMeta struct {
+	Author    string
+	Published string
+	Canonical string
+}
+
+var (
+	jsonLDRe    = regexp.MustCompile(`(?is)<script[^>]*type=["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	canonicalRe = regexp.MustCompile(`(?is)<link[^>]*rel=["']canonical["'][^>]*href=["']([^"']*)["']|<link[^>]*href=["']([^"']*)["'][^>]*rel=["']canonical["']`)
+)
+
+// extractArticleMeta reads the author, publish date and canonical URL from
+// meta tags, overridden by any values found in a JSON-LD Article block
+func extractArticleMeta(html, pageURL string) articleMeta {
+	meta := articleMeta{
+		Author:    extractMetaTag(html, "author"),
+		Published: extractMetaProperty(html, "article:published_time"),
+	}
+	if m := canonicalRe.FindStringSubmatch(html); m != nil {
+		meta.Canonical = m[1] + m[2]
+	}
+
+	if ld, ok := findJSONLDArticle(html); ok {
+		if author := jsonLDNames(ld["author"]); author != "" {
+			meta.Author = author
+		}
+		if published, _ := ld["datePublished"].(string); published != "" {
+			meta.Published = published
+		}
+		if canonical := jsonLDID(ld["mainEntityOfPage"]); canonical != "" {
+			meta.Canonical = canonical
+		} else if canonical, _ := ld["url"].(string); canonical != "" {
+			meta.Canonical = canonical
+		}
+	}
+
+	meta.Canonical = resolveURL(pageURL, strings.TrimSpace(meta.Canonical))
+	return meta
+}
+
+// findJSONLDArticle returns the first Article-typed node in the page's
+// JSON-LD blocks, looking inside arrays and @graph
+func findJSONLDArticle(html string) (map[string]any, bool) {
+	for _, m := range jsonLDRe.FindAllStringSubmatch(html, -1) {
+		var doc any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(m[1])), &doc); err != nil {
+			continue
+		}
+		if node, ok := findArticleNode(doc); ok {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+func findArticleNode(doc any) (map[string]any, bool) {
+	switch v := doc.(type) {
+	case []any:
+		for _, item := range v {
+			if node, ok := findArticleNode(item); ok {
+				return node, true
+			}
+		}
+	case map[string]any:
+		if isArticleType(v["@type"]) {
+			return v, true
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findArticleNode(graph)
+		}
+	}
+	return nil, false
+}
+
+// isArticleType matches schema.org Article and its subtypes
+// (NewsArticle, BlogPosting, ...); @type may be a string or a list
+func isArticleType(t any) bool {
+	switch v := t.(type) {
+	case string:
+		return strings.HasSuffix(v, "Article") || v == "BlogPosting" || v == "Report"
+	case []any:
+		for _, item := range v {
+			if isArticleType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDNames returns the names in a JSON-LD person value, which may be a
+// string, an object with a name, or a list of either
+func jsonLDNames(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]any:
+		name, _ := val["name"].(string)
+		return name
+	case []any:
+		var names []string
+		for _, item := range val {
+			if name := jsonLDNames(item); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// jsonLDID returns a JSON-LD reference, given as a string or {"@id": ...}
+func jsonLDID(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]any:
+		id, _ := val["@id"].(string)
+		return id
+	}
+	return ""
+}
+
+// resolveURL resolves ref against base, returning ref unchanged if either
+// fails to parse
+func resolveURL(base, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return r.String()
+}
+
+var (
+	pageLinkRe   = regexp.MustCompile(`(?is)<(?:link|a)\b([^>]*)>`)
+	anchorRe     = regexp.MustCompile(`(?is)<a\b([^>]*)>(.*?)</a>`)
+	hrefAttrRe   = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']*)["']`)
+	relAttrRe    = regexp.MustCompile(`(?i)\brel\s*=\s*["']([^"']*)["']`)
+	nextAnchorRe = regexp.MustCompile(`(?i)^(next( page)?|older( posts| entries)?)?\s*[»›→]*$`)
+	prevAnchorRe = regexp.MustCompile(`(?i)^[«‹←]*\s*(prev(ious)?( page)?|newer( posts| entries)?)?$`)
+)
+
+// extractPagination finds the next and previous page URLs of a paginated
+// page, resolved against pageURL. rel="next"/"prev" on <link> or <a> wins
+// over anchors whose text reads like "Next", "Older posts" or "»".
+func extractPagination(html, pageURL string) (next, prev string) {
+	for _, m := range pageLinkRe.FindAllStringSubmatch(html, -1) {
+		rel := relAttrRe.FindStringSubmatch(m[1])
+		href := hrefAttrRe.FindStringSubmatch(m[1])
+		if rel == nil || href == nil {
+			continue
+		}
+		for _, token := range strings.Fields(strings.ToLower(rel[1])) {
+			switch {
+			case token == "next" && next == "":
+				next = href[1]
+			case (token == "prev" || token == "previous") && prev == "":
+				prev = href[1]
+			}
+		}
+	}
+
+	if next == "" || prev == "" {
+		for _, m := range anchorRe.FindAllStringSubmatch(html, -1) {
+			href := hrefAttrRe.FindStringSubmatch(m[1])
+			if href == nil || strings.HasPrefix(href[1], "#") {
+				continue
+			}
+			text := strings.TrimSpace(cleanText(m[2]))
+			if text == "" {
+				continue
+			}
+			switch {
+			case next == "" && nextAnchorRe.MatchString(text):
+				next = href[1]
+			case prev == "" && prevAnchorRe.MatchString(text):
+				prev = href[1]
+			}
+		}
+	}
+
+	// Attribute values may carry entities, e.g. "?page=2&amp;sort=new"
+	return resolveURL(pageURL, decodeEntities(strings.TrimSpace(next))), resolveURL(pageURL, decodeEntities(strings.TrimSpace(prev)))
+}
+
 // extractLinks extracts all links from the page with their text
 func extractLinks(html, baseURL string) []map[string]string {
 	var links []map[string]string
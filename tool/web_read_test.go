@@ -0,0 +1,121 @@
+package tool
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// readPage serves page at /articles/1 and returns the web_read result for it
+func readPage(t *testing.T, page string) (map[string]any, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	t.Cleanup(srv.Close)
+
+	input, _ := json.Marshal(map[string]string{"url": srv.URL + "/articles/1"})
+	out, err := NewWebReadTool().Handler(input)
+	if err != nil {
+		t.Fatalf("web_read failed: %v", err)
+	}
+	return out.(map[string]any), srv.URL
+}
+
+func TestHTMLToMarkdown_DecodesEntities(t *testing.T) {
+	md := htmlToMarkdown(`<p>&copy; 2024 Acme &mdash; it&#x2019;s &lt;b&gt;fine&lt;/b&gt;&nbsp;&amp; done</p>`)
+
+	// The escaped tag is decoded after stripping, so it survives as text
+	want := "© 2024 Acme — it’s <b>fine</b> & done"
+	if md != want {
+		t.Errorf("expected %q, got %q", want, md)
+	}
+}
+
+func TestHTMLToMarkdown_CodeBlockLanguage(t *testing.T) {
+	cases := map[string]string{
+		`<pre><code class="hljs language-go">fmt.Println(1)</code></pre>`: "```go\nfmt.Println(1)\n```",
+		`<pre data-lang="Python"><code>print(1)</code></pre>`:             "```python\nprint(1)\n```",
+		`<pre class="lang-rust">let x = 1;</pre>`:                         "```rust\nlet x = 1;\n```",
+		`<pre><code>plain</code></pre>`:                                   "```\nplain\n```",
+	}
+	for html, want := range cases {
+		if got := htmlToMarkdown(html); got != want {
+			t.Errorf("%s: expected %q, got %q", html, want, got)
+		}
+	}
+}
+
+const articleMetaTags = `<meta name="author" content="Meta Author">
+<meta property="article:published_time" content="2024-01-01T00:00:00Z">
+<link rel="canonical" href="/articles/canonical">`
+
+func TestWebRead_ArticleMetadata_PrefersJSONLD(t *testing.T) {
+	page := `<html><head><title>Post</title>` + articleMetaTags + `
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+	{"@type": "WebSite", "name": "Blog"},
+	{"@type": "NewsArticle", "headline": "Post",
+	 "author": [{"@type": "Person", "name": "Jane Doe"}, {"@type": "Person", "name": "John Roe"}],
+	 "datePublished": "2024-03-05T10:00:00Z",
+	 "mainEntityOfPage": {"@id": "https://example.com/post"}}
+]}
+</script></head><body><article><p>Hello</p></article></body></html>`
+
+	result, _ := readPage(t, page)
+	if result["author"] != "Jane Doe, John Roe" {
+		t.Errorf("expected JSON-LD authors, got %v", result["author"])
+	}
+	if result["published"] != "2024-03-05T10:00:00Z" {
+		t.Errorf("expected JSON-LD publish date, got %v", result["published"])
+	}
+	if result["canonical"] != "https://example.com/post" {
+		t.Errorf("expected JSON-LD canonical, got %v", result["canonical"])
+	}
+}
+
+func TestWebRead_ArticleMetadata_MetaTags(t *testing.T) {
+	page := `<html><head><title>Post</title>` + articleMetaTags + `</head><body><p>Hello</p></body></html>`
+
+	result, base := readPage(t, page)
+	if result["author"] != "Meta Author" || result["published"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected meta tag values, got author=%v published=%v", result["author"], result["published"])
+	}
+	// The relative canonical link is resolved against the page URL
+	if result["canonical"] != base+"/articles/canonical" {
+		t.Errorf("expected resolved canonical, got %v", result["canonical"])
+	}
+}
+
+func TestWebRead_PaginationRelNext(t *testing.T) {
+	page := `<html><head><link rel="next" href="/articles/1?page=2&amp;sort=new"></head>
+<body><article><p>Part one</p><a href="/articles/0">« Previous</a></article></body></html>`
+
+	result, base := readPage(t, page)
+	if result["next_url"] != base+"/articles/1?page=2&sort=new" {
+		t.Errorf("expected absolute next_url, got %v", result["next_url"])
+	}
+	// No rel="prev", so the anchor text is used
+	if result["prev_url"] != base+"/articles/0" {
+		t.Errorf("expected prev_url from anchor text, got %v", result["prev_url"])
+	}
+}
+
+func TestExtractPagination_AnchorText(t *testing.T) {
+	cases := []struct {
+		html, next, prev string
+	}{
+		{`<a href="3">Older posts</a> <a href="1">Newer posts</a>`, "https://blog.example/p/3", "https://blog.example/p/1"},
+		{`<a href="/p/3"><span>»</span></a>`, "https://blog.example/p/3", ""},
+		{`<a href="/next-steps">Next steps in Go</a> <a rel="nofollow next" href="/p/9">9</a>`, "https://blog.example/p/9", ""},
+		{`<a href="/about">About</a>`, "", ""},
+	}
+	for _, c := range cases {
+		next, prev := extractPagination(c.html, "https://blog.example/p/2")
+		if next != c.next || prev != c.prev {
+			t.Errorf("%s: expected (%q, %q), got (%q, %q)", c.html, c.next, c.prev, next, prev)
+		}
+	}
+}
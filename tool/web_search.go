@@ -1,50 +1,73 @@
 package tool
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/dvictor357/blaze/adapter"
 )
 
+// webSearchSchema is the input schema for web_search
+var webSearchSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"query": map[string]any{
+			"type":        "string",
+			"description": "The search query (e.g., 'golang http server tutorial')",
+		},
+		"max_results": map[string]any{
+			"type":        "integer",
+			"description": "Maximum number of results to return (default: 5, max: 10)",
+		},
+		"mode": map[string]any{
+			"type":        "string",
+			"enum":        []string{"web", "instant"},
+			"description": "'web' for a list of web results (default), 'instant' for a short factual summary of the topic from DuckDuckGo's Instant Answer API. Instant falls back to web when there is no summary",
+		},
+		"region": map[string]any{
+			"type":        "string",
+			"description": "Region and language of results as country-language, e.g. 'us-en', 'de-de', 'fr-fr' (default: worldwide)",
+		},
+		"safe_search": map[string]any{
+			"type":        "string",
+			"enum":        []string{"strict", "moderate", "off"},
+			"description": "Filtering of adult content (default: DuckDuckGo's own, moderate)",
+		},
+		"time_range": map[string]any{
+			"type":        "string",
+			"enum":        []string{"day", "week", "month", "year"},
+			"description": "Only return pages from the past day, week, month or year",
+		},
+	},
+	"required": []string{"query"},
+}
+
 // NewWebSearchTool creates a web search tool that uses DuckDuckGo.
 // No API key required - it scrapes the HTML results page.
 // This gives the AI the ability to search the internet for information.
 func NewWebSearchTool() adapter.Tool {
-	return adapter.NewTool(
+	t := adapter.NewContextTool(
 		"web_search",
 		"Search the web using DuckDuckGo and return a list of results with titles, URLs, and snippets. Use this to find information, documentation, or answers to questions. No API key required.",
-		map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"query": map[string]any{
-					"type":        "string",
-					"description": "The search query (e.g., 'golang http server tutorial')",
-				},
-				"max_results": map[string]any{
-					"type":        "integer",
-					"description": "Maximum number of results to return (default: 5, max: 10)",
-				},
-			},
-			"required": []string{"query"},
-		},
-		func(input json.RawMessage) (any, error) {
+		webSearchSchema,
+		func(ctx context.Context, input json.RawMessage) (any, error) {
 			var data struct {
 				Query      string `json:"query"`
 				MaxResults int    `json:"max_results"`
+				Mode       string `json:"mode"`
+				searchOptions
 			}
-			if err := json.Unmarshal(input, &data); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if err := BindInput(input, &data, webSearchSchema); err != nil {
+				return nil, err
 			}
 
 			if data.Query == "" {
-				return nil, fmt.Errorf("query cannot be empty")
+				return nil, InvalidInput("query cannot be empty")
 			}
 
 			if data.MaxResults <= 0 {
@@ -54,18 +77,37 @@ func NewWebSearchTool() adapter.Tool {
 				data.MaxResults = 10
 			}
 
-			results, err := searchDuckDuckGo(data.Query, data.MaxResults)
+			if data.Mode == "instant" {
+				// Any failure here falls back to the web search below
+				if answer, err := fetchInstantAnswer(ctx, data.Query, data.MaxResults); err == nil && answer.Abstract != "" {
+					return map[string]any{
+						"query":        data.Query,
+						"mode":         "instant",
+						"heading":      answer.Heading,
+						"abstract":     answer.Abstract,
+						"abstract_url": answer.AbstractURL,
+						"source":       answer.Source,
+						"related":      answer.Related,
+					}, nil
+				}
+			}
+
+			results, truncated, err := searchDuckDuckGo(ctx, data.Query, data.MaxResults, data.searchOptions)
 			if err != nil {
 				return nil, err
 			}
 
 			return map[string]any{
-				"query":   data.Query,
-				"results": results,
-				"count":   len(results),
+				"query":     data.Query,
+				"mode":      "web",
+				"results":   results,
+				"count":     len(results),
+				"truncated": truncated,
 			}, nil
 		},
 	)
+	t.SideEffect = true // reaches the network
+	return t
 }
 
 // SearchResult represents a single search result
@@ -75,21 +117,59 @@ type SearchResult struct {
 	Snippet string `json:"snippet"`
 }
 
-// searchDuckDuckGo performs a search using DuckDuckGo's HTML interface
-func searchDuckDuckGo(query string, maxResults int) ([]SearchResult, error) {
-	// Use DuckDuckGo HTML interface (no JavaScript required)
-	searchURL := fmt.Sprintf("https://html.duckduckgo.com/html/?q=%s", url.QueryEscape(query))
+// searchOptions narrows a search. Values outside the documented ones are
+// ignored rather than rejected.
+type searchOptions struct {
+	Region     string `json:"region"`
+	SafeSearch string `json:"safe_search"`
+	TimeRange  string `json:"time_range"`
+}
 
-	client := &http.Client{
-		Timeout: 15 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return nil // Follow redirects
-		},
+var (
+	// searchRegionRe matches DuckDuckGo region codes such as us-en or wt-wt
+	searchRegionRe = regexp.MustCompile(`^[a-z]{2}-[a-z]{2,3}$`)
+
+	// searchSafeSearch maps safe_search to DuckDuckGo's kp parameter
+	searchSafeSearch = map[string]string{"strict": "1", "moderate": "-1", "off": "-2"}
+
+	// searchTimeRange maps time_range to DuckDuckGo's df parameter
+	searchTimeRange = map[string]string{"day": "d", "week": "w", "month": "m", "year": "y"}
+)
+
+// duckDuckGoSearchURL builds the HTML results URL for query, mapping opts to
+// DuckDuckGo's kl (region), kp (safe search) and df (date) parameters
+func duckDuckGoSearchURL(query string, opts searchOptions) string {
+	params := url.Values{"q": {query}}
+	if region := strings.ToLower(strings.TrimSpace(opts.Region)); searchRegionRe.MatchString(region) {
+		params.Set("kl", region)
+	}
+	if kp, ok := searchSafeSearch[strings.ToLower(opts.SafeSearch)]; ok {
+		params.Set("kp", kp)
+	}
+	if df, ok := searchTimeRange[strings.ToLower(opts.TimeRange)]; ok {
+		params.Set("df", df)
 	}
+	return "https://html.duckduckgo.com/html/?" + params.Encode()
+}
+
+// maxSearchBody caps how much of a results page is read
+const maxSearchBody = 500 << 10
+
+// noResultsRe matches DuckDuckGo's marker for a query without results
+var noResultsRe = regexp.MustCompile(`(?i)class="[^"]*\bno-results\b`)
+
+// searchDuckDuckGo performs a search using DuckDuckGo's HTML interface.
+// truncated reports that the page was larger than maxSearchBody and only
+// its start was parsed.
+func searchDuckDuckGo(ctx context.Context, query string, maxResults int, opts searchOptions) (results []SearchResult, truncated bool, err error) {
+	// Use DuckDuckGo HTML interface (no JavaScript required)
+	searchURL := duckDuckGoSearchURL(query, opts)
+
+	client := newWebClient(redirectPolicy(DefaultWebFetchConfig().MaxRedirects, false))
 
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, Internal("failed to create request: %w", err)
 	}
 
 	// Set headers to look like a browser
@@ -99,21 +179,37 @@ func searchDuckDuckGo(query string, maxResults int) ([]SearchResult, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("search request failed: %w", err)
+		return nil, false, Upstream("search request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, false, RateLimited("search failed with status: %d", resp.StatusCode)
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("search failed with status: %d", resp.StatusCode)
+		return nil, false, Upstream("search failed with status: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 500*1024))
+	// Read one byte past the cap to tell a page of exactly the cap apart
+	// from a longer one
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSearchBody+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, false, Upstream("failed to read response: %w", err)
+	}
+	truncated = len(body) > maxSearchBody
+	if truncated {
+		body = body[:maxSearchBody]
 	}
 
-	html := string(body)
+	results, err = parseSearchPage(string(body), maxResults, truncated)
+	return results, truncated, err
+}
 
+// parseSearchPage extracts results from a DuckDuckGo results page. A page
+// that yields nothing is an error, unless DuckDuckGo says there are no
+// results: silently returning nothing would hide a layout change or a page
+// cut off mid-result.
+func parseSearchPage(html string, maxResults int, truncated bool) ([]SearchResult, error) {
 	// Parse DuckDuckGo HTML results
 	results := parseDuckDuckGoResults(html, maxResults)
 
@@ -122,7 +218,16 @@ func searchDuckDuckGo(query string, maxResults int) ([]SearchResult, error) {
 		results = parseDuckDuckGoResultsAlt(html, maxResults)
 	}
 
-	return results, nil
+	if len(results) > 0 {
+		return results, nil
+	}
+	if noResultsRe.MatchString(html) {
+		return []SearchResult{}, nil
+	}
+	if truncated {
+		return nil, Upstream("no results parsed from a page truncated at %dKB; the page may be cut mid-result", maxSearchBody>>10)
+	}
+	return nil, Upstream("no results parsed from %d bytes of HTML; the DuckDuckGo page layout may have changed", len(html))
 }
 
 // parseDuckDuckGoResults extracts search results from DuckDuckGo HTML
@@ -220,6 +325,101 @@ func parseDuckDuckGoResultsAlt(html string, maxResults int) []SearchResult {
 	return results
 }
 
+// instantAnswer is the summary of a topic from DuckDuckGo's Instant Answer API
+type instantAnswer struct {
+	Heading     string
+	Abstract    string
+	AbstractURL string
+	Source      string
+	Related     []SearchResult
+}
+
+// instantAnswerLinkRe matches the link text in a related topic's HTML
+var instantAnswerLinkRe = regexp.MustCompile(`(?is)<a[^>]*>(.*?)</a>`)
+
+// fetchInstantAnswer queries DuckDuckGo's Instant Answer API, which returns
+// JSON and so does not depend on the HTML page layout
+func fetchInstantAnswer(ctx context.Context, query string, maxRelated int) (instantAnswer, error) {
+	params := url.Values{"q": {query}, "format": {"json"}, "no_html": {"1"}, "skip_disambig": {"1"}}
+	client := newWebClient(redirectPolicy(DefaultWebFetchConfig().MaxRedirects, false))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.duckduckgo.com/?"+params.Encode(), nil)
+	if err != nil {
+		return instantAnswer{}, Internal("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return instantAnswer{}, Upstream("instant answer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return instantAnswer{}, Upstream("instant answer failed with status: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSearchBody))
+	if err != nil {
+		return instantAnswer{}, Upstream("failed to read response: %w", err)
+	}
+	return parseInstantAnswer(body, maxRelated)
+}
+
+// parseInstantAnswer decodes an Instant Answer API response, keeping up to
+// maxRelated related topics. Topics nested in groups are flattened.
+func parseInstantAnswer(body []byte, maxRelated int) (instantAnswer, error) {
+	type topic struct {
+		Text     string  `json:"Text"`
+		FirstURL string  `json:"FirstURL"`
+		Result   string  `json:"Result"`
+		Topics   []topic `json:"Topics"`
+	}
+	var resp struct {
+		Heading        string  `json:"Heading"`
+		AbstractText   string  `json:"AbstractText"`
+		AbstractURL    string  `json:"AbstractURL"`
+		AbstractSource string  `json:"AbstractSource"`
+		RelatedTopics  []topic `json:"RelatedTopics"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return instantAnswer{}, Upstream("invalid instant answer response: %w", err)
+	}
+
+	answer := instantAnswer{
+		Heading:     resp.Heading,
+		Abstract:    cleanText(resp.AbstractText),
+		AbstractURL: resp.AbstractURL,
+		Source:      resp.AbstractSource,
+		Related:     []SearchResult{},
+	}
+
+	var add func(topics []topic)
+	add = func(topics []topic) {
+		for _, t := range topics {
+			if len(answer.Related) >= maxRelated {
+				return
+			}
+			if len(t.Topics) > 0 {
+				add(t.Topics)
+				continue
+			}
+			if t.FirstURL == "" {
+				continue
+			}
+			title := t.Text
+			if m := instantAnswerLinkRe.FindStringSubmatch(t.Result); m != nil {
+				title = cleanText(m[1])
+			}
+			answer.Related = append(answer.Related, SearchResult{
+				Title:   title,
+				URL:     t.FirstURL,
+				Snippet: cleanText(t.Text),
+			})
+		}
+	}
+	add(resp.RelatedTopics)
+
+	return answer, nil
+}
+
 // extractActualURL extracts the real URL from DuckDuckGo's redirect URL
 func extractActualURL(ddgURL string) string {
 	// DuckDuckGo uses URLs like: //duckduckgo.com/l/?uddg=https%3A%2F%2Fexample.com...
@@ -257,13 +457,7 @@ func cleanText(s string) string {
 	s = regexp.MustCompile(`<[^>]+>`).ReplaceAllString(s, "")
 
 	// Decode HTML entities
-	s = strings.ReplaceAll(s, "&nbsp;", " ")
-	s = strings.ReplaceAll(s, "&amp;", "&")
-	s = strings.ReplaceAll(s, "&lt;", "<")
-	s = strings.ReplaceAll(s, "&gt;", ">")
-	s = strings.ReplaceAll(s, "&quot;", "\"")
-	s = strings.ReplaceAll(s, "&#39;", "'")
-	s = strings.ReplaceAll(s, "&apos;", "'")
+	s = decodeEntities(s)
 
 	// Clean whitespace
 	s = regexp.MustCompile(`\s+`).ReplaceAllString(s, " ")
@@ -0,0 +1,123 @@
+package tool
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCleanText_DecodesEntities(t *testing.T) {
+	got := cleanText(`<b>Go</b> &copy; Google &mdash; Don&#x2019;t&nbsp;panic &#169;`)
+
+	want := "Go © Google — Don’t panic ©"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestParseSearchPage(t *testing.T) {
+	page := `<div class="result"><a class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2F&amp;rut=x">The Go <b>Programming</b> Language</a>
+<a class="result__snippet" href="#">Go is an open source language.</a></div>`
+
+	results, err := parseSearchPage(page, 5, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "https://go.dev/" || results[0].Title != "The Go Programming Language" {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	// DuckDuckGo's own "no results" page is an empty success
+	results, err = parseSearchPage(`<div class="no-results">No results.</div>`, 5, false)
+	if err != nil || results == nil || len(results) != 0 {
+		t.Errorf("expected empty results, got %v, %v", results, err)
+	}
+}
+
+func TestParseSearchPage_NothingParsed(t *testing.T) {
+	// A page cut off in the middle of the first result
+	truncated := `<html><body><div class="result"><a class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo`
+	_, err := parseSearchPage(truncated, 5, true)
+	if err == nil || !strings.Contains(err.Error(), "truncated at 500KB") {
+		t.Errorf("expected truncation error, got %v", err)
+	}
+
+	_, err = parseSearchPage("<html><body>garbage</body></html>", 5, false)
+	if err == nil || !strings.Contains(err.Error(), "layout may have changed") {
+		t.Errorf("expected layout error, got %v", err)
+	}
+}
+
+func TestDuckDuckGoSearchURL(t *testing.T) {
+	tests := []struct {
+		name string
+		opts searchOptions
+		want string
+	}{
+		{"defaults", searchOptions{}, "https://html.duckduckgo.com/html/?q=go+generics"},
+		{
+			"all options",
+			searchOptions{Region: "DE-de", SafeSearch: "strict", TimeRange: "week"},
+			"https://html.duckduckgo.com/html/?df=w&kl=de-de&kp=1&q=go+generics",
+		},
+		{"safe search off", searchOptions{SafeSearch: "off", TimeRange: "year"}, "https://html.duckduckgo.com/html/?df=y&kp=-2&q=go+generics"},
+		{
+			"unknown values are ignored",
+			searchOptions{Region: "germany", SafeSearch: "maybe", TimeRange: "decade"},
+			"https://html.duckduckgo.com/html/?q=go+generics",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duckDuckGoSearchURL("go generics", tt.opts); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseInstantAnswer(t *testing.T) {
+	body, err := os.ReadFile("testdata/ddg_instant_answer.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answer, err := parseInstantAnswer(body, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.Heading != "Go (programming language)" || answer.Source != "Wikipedia" {
+		t.Errorf("unexpected heading or source %+v", answer)
+	}
+	if !strings.HasPrefix(answer.Abstract, "Go is a high-level general purpose programming language") {
+		t.Errorf("unexpected abstract %q", answer.Abstract)
+	}
+	if answer.AbstractURL != "https://en.wikipedia.org/wiki/Go_(programming_language)" {
+		t.Errorf("unexpected abstract URL %q", answer.AbstractURL)
+	}
+
+	// Grouped topics are flattened, up to the limit
+	want := []SearchResult{
+		{
+			Title:   "Robert Griesemer",
+			URL:     "https://duckduckgo.com/Robert_Griesemer",
+			Snippet: "Robert Griesemer - Robert Griesemer is a Swiss computer scientist. He is best known for his work on the Go programming language.",
+		},
+		{
+			Title:   "Rust (programming language)",
+			URL:     "https://duckduckgo.com/Rust_(programming_language)",
+			Snippet: "Rust (programming language) - Rust is a general-purpose programming language emphasizing performance, type safety, and concurrency.",
+		},
+	}
+	if !reflect.DeepEqual(answer.Related, want) {
+		t.Errorf("expected %+v, got %+v", want, answer.Related)
+	}
+
+	// No abstract means the tool falls back to web results
+	empty, err := parseInstantAnswer([]byte(`{"AbstractText": "", "RelatedTopics": []}`), 5)
+	if err != nil || empty.Abstract != "" || len(empty.Related) != 0 {
+		t.Errorf("expected an empty answer, got %+v, %v", empty, err)
+	}
+}